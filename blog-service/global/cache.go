@@ -0,0 +1,7 @@
+package global
+
+import "blog-service/pkg/cache"
+
+var (
+	Cache cache.Cache
+)
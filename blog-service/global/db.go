@@ -1,6 +1,6 @@
 package global
 
-import "github.com/jinzhu/gorm"
+import "gorm.io/gorm"
 
 var (
 	DBEngine *gorm.DB
@@ -0,0 +1,4 @@
+package global
+
+// 服务是否可以接收流量, 1为就绪, 通过sync/atomic读写
+var ServerReady int32
@@ -6,8 +6,16 @@ import (
 )
 
 var (
-	ServerSetting   *setting.ServerSettingS
-	AppSetting      *setting.AppSettingS
-	DatabaseSetting *setting.DatabaseSettingS
-	Logger          *logger.Logger
+	ServerSetting    *setting.ServerSettingS
+	AppSetting       *setting.AppSettingS
+	DatabaseSetting  *setting.DatabaseSettingS
+	JWTSetting       *setting.JWTSettingS
+	EmailSetting     *setting.EmailSettingS
+	TracerSetting    *setting.TracerSettingS
+	SearchSetting    *setting.SearchSettingS
+	SchedulerSetting *setting.SchedulerSettingS
+	SiteSetting      *setting.SiteSettingS
+	CacheSetting     *setting.CacheSettingS
+	LimiterSetting   *setting.LimiterSettingS
+	Logger           *logger.Logger
 )
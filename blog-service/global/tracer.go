@@ -0,0 +1,11 @@
+package global
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	TracerProvider *sdktrace.TracerProvider
+	Tracer         trace.Tracer
+)
@@ -11,10 +11,12 @@ type Article struct {
 	Title         string `json:"title"`
 	Desc          string `json:"desc"`
 	Content       string `json:"content"`
+	ContentHTML   string `json:"content_html"`
 	CoverImageUrl string `json:"cover_image_url"`
 	CreatedBy     string `json:"created_by"`
 	ModifiedBy    string `json:"modified_by"`
 	State         uint8  `json:"state"`
+	PublishAt     uint32 `json:"publish_at"`
 }
 
 // 创建文章
@@ -23,8 +25,10 @@ func (d *Dao) CreateArticle(param *Article) (*model.Article, error) {
 		Title:         param.Title,
 		Desc:          param.Desc,
 		Content:       param.Content,
+		ContentHTML:   param.ContentHTML,
 		CoverImageUrl: param.CoverImageUrl,
 		State:         param.State,
+		PublishAt:     param.PublishAt,
 		Model:         &model.Model{CreatedBy: param.CreatedBy},
 	}
 	return article.Create(d.engine)
@@ -49,16 +53,62 @@ func (d *Dao) UpdateArticle(param *Article) error {
 	if param.Content != "" {
 		values["content"] = param.Content
 	}
+	if param.ContentHTML != "" {
+		values["content_html"] = param.ContentHTML
+	}
+	if param.PublishAt != 0 {
+		values["publish_at"] = param.PublishAt
+	}
 
 	return article.Update(d.engine, values)
 }
 
+// 缓存文章渲染后的HTML
+func (d *Dao) UpdateArticleContentHTML(id uint32, contentHTML string) error {
+	article := model.Article{Model: &model.Model{ID: id}}
+	return article.UpdateColumns(d.engine, map[string]interface{}{"content_html": contentHTML})
+}
+
 // 获取文章
 func (d *Dao) GetArticle(id uint32, state uint8) (model.Article, error) {
 	article := model.Article{Model: &model.Model{ID: id}, State: state}
 	return article.Get(d.engine)
 }
 
+// 通过ID获取文章, 不限制状态
+func (d *Dao) GetArticleByID(id uint32) (model.Article, error) {
+	article := model.Article{Model: &model.Model{ID: id}}
+	return article.GetByID(d.engine)
+}
+
+func (d *Dao) GetArticleByTitle(title string) (model.Article, error) {
+	article := model.Article{Title: title}
+	return article.GetByTitle(d.engine)
+}
+
+// 锁定文章所在的行, 需要在事务中调用
+func (d *Dao) LockArticle(id uint32) (model.Article, error) {
+	article := model.Article{Model: &model.Model{ID: id}}
+	return article.LockByID(d.engine)
+}
+
+// 锁定已到发布时间的定时发布文章, 需要在事务中调用
+func (d *Dao) LockDueScheduledArticles(publishAt uint32, limit int) ([]*model.Article, error) {
+	article := model.Article{}
+	return article.LockDueScheduled(d.engine, publishAt, limit)
+}
+
+func (d *Dao) GetArticleListByIDs(ids []uint32, state uint8) ([]*model.Article, error) {
+	article := model.Article{State: state}
+	return article.ListByIDs(d.engine, ids)
+}
+
+// 分页获取全部状态的文章
+func (d *Dao) GetArticleList(page, pageSize int) ([]*model.Article, error) {
+	article := model.Article{}
+	return article.List(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
+
 // 删除文章
 func (d *Dao) DeleteArticle(id uint32) error {
 	article := model.Article{Model: &model.Model{ID: id}}
@@ -66,12 +116,29 @@ func (d *Dao) DeleteArticle(id uint32) error {
 }
 
 // 通过TagID获取文章总数
-func (d *Dao) CountArticleListByTagID(id uint32, state uint8) (int, error) {
-	article := model.Article{State: state}
-	return article.CountByTagID(d.engine, id)
+func (d *Dao) CountArticleListByTagID(id uint32, states []uint8) (int, error) {
+	article := model.Article{}
+	return article.CountByTagID(d.engine, id, states)
 }
 
-func (d *Dao) GetArticleListByTagID(id uint32, state uint8, page, pageSize int) ([]*model.ArticleRow, error) {
-	article := model.Article{State: state}
-	return article.ListByTagID(d.engine, id, app.GetPageOffset(page, pageSize), pageSize)
+func (d *Dao) GetArticleListByTagID(id uint32, states []uint8, page, pageSize int) ([]*model.ArticleRow, error) {
+	article := model.Article{}
+	return article.ListByTagID(d.engine, id, states, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+// 通过TagID按游标获取文章列表, cursor为上一页最后一篇文章的ID
+func (d *Dao) GetArticleListByTagIDBefore(id uint32, states []uint8, cursor uint32, pageSize int) ([]*model.ArticleRow, error) {
+	article := model.Article{}
+	return article.ListByTagIDBefore(d.engine, id, states, cursor, pageSize)
+}
+
+// 获取最新发布的文章, id为0时不限制标签
+func (d *Dao) GetLatestArticleList(tagID uint32, states []uint8, limit int) ([]*model.Article, error) {
+	article := model.Article{}
+	return article.ListLatest(d.engine, tagID, states, limit)
+}
+
+// 文章、标签和文章标签关联中最近一次修改或删除的时间, 文章取消发布或删除后也会变化
+func (d *Dao) GetContentLastChangedOn() (uint32, error) {
+	return model.LastChangedOn(d.engine, model.Article{}.TableName(), model.Tag{}.TableName(), model.ArticleTag{}.TableName())
 }
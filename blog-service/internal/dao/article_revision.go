@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+// 以文章当前的内容创建一个新版本
+func (d *Dao) CreateArticleRevision(article *model.Article, revision uint32, modifiedBy string) error {
+	articleRevision := model.ArticleRevision{
+		Model:         &model.Model{CreatedBy: modifiedBy, ModifiedBy: modifiedBy},
+		ArticleID:     article.ID,
+		Revision:      revision,
+		Title:         article.Title,
+		Desc:          article.Desc,
+		Content:       article.Content,
+		CoverImageUrl: article.CoverImageUrl,
+		State:         article.State,
+	}
+	return articleRevision.Create(d.engine)
+}
+
+func (d *Dao) GetArticleRevision(articleID, revision uint32) (model.ArticleRevision, error) {
+	articleRevision := model.ArticleRevision{ArticleID: articleID, Revision: revision}
+	return articleRevision.Get(d.engine)
+}
+
+func (d *Dao) GetArticleMaxRevision(articleID uint32) (uint32, error) {
+	articleRevision := model.ArticleRevision{ArticleID: articleID}
+	return articleRevision.MaxRevision(d.engine)
+}
+
+func (d *Dao) CountArticleRevision(articleID uint32) (int, error) {
+	articleRevision := model.ArticleRevision{ArticleID: articleID}
+	return articleRevision.CountByAID(d.engine)
+}
+
+func (d *Dao) GetArticleRevisionList(articleID uint32, page, pageSize int) ([]*model.ArticleRevision, error) {
+	articleRevision := model.ArticleRevision{ArticleID: articleID}
+	return articleRevision.ListByAID(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
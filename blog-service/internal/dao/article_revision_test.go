@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"testing"
+
+	"blog-service/internal/model"
+)
+
+func TestArticleRevision(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	article := createTestArticle(t, d, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+
+	max, err := d.GetArticleMaxRevision(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleMaxRevision err: %v", err)
+	}
+	if max != 0 {
+		t.Errorf("GetArticleMaxRevision = %d without revisions, want 0", max)
+	}
+
+	for revision := uint32(1); revision <= 3; revision++ {
+		if err := d.CreateArticleRevision(article, revision, "editor"); err != nil {
+			t.Fatalf("CreateArticleRevision(%d) err: %v", revision, err)
+		}
+	}
+
+	max, err = d.GetArticleMaxRevision(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleMaxRevision err: %v", err)
+	}
+	if max != 3 {
+		t.Errorf("GetArticleMaxRevision = %d, want 3", max)
+	}
+
+	count, err := d.CountArticleRevision(article.ID)
+	if err != nil {
+		t.Fatalf("CountArticleRevision err: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("CountArticleRevision = %d, want 3", count)
+	}
+
+	revisions, err := d.GetArticleRevisionList(article.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("GetArticleRevisionList err: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 3 || revisions[1].Revision != 2 {
+		t.Errorf("GetArticleRevisionList = %+v, want revisions [3, 2]", revisions)
+	}
+
+	revision, err := d.GetArticleRevision(article.ID, 2)
+	if err != nil {
+		t.Fatalf("GetArticleRevision err: %v", err)
+	}
+	if revision.Model == nil || revision.Title != article.Title || revision.CreatedBy != "editor" {
+		t.Errorf("GetArticleRevision = %+v, want a snapshot of %q by editor", revision, article.Title)
+	}
+}
@@ -2,9 +2,9 @@ package dao
 
 import "blog-service/internal/model"
 
-func (d *Dao) GetArticleTagByAID(articleID uint32) (model.ArticleTag, error) {
+func (d *Dao) GetArticleTagListByAID(articleID uint32) ([]*model.ArticleTag, error) {
 	articleTag := model.ArticleTag{ArticleID: articleID}
-	return articleTag.GetByAID(d.engine)
+	return articleTag.ListByAID(d.engine)
 }
 
 func (d *Dao) GetArticleTagListByTID(tagID uint32) ([]*model.ArticleTag, error) {
@@ -28,17 +28,14 @@ func (d *Dao) CreateArticleTag(articleID, tagID uint32, createdBy string) error
 	return articleTag.Create(d.engine)
 }
 
-func (d *Dao) UpdateArticleTag(articleID, tagID uint32, modifiedBy string) error {
+// 删除文章与指定标签的关联
+func (d *Dao) DeleteArticleTagByTIDs(articleID uint32, tagIDs []uint32) error {
 	articleTag := model.ArticleTag{ArticleID: articleID}
-	values := map[string]interface{}{
-		"article_id":  articleID,
-		"tag_id":      tagID,
-		"modified_by": modifiedBy,
-	}
-	return articleTag.UpdateOne(d.engine, values)
+	return articleTag.DeleteByTIDs(d.engine, tagIDs)
 }
 
+// 删除文章的全部标签关联
 func (d *Dao) DeleteArticleTag(articleID uint32) error {
 	articleTag := model.ArticleTag{ArticleID: articleID}
-	return articleTag.DeleteOne(d.engine)
+	return articleTag.DeleteByAID(d.engine)
 }
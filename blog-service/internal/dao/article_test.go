@@ -0,0 +1,307 @@
+package dao
+
+import (
+	"fmt"
+	"testing"
+
+	"blog-service/internal/model"
+)
+
+// 创建文章并关联到指定标签
+func createTestArticle(t *testing.T, d *Dao, title string, state uint8, publishAt uint32, tagIDs ...uint32) *model.Article {
+	t.Helper()
+
+	article, err := d.CreateArticle(&Article{
+		Title:         title,
+		Desc:          title + " desc",
+		Content:       "# " + title,
+		CoverImageUrl: "https://example.com/cover.png",
+		CreatedBy:     "tester",
+		State:         state,
+		PublishAt:     publishAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateArticle(%s) err: %v", title, err)
+	}
+	for _, tagID := range tagIDs {
+		if err := d.CreateArticleTag(article.ID, tagID, "tester"); err != nil {
+			t.Fatalf("CreateArticleTag(%d, %d) err: %v", article.ID, tagID, err)
+		}
+	}
+	return article
+}
+
+func TestArticleCRUD(t *testing.T) {
+	d, db := newTestDao(t)
+
+	article := createTestArticle(t, d, "Hello", model.ARTICLE_STATE_DRAFT, 0)
+	if article.ID == 0 || article.CreatedOn == 0 {
+		t.Fatalf("CreateArticle = %+v, want id and created_on set", article)
+	}
+
+	got, err := d.GetArticle(article.ID, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		t.Fatalf("GetArticle err: %v", err)
+	}
+	if got.Model != nil {
+		t.Errorf("GetArticle with published state = %+v, want not found for a draft", got)
+	}
+
+	err = d.UpdateArticle(&Article{
+		ID:         article.ID,
+		Title:      "Hello, world",
+		ModifiedBy: "editor",
+		State:      model.ARTICLE_STATE_PUBLISHED,
+		PublishAt:  1000,
+	})
+	if err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	got, err = d.GetArticle(article.ID, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		t.Fatalf("GetArticle err: %v", err)
+	}
+	if got.Model == nil || got.Title != "Hello, world" || got.Desc != "Hello desc" || got.PublishAt != 1000 {
+		t.Fatalf("GetArticle after update = %+v, want new title with the old desc kept", got)
+	}
+
+	locked, err := d.LockArticle(article.ID)
+	if err != nil {
+		t.Fatalf("LockArticle err: %v", err)
+	}
+	if locked.Model == nil || locked.ID != article.ID {
+		t.Errorf("LockArticle = %+v, want article %d", locked, article.ID)
+	}
+
+	if err := d.DeleteArticle(article.ID); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+	got, err = d.GetArticleByID(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleByID err: %v", err)
+	}
+	if got.Model != nil {
+		t.Errorf("GetArticleByID after delete = %+v, want not found", got)
+	}
+
+	row := rawRow(t, db, model.Article{}.TableName(), article.ID)
+	if row.IsDel != 1 || row.DeletedOn == 0 {
+		t.Errorf("soft deleted row is_del = %d, deleted_on = %d, want 1 and non-zero", row.IsDel, row.DeletedOn)
+	}
+}
+
+func TestUpdateArticleContentHTMLKeepsModifiedOn(t *testing.T) {
+	d, db := newTestDao(t)
+
+	article := createTestArticle(t, d, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+	err := db.Table(model.Article{}.TableName()).Where("id = ?", article.ID).UpdateColumn("modified_on", 1).Error
+	if err != nil {
+		t.Fatalf("reset modified_on err: %v", err)
+	}
+
+	if err := d.UpdateArticleContentHTML(article.ID, "<h1>Hello</h1>"); err != nil {
+		t.Fatalf("UpdateArticleContentHTML err: %v", err)
+	}
+
+	got, err := d.GetArticleByID(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleByID err: %v", err)
+	}
+	if got.ContentHTML != "<h1>Hello</h1>" {
+		t.Errorf("content_html = %q, want rendered html", got.ContentHTML)
+	}
+	if got.ModifiedOn != 1 {
+		t.Errorf("modified_on = %d, want unchanged 1", got.ModifiedOn)
+	}
+}
+
+func TestArticleListByTagID(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	if _, err := d.CreateTag("Go", model.STATE_OPEN, "tester"); err != nil {
+		t.Fatalf("CreateTag err: %v", err)
+	}
+	if _, err := d.CreateTag("Rust", model.STATE_OPEN, "tester"); err != nil {
+		t.Fatalf("CreateTag err: %v", err)
+	}
+
+	createTestArticle(t, d, "draft", model.ARTICLE_STATE_DRAFT, 0, 1)
+	createTestArticle(t, d, "first", model.ARTICLE_STATE_PUBLISHED, 100, 1)
+	createTestArticle(t, d, "second", model.ARTICLE_STATE_PUBLISHED, 200, 1, 2)
+	createTestArticle(t, d, "rust only", model.ARTICLE_STATE_PUBLISHED, 300, 2)
+	deleted := createTestArticle(t, d, "deleted", model.ARTICLE_STATE_PUBLISHED, 400, 1)
+	if err := d.DeleteArticle(deleted.ID); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		states []uint8
+		want   int
+	}{
+		{name: "published", states: []uint8{model.ARTICLE_STATE_PUBLISHED}, want: 2},
+		{name: "draft", states: []uint8{model.ARTICLE_STATE_DRAFT}, want: 1},
+		{name: "draft and published", states: []uint8{model.ARTICLE_STATE_DRAFT, model.ARTICLE_STATE_PUBLISHED}, want: 3},
+		{name: "archived", states: []uint8{model.ARTICLE_STATE_ARCHIVED}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := d.CountArticleListByTagID(1, tt.states)
+			if err != nil {
+				t.Fatalf("CountArticleListByTagID err: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("CountArticleListByTagID = %d, want %d", count, tt.want)
+			}
+
+			rows, err := d.GetArticleListByTagID(1, tt.states, 1, 10)
+			if err != nil {
+				t.Fatalf("GetArticleListByTagID err: %v", err)
+			}
+			if len(rows) != tt.want {
+				t.Errorf("GetArticleListByTagID len = %d, want %d", len(rows), tt.want)
+			}
+			for _, row := range rows {
+				if row.TagID != 1 || row.TagName != "Go" {
+					t.Errorf("row tag = (%d, %s), want (1, Go)", row.TagID, row.TagName)
+				}
+			}
+		})
+	}
+
+	latest, err := d.GetLatestArticleList(0, []uint8{model.ARTICLE_STATE_PUBLISHED}, 2)
+	if err != nil {
+		t.Fatalf("GetLatestArticleList err: %v", err)
+	}
+	if len(latest) != 2 || latest[0].Title != "rust only" || latest[1].Title != "second" {
+		t.Errorf("GetLatestArticleList = %v, want [rust only, second]", articleTitles(latest))
+	}
+
+	latest, err = d.GetLatestArticleList(1, []uint8{model.ARTICLE_STATE_PUBLISHED}, 10)
+	if err != nil {
+		t.Fatalf("GetLatestArticleList by tag err: %v", err)
+	}
+	if len(latest) != 2 || latest[0].Title != "second" || latest[1].Title != "first" {
+		t.Errorf("GetLatestArticleList by tag = %v, want [second, first]", articleTitles(latest))
+	}
+}
+
+func TestArticleListByTagIDBefore(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	for _, name := range []string{"Go", "Rust"} {
+		if _, err := d.CreateTag(name, model.STATE_OPEN, "tester"); err != nil {
+			t.Fatalf("CreateTag err: %v", err)
+		}
+	}
+	for i := 1; i <= 5; i++ {
+		createTestArticle(t, d, fmt.Sprintf("article %d", i), model.ARTICLE_STATE_PUBLISHED, 0, 1)
+	}
+	createTestArticle(t, d, "other tag", model.ARTICLE_STATE_PUBLISHED, 0, 2)
+
+	states := []uint8{model.ARTICLE_STATE_PUBLISHED}
+	var got []uint32
+	var cursor uint32
+	for {
+		rows, err := d.GetArticleListByTagIDBefore(1, states, cursor, 2)
+		if err != nil {
+			t.Fatalf("GetArticleListByTagIDBefore(%d) err: %v", cursor, err)
+		}
+		for _, row := range rows {
+			got = append(got, row.ArticleID)
+		}
+		if len(rows) < 2 {
+			break
+		}
+		cursor = rows[len(rows)-1].ArticleID
+	}
+
+	want := []uint32{5, 4, 3, 2, 1}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("article ids = %v, want %v", got, want)
+	}
+}
+
+func TestArticleTag(t *testing.T) {
+	d, db := newTestDao(t)
+
+	article := createTestArticle(t, d, "Hello", model.ARTICLE_STATE_PUBLISHED, 0, 1, 2, 3)
+
+	if err := d.DeleteArticleTagByTIDs(article.ID, []uint32{2}); err != nil {
+		t.Fatalf("DeleteArticleTagByTIDs err: %v", err)
+	}
+	articleTags, err := d.GetArticleTagListByAID(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleTagListByAID err: %v", err)
+	}
+	if len(articleTags) != 2 {
+		t.Fatalf("GetArticleTagListByAID len = %d, want 2", len(articleTags))
+	}
+
+	articleTags, err = d.GetArticleTagListByTID(3)
+	if err != nil {
+		t.Fatalf("GetArticleTagListByTID err: %v", err)
+	}
+	if len(articleTags) != 1 || articleTags[0].ArticleID != article.ID {
+		t.Errorf("GetArticleTagListByTID = %+v, want article %d", articleTags, article.ID)
+	}
+
+	if err := d.DeleteArticleTag(article.ID); err != nil {
+		t.Fatalf("DeleteArticleTag err: %v", err)
+	}
+	articleTags, err = d.GetArticleTagListByAIDs([]uint32{article.ID})
+	if err != nil {
+		t.Fatalf("GetArticleTagListByAIDs err: %v", err)
+	}
+	if len(articleTags) != 0 {
+		t.Errorf("GetArticleTagListByAIDs len = %d after delete, want 0", len(articleTags))
+	}
+
+	var deleted int64
+	err = db.Table(model.ArticleTag{}.TableName()).Where("article_id = ? AND is_del = ?", article.ID, 1).Count(&deleted).Error
+	if err != nil {
+		t.Fatalf("count deleted article tags err: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("soft deleted article tags = %d, want 3", deleted)
+	}
+}
+
+func TestLockDueScheduledArticles(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	createTestArticle(t, d, "later", model.ARTICLE_STATE_SCHEDULED, 300)
+	createTestArticle(t, d, "due second", model.ARTICLE_STATE_SCHEDULED, 200)
+	createTestArticle(t, d, "due first", model.ARTICLE_STATE_SCHEDULED, 100)
+	createTestArticle(t, d, "draft", model.ARTICLE_STATE_DRAFT, 100)
+
+	err := d.Transaction(func(tx *Dao) error {
+		articles, err := tx.LockDueScheduledArticles(250, 10)
+		if err != nil {
+			return err
+		}
+		if len(articles) != 2 || articles[0].Title != "due first" || articles[1].Title != "due second" {
+			t.Errorf("LockDueScheduledArticles = %v, want [due first, due second]", articleTitles(articles))
+		}
+
+		articles, err = tx.LockDueScheduledArticles(250, 1)
+		if err != nil {
+			return err
+		}
+		if len(articles) != 1 {
+			t.Errorf("LockDueScheduledArticles with limit 1 len = %d, want 1", len(articles))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Transaction err: %v", err)
+	}
+}
+
+func articleTitles(articles []*model.Article) []string {
+	titles := make([]string, 0, len(articles))
+	for _, article := range articles {
+		titles = append(titles, article.Title)
+	}
+	return titles
+}
@@ -0,0 +1,8 @@
+package dao
+
+import "blog-service/internal/model"
+
+func (d *Dao) GetAuth(appKey string) (model.Auth, error) {
+	auth := model.Auth{AppKey: appKey}
+	return auth.Get(d.engine)
+}
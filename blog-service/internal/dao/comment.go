@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+)
+
+func (d *Dao) GetComment(id, articleID uint32) (model.Comment, error) {
+	comment := model.Comment{Model: &model.Model{ID: id}, ArticleID: articleID}
+	return comment.Get(d.engine)
+}
+
+func (d *Dao) CountCommentRoots(articleID uint32, state uint8) (int, error) {
+	comment := model.Comment{ArticleID: articleID, State: state}
+	return comment.CountRoots(d.engine)
+}
+
+func (d *Dao) GetCommentRoots(articleID uint32, state uint8, page, pageSize int) ([]*model.Comment, error) {
+	comment := model.Comment{ArticleID: articleID, State: state}
+	return comment.ListRoots(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+func (d *Dao) GetCommentReplies(articleID uint32, state uint8, parentIDs []uint32, limit int) ([]*model.Comment, error) {
+	comment := model.Comment{ArticleID: articleID, State: state}
+	return comment.ListReplies(d.engine, parentIDs, limit)
+}
+
+func (d *Dao) GetCommentReplyIDs(articleID uint32, parentIDs []uint32) ([]uint32, error) {
+	comment := model.Comment{ArticleID: articleID}
+	return comment.ListReplyIDs(d.engine, parentIDs)
+}
+
+func (d *Dao) CreateComment(articleID, parentID uint32, content string, state uint8, createdBy string) (*model.Comment, error) {
+	comment := model.Comment{
+		Model:     &model.Model{CreatedBy: createdBy},
+		ArticleID: articleID,
+		ParentID:  parentID,
+		Content:   content,
+		State:     state,
+	}
+	return comment.Create(d.engine)
+}
+
+func (d *Dao) UpdateCommentState(id, articleID uint32, state uint8, modifiedBy string) error {
+	comment := model.Comment{Model: &model.Model{ID: id}, ArticleID: articleID}
+	values := map[string]interface{}{
+		"state":       state,
+		"modified_by": modifiedBy,
+	}
+	return comment.Update(d.engine, values)
+}
+
+func (d *Dao) DeleteComments(ids []uint32, articleID uint32) error {
+	comment := model.Comment{ArticleID: articleID}
+	return comment.DeleteByIDs(d.engine, ids)
+}
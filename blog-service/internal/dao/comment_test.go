@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"testing"
+
+	"blog-service/internal/model"
+)
+
+func TestComment(t *testing.T) {
+	d, db := newTestDao(t)
+
+	article := createTestArticle(t, d, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+
+	root, err := d.CreateComment(article.ID, 0, "first", model.COMMENT_STATE_PENDING, "reader")
+	if err != nil {
+		t.Fatalf("CreateComment err: %v", err)
+	}
+	reply, err := d.CreateComment(article.ID, root.ID, "reply", model.COMMENT_STATE_APPROVED, "author")
+	if err != nil {
+		t.Fatalf("CreateComment reply err: %v", err)
+	}
+
+	count, err := d.CountCommentRoots(article.ID, model.COMMENT_STATE_APPROVED)
+	if err != nil {
+		t.Fatalf("CountCommentRoots err: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("CountCommentRoots = %d before approval, want 0", count)
+	}
+
+	if err := d.UpdateCommentState(root.ID, article.ID, model.COMMENT_STATE_APPROVED, "admin"); err != nil {
+		t.Fatalf("UpdateCommentState err: %v", err)
+	}
+	roots, err := d.GetCommentRoots(article.ID, model.COMMENT_STATE_APPROVED, 1, 10)
+	if err != nil {
+		t.Fatalf("GetCommentRoots err: %v", err)
+	}
+	if len(roots) != 1 || roots[0].ID != root.ID || roots[0].ModifiedBy != "admin" {
+		t.Errorf("GetCommentRoots = %+v, want the approved root comment", roots)
+	}
+
+	replies, err := d.GetCommentReplies(article.ID, model.COMMENT_STATE_APPROVED, []uint32{root.ID}, 0)
+	if err != nil {
+		t.Fatalf("GetCommentReplies err: %v", err)
+	}
+	if len(replies) != 1 || replies[0].ParentID != root.ID {
+		t.Errorf("GetCommentReplies = %+v, want a reply to %d", replies, root.ID)
+	}
+
+	if err := d.DeleteComments([]uint32{reply.ID}, article.ID); err != nil {
+		t.Fatalf("DeleteComments err: %v", err)
+	}
+	got, err := d.GetComment(reply.ID, article.ID)
+	if err != nil {
+		t.Fatalf("GetComment err: %v", err)
+	}
+	if got.Model != nil {
+		t.Errorf("GetComment after delete = %+v, want not found", got)
+	}
+
+	row := rawRow(t, db, model.Comment{}.TableName(), reply.ID)
+	if row.IsDel != 1 || row.DeletedOn == 0 {
+		t.Errorf("soft deleted row is_del = %d, deleted_on = %d, want 1 and non-zero", row.IsDel, row.DeletedOn)
+	}
+}
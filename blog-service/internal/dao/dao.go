@@ -1,6 +1,6 @@
 package dao
 
-import "github.com/jinzhu/gorm"
+import "gorm.io/gorm"
 
 type Dao struct {
 	engine *gorm.DB
@@ -9,3 +9,10 @@ type Dao struct {
 func New(engine *gorm.DB) *Dao {
 	return &Dao{engine: engine}
 }
+
+// 在同一个事务中执行fn, fn返回错误时回滚
+func (d *Dao) Transaction(fn func(tx *Dao) error) error {
+	return d.engine.Transaction(func(tx *gorm.DB) error {
+		return fn(New(tx))
+	})
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"blog-service/internal/migration"
+	"blog-service/internal/model"
+	"blog-service/pkg/setting"
+
+	"gorm.io/gorm"
+)
+
+// 每个测试使用独立的SQLite内存数据库, 并执行全部迁移
+func newTestDao(t *testing.T) (*Dao, *gorm.DB) {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := model.NewDBEngine(&setting.DatabaseSettingS{
+		DBType:       "sqlite",
+		DBName:       fmt.Sprintf("file:%s?mode=memory&cache=shared", name),
+		MaxIdleConns: 1,
+		MaxOpenConns: 1,
+	})
+	if err != nil {
+		t.Fatalf("model.NewDBEngine err: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB err: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := migration.Up(db); err != nil {
+		t.Fatalf("migration.Up err: %v", err)
+	}
+
+	return New(db), db
+}
+
+// 查询被软删除的行, 绕过is_del条件
+func rawRow(t *testing.T, db *gorm.DB, table string, id uint32) model.Model {
+	t.Helper()
+
+	var row model.Model
+	err := db.Table(table).Select("id, created_on, modified_on, deleted_on, is_del").
+		Where("id = ?", id).Take(&row).Error
+	if err != nil {
+		t.Fatalf("query %s %d err: %v", table, id, err)
+	}
+	return row
+}
+
+func TestTransactionRollback(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	errAbort := errors.New("abort")
+	err := d.Transaction(func(tx *Dao) error {
+		if _, err := tx.CreateTag("Go", model.STATE_OPEN, "tester"); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("Transaction err = %v, want %v", err, errAbort)
+	}
+
+	count, err := d.CountTag("Go", model.STATE_OPEN)
+	if err != nil {
+		t.Fatalf("CountTag err: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("CountTag = %d after rollback, want 0", count)
+	}
+}
+
+func TestTransactionCommit(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	err := d.Transaction(func(tx *Dao) error {
+		_, err := tx.CreateTag("Go", model.STATE_OPEN, "tester")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Transaction err: %v", err)
+	}
+
+	count, err := d.CountTag("Go", model.STATE_OPEN)
+	if err != nil {
+		t.Fatalf("CountTag err: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountTag = %d after commit, want 1", count)
+	}
+}
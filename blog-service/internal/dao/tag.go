@@ -21,7 +21,23 @@ func (d *Dao) GetTagList(name string, state uint8, page, pageSize int) ([]*model
 	return tag.List(d.engine, pageOffset, pageSize)
 }
 
-func (d *Dao) CreateTag(name string, state uint8, createdBy string) error {
+// 获取全部状态的标签
+func (d *Dao) GetAllTagList(page, pageSize int) ([]*model.Tag, error) {
+	tag := model.Tag{}
+	return tag.ListAll(d.engine, app.GetPageOffset(page, pageSize), pageSize)
+}
+
+func (d *Dao) GetTagByName(name string) (model.Tag, error) {
+	tag := model.Tag{Name: name}
+	return tag.GetByName(d.engine)
+}
+
+func (d *Dao) GetTagListByIDs(ids []uint32, state uint8) ([]*model.Tag, error) {
+	tag := model.Tag{State: state}
+	return tag.ListByIDs(d.engine, ids)
+}
+
+func (d *Dao) CreateTag(name string, state uint8, createdBy string) (*model.Tag, error) {
 	tag := model.Tag{
 		Name:  name,
 		State: state,
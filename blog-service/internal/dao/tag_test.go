@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"testing"
+
+	"blog-service/internal/model"
+)
+
+func TestTagCRUD(t *testing.T) {
+	d, db := newTestDao(t)
+
+	for i, name := range []string{"Go", "Rust", "Java"} {
+		tag, err := d.CreateTag(name, model.STATE_OPEN, "tester")
+		if err != nil {
+			t.Fatalf("CreateTag(%s) err: %v", name, err)
+		}
+		if tag.ID != uint32(i+1) || tag.Name != name {
+			t.Errorf("CreateTag(%s) = %d, %q, want the created row", name, tag.ID, tag.Name)
+		}
+	}
+
+	count, err := d.CountTag("", model.STATE_OPEN)
+	if err != nil {
+		t.Fatalf("CountTag err: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("CountTag = %d, want 3", count)
+	}
+
+	tags, err := d.GetTagList("Rust", model.STATE_OPEN, 1, 10)
+	if err != nil {
+		t.Fatalf("GetTagList err: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "Rust" {
+		t.Fatalf("GetTagList(Rust) = %+v, want a single Rust tag", tags)
+	}
+	tag := tags[0]
+	if tag.CreatedOn == 0 || tag.ModifiedOn == 0 {
+		t.Errorf("CreateTag timestamps = (%d, %d), want both set", tag.CreatedOn, tag.ModifiedOn)
+	}
+
+	tags, err = d.GetTagList("", model.STATE_OPEN, 2, 2)
+	if err != nil {
+		t.Fatalf("GetTagList page 2 err: %v", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("GetTagList page 2 len = %d, want 1", len(tags))
+	}
+
+	if err := d.UpdateTag(tag.ID, "Rustlang", model.STATE_CLOSE, "editor"); err != nil {
+		t.Fatalf("UpdateTag err: %v", err)
+	}
+	got, err := d.GetTag(tag.ID, model.STATE_CLOSE)
+	if err != nil {
+		t.Fatalf("GetTag err: %v", err)
+	}
+	if got.Model == nil || got.Name != "Rustlang" || got.ModifiedBy != "editor" {
+		t.Errorf("GetTag after update = %+v, want name Rustlang modified by editor", got)
+	}
+
+	if err := d.DeleteTag(tag.ID); err != nil {
+		t.Fatalf("DeleteTag err: %v", err)
+	}
+	got, err = d.GetTag(tag.ID, model.STATE_CLOSE)
+	if err != nil {
+		t.Fatalf("GetTag after delete err: %v", err)
+	}
+	if got.Model != nil {
+		t.Errorf("GetTag after delete = %+v, want not found", got)
+	}
+
+	row := rawRow(t, db, model.Tag{}.TableName(), tag.ID)
+	if row.IsDel != 1 || row.DeletedOn == 0 {
+		t.Errorf("soft deleted row is_del = %d, deleted_on = %d, want 1 and non-zero", row.IsDel, row.DeletedOn)
+	}
+}
+
+func TestGetTagListByIDs(t *testing.T) {
+	d, _ := newTestDao(t)
+
+	states := []uint8{model.STATE_OPEN, model.STATE_CLOSE, model.STATE_OPEN}
+	for i, state := range states {
+		if _, err := d.CreateTag(string(rune('A'+i)), state, "tester"); err != nil {
+			t.Fatalf("CreateTag err: %v", err)
+		}
+	}
+
+	tags, err := d.GetTagListByIDs([]uint32{1, 2, 3}, model.STATE_OPEN)
+	if err != nil {
+		t.Fatalf("GetTagListByIDs err: %v", err)
+	}
+	if len(tags) != 2 {
+		t.Errorf("GetTagListByIDs len = %d, want 2", len(tags))
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 访问日志, 记录请求方法、路径、状态码、耗时及请求和响应的大小
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		beginTime := time.Now()
+		c.Next()
+		endTime := time.Now()
+
+		requestSize := c.Request.ContentLength
+		if requestSize < 0 {
+			requestSize = 0
+		}
+		responseSize := c.Writer.Size()
+		if responseSize < 0 {
+			responseSize = 0
+		}
+
+		fields := logger.Fields{
+			"method":        c.Request.Method,
+			"path":          c.Request.URL.Path,
+			"status_code":   c.Writer.Status(),
+			"latency":       endTime.Sub(beginTime).String(),
+			"request_size":  requestSize,
+			"response_size": responseSize,
+			"client_ip":     c.ClientIP(),
+		}
+		global.Logger.WithFields(fields).WithTrace(c).Infof("access log: method: %s, path: %s, status_code: %d, begin_time: %d, end_time: %d",
+			c.Request.Method,
+			c.Request.URL.Path,
+			c.Writer.Status(),
+			beginTime.Unix(),
+			endTime.Unix(),
+		)
+	}
+}
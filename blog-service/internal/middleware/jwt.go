@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// 校验请求中携带的Token
+func JWT() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ecode := errcode.Success
+		token := app.GetToken(c)
+		if token == "" {
+			ecode = errcode.UnauthorizedTokenError
+		} else {
+			_, err := app.ParseToken(token)
+			if err != nil {
+				ecode = errcode.UnauthorizedTokenError
+				if verr, ok := err.(*jwt.ValidationError); ok && verr.Errors&jwt.ValidationErrorExpired != 0 {
+					ecode = errcode.UnauthorizedTokenTimeout
+				}
+			}
+		}
+
+		if ecode != errcode.Success {
+			response := app.NewResponse(c)
+			response.ToErrorResponse(ecode)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 只允许审核员访问, 需要在JWT之后使用
+func Moderator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !app.IsModerator(c) {
+			response := app.NewResponse(c)
+			response.ToErrorResponse(errcode.Forbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
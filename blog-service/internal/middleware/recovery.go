@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/app"
+	"blog-service/pkg/email"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/setting"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 捕获panic, 记录调用堆栈并通过邮件发送告警
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				global.Logger.WithCallersFrames().WithTrace(c).Errorf("panic recover err: %v", err)
+
+				setting.RLock()
+				emailSetting := global.EmailSetting
+				setting.RUnlock()
+				if emailSetting.Host != "" && len(emailSetting.To) > 0 {
+					mailer := email.NewEmail(&email.SMTPInfo{
+						Host:     emailSetting.Host,
+						Port:     emailSetting.Port,
+						IsSSL:    emailSetting.IsSSL,
+						UserName: emailSetting.UserName,
+						Password: emailSetting.Password,
+						From:     emailSetting.From,
+					})
+					ctx := c.Copy()
+					subject := fmt.Sprintf("异常抛出，发生时间: %s", time.Now().Format("2006-01-02 15:04:05"))
+					body := fmt.Sprintf("请求: %s %s<br/>错误信息: %v", c.Request.Method, c.Request.URL.Path, err)
+					// 异步发送告警邮件, 避免阻塞当前请求的响应
+					go func() {
+						if err := mailer.SendMail(emailSetting.To, subject, body); err != nil {
+							global.Logger.WithTrace(ctx).Errorf("mail.SendMail err: %v", err)
+						}
+					}()
+				}
+
+				app.NewResponse(c).ToErrorResponse(errcode.ServerError)
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-service/global"
+	"blog-service/pkg/email/emailtest"
+	"blog-service/pkg/logger"
+	"blog-service/pkg/setting"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newPanicRouter(t *testing.T, emailSetting *setting.EmailSettingS) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	l, es := global.Logger, global.EmailSetting
+	global.Logger = logger.NewLogger(io.Discard, "", log.LstdFlags)
+	global.EmailSetting = emailSetting
+	t.Cleanup(func() { global.Logger, global.EmailSetting = l, es })
+
+	r := gin.New()
+	r.Use(Recovery())
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	return r
+}
+
+func TestRecoverySendsAlert(t *testing.T) {
+	server := emailtest.NewServer(t)
+	r := newPanicRouter(t, &setting.EmailSettingS{
+		Host: server.Host,
+		Port: server.Port,
+		From: "alert@example.com",
+		To:   []string{"ops@example.com"},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	msg := server.Receive(5 * time.Second)
+	if msg == nil {
+		t.Fatal("SMTP server received no alert")
+	}
+	if len(msg.To) != 1 || msg.To[0] != "ops@example.com" {
+		t.Errorf("To = %v, want [ops@example.com]", msg.To)
+	}
+	body, err := msg.Body()
+	if err != nil {
+		t.Fatalf("msg.Body err: %v", err)
+	}
+	if !strings.Contains(body, "GET /panic") || !strings.Contains(body, "boom") {
+		t.Errorf("body = %q, want the request and panic value", body)
+	}
+}
+
+func TestRecoveryWithoutEmailHost(t *testing.T) {
+	server := emailtest.NewServer(t)
+	r := newPanicRouter(t, &setting.EmailSettingS{Port: server.Port, To: []string{"ops@example.com"}})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if msg := server.Receive(200 * time.Millisecond); msg != nil {
+		t.Errorf("SMTP server received %+v, want no alert when Host is empty", msg)
+	}
+}
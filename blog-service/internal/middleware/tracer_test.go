@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog-service/global"
+	"blog-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 处理函数和service中记录的日志都带有当前请求的trace_id
+func TestTracingLogCorrelation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	l, tracer := global.Logger, global.Tracer
+	global.Logger = logger.NewLogger(&buf, "", 0)
+	global.Tracer = sdktrace.NewTracerProvider().Tracer("test")
+	t.Cleanup(func() { global.Logger, global.Tracer = l, tracer })
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/log", func(c *gin.Context) {
+		global.Logger.WithTrace(c).Errorf("handler")
+		global.Logger.WithTrace(c.Request.Context()).Errorf("service")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/log", nil))
+	traceID := w.Header().Get("X-Trace-ID")
+	if traceID == "" {
+		t.Fatal("response has no X-Trace-ID header")
+	}
+
+	dec := json.NewDecoder(&buf)
+	for _, msg := range []string{"handler", "service"} {
+		var entry map[string]interface{}
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("decode %s log err: %v", msg, err)
+		}
+		if entry["message"] != msg || entry["trace_id"] != traceID || entry["span_id"] == nil {
+			t.Errorf("%s log = %v, want trace_id %s and a span_id", msg, entry, traceID)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package middleware
 
 import (
+	"blog-service/pkg/errcode"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
@@ -12,27 +17,108 @@ import (
 	zh_translations "github.com/go-playground/validator/v10/translations/zh"
 )
 
+const defaultLocale = "zh"
+
+// 有校验错误翻译的语言, 其他语言的校验错误统一使用errcode.FallbackLocale
+var validatorTranslations = map[string]func(*validator.Validate, ut.Translator) error{
+	"zh": zh_translations.RegisterDefaultTranslations,
+	"en": en_translations.RegisterDefaultTranslations,
+}
+
 func Translations() gin.HandlerFunc {
+	// 翻译只在创建中间件时注册一次, 避免并发请求同时修改校验器
+	uni := ut.New(en.New(), zh.New(), zh_Hant_TW.New())
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if ok {
+		for locale, register := range validatorTranslations {
+			trans, _ := uni.GetTranslator(locale)
+			_ = register(v, trans)
+		}
+	}
+
 	return func(c *gin.Context) {
-		uni := ut.New(en.New(), zh.New(), zh_Hant_TW.New())
-		locale := c.GetHeader("locale")
-		trans, _ := uni.GetTranslator(locale)
-		v, ok := binding.Validator.Engine().(*validator.Validate)
+		locale := getLocale(c)
 		if ok {
-			switch locale {
-			case "zh":
-				_ = zh_translations.RegisterDefaultTranslations(v, trans)
-				break
-			case "en":
-				_ = en_translations.RegisterDefaultTranslations(v, trans)
-				break
-			default:
-				_ = zh_translations.RegisterDefaultTranslations(v, trans)
+			c.Set("trans", validatorTranslator(uni, locale))
+		}
+		// 错误信息使用同一个语言
+		c.Set("locale", locale)
+
+		c.Next()
+	}
+}
+
+func validatorTranslator(uni *ut.UniversalTranslator, locale string) ut.Translator {
+	if _, ok := validatorTranslations[locale]; !ok {
+		locale = errcode.FallbackLocale
+	}
+	trans, _ := uni.GetTranslator(locale)
+	return trans
+}
+
+// 优先使用请求头locale, 其次按Accept-Language的权重选择支持的语言, 都不支持时使用中文
+func getLocale(c *gin.Context) string {
+	candidates := parseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if locale := c.GetHeader("locale"); locale != "" {
+		candidates = append([]string{locale}, candidates...)
+	}
+
+	supported := map[string]bool{}
+	for _, locale := range errcode.Locales() {
+		supported[locale] = true
+	}
+	for _, candidate := range candidates {
+		// zh-Hant-TW、zh_Hant_TW 都转换为 zh_Hant_TW, 找不到时再尝试上级语言 zh_Hant、zh
+		locale := strings.ReplaceAll(candidate, "-", "_")
+		for locale != "" {
+			if supported[locale] {
+				return locale
+			}
+			i := strings.LastIndex(locale, "_")
+			if i < 0 {
 				break
 			}
-			c.Set("trans", trans)
+			locale = locale[:i]
 		}
+	}
+	return defaultLocale
+}
 
-		c.Next()
+// 解析 Accept-Language, 如 en-US,en;q=0.9,zh-CN;q=0.8, 返回按权重排序的语言
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			languages = append(languages, language{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+	tags := make([]string, 0, len(languages))
+	for _, l := range languages {
+		tags = append(tags, l.tag)
 	}
+	return tags
 }
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetLocale(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		locale         string
+		acceptLanguage string
+		want           string
+	}{
+		{"no header", "", "", "zh"},
+		{"locale header", "en", "zh-CN", "en"},
+		{"unsupported locale header", "fr", "en", "en"},
+		{"quality order", "", "zh;q=0.5,en-US;q=0.8", "en"},
+		{"region fallback", "", "zh-Hant-TW", "zh_Hant_TW"},
+		{"parent fallback", "", "zh-Hant-HK", "zh"},
+		{"zero quality", "", "en;q=0,zh-CN", "zh"},
+		{"unsupported only", "", "fr-FR,de;q=0.9", "zh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.locale != "" {
+				c.Request.Header.Set("locale", tt.locale)
+			}
+			if tt.acceptLanguage != "" {
+				c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			if got := getLocale(c); got != tt.want {
+				t.Errorf("getLocale = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// 没有校验错误翻译的语言使用英文的校验错误, 错误信息仍使用该语言的目录
+func TestTranslationsValidationFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Translations())
+	r.GET("/tags", func(c *gin.Context) {
+		var param struct {
+			Name string `form:"name" binding:"required"`
+		}
+		response := app.NewResponse(c)
+		if valid, errs := app.BindAndValid(c, &param); !valid {
+			response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+			return
+		}
+		response.ToResponse(gin.H{})
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		wantMsg        string
+		wantDetail     string
+	}{
+		{"zh-CN", "入参错误", "Name为必填字段"},
+		{"en-US", "Invalid parameters", "Name is a required field"},
+		{"zh-Hant-TW", "入參錯誤", "Name is a required field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var body struct {
+				Msg     string   `json:"msg"`
+				Details []string `json:"details"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("json.Unmarshal err: %v, body: %s", err, w.Body.String())
+			}
+			if body.Msg != tt.wantMsg {
+				t.Errorf("msg = %q, want %q", body.Msg, tt.wantMsg)
+			}
+			if len(body.Details) != 1 || !strings.Contains(body.Details[0], tt.wantDetail) {
+				t.Errorf("details = %q, want %q", body.Details, tt.wantDetail)
+			}
+		})
+	}
+}
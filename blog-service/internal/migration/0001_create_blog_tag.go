@@ -0,0 +1,29 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogTagV1 struct {
+	CommonColumns
+	Name  string `gorm:"size:100;not null;default:''"`
+	State uint8  `gorm:"not null;default:1"`
+}
+
+func (t blogTagV1) TableName() string {
+	return "blog_tag"
+}
+
+func init() {
+	register(&Migration{
+		Version: 1,
+		Name:    "create_blog_tag",
+		Up: func(tx *gorm.DB) error {
+			if err := renameLegacyTable(tx, "tag", &blogTagV1{}); err != nil {
+				return err
+			}
+			return createTable(tx, &blogTagV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogTagV1{})
+		},
+	})
+}
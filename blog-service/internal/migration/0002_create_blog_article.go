@@ -0,0 +1,34 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogArticleV1 struct {
+	CommonColumns
+	Title         string `gorm:"size:100;not null;default:''"`
+	Desc          string `gorm:"size:255;not null;default:''"`
+	Content       string
+	ContentHTML   string
+	CoverImageUrl string `gorm:"size:255;not null;default:''"`
+	State         uint8  `gorm:"not null;default:1;index:idx_blog_article_state_publish_at,priority:1"`
+	PublishAt     uint32 `gorm:"not null;default:0;index:idx_blog_article_state_publish_at,priority:2"`
+}
+
+func (a blogArticleV1) TableName() string {
+	return "blog_article"
+}
+
+func init() {
+	register(&Migration{
+		Version: 2,
+		Name:    "create_blog_article",
+		Up: func(tx *gorm.DB) error {
+			if err := renameLegacyTable(tx, "article", &blogArticleV1{}); err != nil {
+				return err
+			}
+			return createTable(tx, &blogArticleV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogArticleV1{})
+		},
+	})
+}
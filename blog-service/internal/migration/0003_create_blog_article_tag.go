@@ -0,0 +1,29 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogArticleTagV1 struct {
+	CommonColumns
+	ArticleID uint32 `gorm:"not null;default:0;index"`
+	TagID     uint32 `gorm:"not null;default:0;index"`
+}
+
+func (a blogArticleTagV1) TableName() string {
+	return "blog_article_tag"
+}
+
+func init() {
+	register(&Migration{
+		Version: 3,
+		Name:    "create_blog_article_tag",
+		Up: func(tx *gorm.DB) error {
+			if err := renameLegacyTable(tx, "article_tag", &blogArticleTagV1{}); err != nil {
+				return err
+			}
+			return createTable(tx, &blogArticleTagV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogArticleTagV1{})
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogAuthV1 struct {
+	CommonColumns
+	AppKey    string `gorm:"size:20;not null;default:''"`
+	AppSecret string `gorm:"size:50;not null;default:''"`
+}
+
+func (a blogAuthV1) TableName() string {
+	return "blog_auth"
+}
+
+func init() {
+	register(&Migration{
+		Version: 4,
+		Name:    "create_blog_auth",
+		Up: func(tx *gorm.DB) error {
+			return createTable(tx, &blogAuthV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogAuthV1{})
+		},
+	})
+}
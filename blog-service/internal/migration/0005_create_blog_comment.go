@@ -0,0 +1,28 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogCommentV1 struct {
+	CommonColumns
+	ArticleID uint32 `gorm:"not null;default:0;index"`
+	ParentID  uint32 `gorm:"not null;default:0"`
+	Content   string `gorm:"size:1000;not null;default:''"`
+	State     uint8  `gorm:"not null;default:0"`
+}
+
+func (c blogCommentV1) TableName() string {
+	return "blog_comment"
+}
+
+func init() {
+	register(&Migration{
+		Version: 5,
+		Name:    "create_blog_comment",
+		Up: func(tx *gorm.DB) error {
+			return createTable(tx, &blogCommentV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogCommentV1{})
+		},
+	})
+}
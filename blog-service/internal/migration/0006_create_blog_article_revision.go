@@ -0,0 +1,31 @@
+package migration
+
+import "gorm.io/gorm"
+
+type blogArticleRevisionV1 struct {
+	CommonColumns
+	ArticleID     uint32 `gorm:"not null;default:0;uniqueIndex:uk_blog_article_revision,priority:1"`
+	Revision      uint32 `gorm:"not null;default:0;uniqueIndex:uk_blog_article_revision,priority:2"`
+	Title         string `gorm:"size:100;not null;default:''"`
+	Desc          string `gorm:"size:255;not null;default:''"`
+	Content       string
+	CoverImageUrl string `gorm:"size:255;not null;default:''"`
+	State         uint8  `gorm:"not null;default:0"`
+}
+
+func (a blogArticleRevisionV1) TableName() string {
+	return "blog_article_revision"
+}
+
+func init() {
+	register(&Migration{
+		Version: 6,
+		Name:    "create_blog_article_revision",
+		Up: func(tx *gorm.DB) error {
+			return createTable(tx, &blogArticleRevisionV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&blogArticleRevisionV1{})
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"blog-service/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+// app_secret改为保存SHA-256编码后的值
+type blogAuthV2 struct {
+	CommonColumns
+	AppKey    string `gorm:"size:20;not null;default:''"`
+	AppSecret string `gorm:"size:64;not null;default:''"`
+}
+
+func (a blogAuthV2) TableName() string {
+	return "blog_auth"
+}
+
+func init() {
+	register(&Migration{
+		Version: 7,
+		Name:    "hash_blog_auth_secret",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AlterColumn(&blogAuthV2{}, "AppSecret"); err != nil {
+				return err
+			}
+
+			var auths []blogAuthV2
+			if err := tx.Find(&auths).Error; err != nil {
+				return err
+			}
+			for _, auth := range auths {
+				// 原来的字段最长50个字符, 已经是64位编码的值说明上次执行时已经更新过
+				if isSHA256Hex(auth.AppSecret) {
+					continue
+				}
+				err := tx.Model(&blogAuthV2{}).Where("id = ?", auth.ID).
+					Update("app_secret", util.EncodeSHA256(auth.AppSecret)).Error
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		// 编码后无法还原原来的Secret
+		Down: func(tx *gorm.DB) error {
+			return errors.New("hashed app_secret cannot be restored")
+		},
+	})
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
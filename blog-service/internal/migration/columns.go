@@ -0,0 +1,14 @@
+package migration
+
+// CommonColumns 各业务表共用的公共字段, 对应model.Model
+// 迁移中的表结构是创建时的快照, 不引用model包中的结构体, 避免模型修改后影响已发布的迁移
+// 必须导出, gorm会忽略未导出的匿名嵌入字段
+type CommonColumns struct {
+	ID         uint32 `gorm:"primaryKey;autoIncrement"`
+	CreatedBy  string `gorm:"size:100;not null;default:''"`
+	ModifiedBy string `gorm:"size:100;not null;default:''"`
+	CreatedOn  uint32 `gorm:"not null;default:0"`
+	ModifiedOn uint32 `gorm:"not null;default:0"`
+	DeletedOn  uint32 `gorm:"not null;default:0"`
+	IsDel      uint8  `gorm:"not null;default:0"`
+}
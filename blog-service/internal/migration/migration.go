@@ -0,0 +1,160 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 一个版本的数据库结构变更, 版本号递增且发布后不再修改
+type Migration struct {
+	Version uint32
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// 记录已执行的迁移版本
+type schemaMigration struct {
+	Version   uint32 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:100;not null;default:''"`
+	AppliedOn int64  `gorm:"not null;default:0"`
+}
+
+func (m schemaMigration) TableName() string {
+	return "blog_schema_migration"
+}
+
+var migrations []*Migration
+
+func register(m *Migration) {
+	migrations = append(migrations, m)
+}
+
+// 按版本号升序排列的全部迁移
+func sortedMigrations() []*Migration {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// Up 按版本号顺序执行全部未执行的迁移, 返回本次执行的迁移
+// 每个迁移在一个事务中执行, 但MySQL中的DDL会隐式提交, 迁移中途失败时已执行的DDL不会回滚,
+// 因此每个迁移的Up都必须可以重复执行, 修复问题后再次执行migrate up即可继续
+func Up(db *gorm.DB) ([]*Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []*Migration
+	for _, m := range sortedMigrations() {
+		if applied[m.Version] {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedOn: time.Now().Unix()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// Pending 返回尚未执行的迁移, 不修改数据库
+func Pending(db *gorm.DB) ([]*Migration, error) {
+	applied := map[uint32]bool{}
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		var versions []uint32
+		if err := db.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			applied[v] = true
+		}
+	}
+
+	var pending []*Migration
+	for _, m := range sortedMigrations() {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Down 从最新的版本开始回滚steps个已执行的迁移, 返回本次回滚的迁移
+func Down(db *gorm.DB, steps int) ([]*Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := sortedMigrations()
+	var done []*Migration
+	for i := len(sorted) - 1; i >= 0 && len(done) < steps; i-- {
+		m := sorted[i]
+		if !applied[m.Version] {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{Version: m.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// 将基线版本中没有前缀的表(如article)改名为当前的表名, 新表已存在或旧表不存在时不做处理
+// 需要在对应表的createTable之前调用, 改名后由createTable补齐缺少的字段和索引
+func renameLegacyTable(tx *gorm.DB, legacy string, table interface{}) error {
+	if tx.Migrator().HasTable(table) || !tx.Migrator().HasTable(legacy) {
+		return nil
+	}
+	return tx.Migrator().RenameTable(legacy, table)
+}
+
+// 创建表, 表已存在时(如使用早期的建表SQL初始化的数据库)只补齐缺少的字段和索引, 保留已有数据
+func createTable(tx *gorm.DB, table interface{}) error {
+	if tx.Migrator().HasTable(table) {
+		return tx.AutoMigrate(table)
+	}
+	return tx.Migrator().CreateTable(table)
+}
+
+func appliedVersions(db *gorm.DB) (map[uint32]bool, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var versions []uint32
+	if err := db.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint32]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+	return applied, nil
+}
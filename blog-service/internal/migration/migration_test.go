@@ -0,0 +1,257 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-service/pkg/util"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:migration_%s?mode=memory&cache=shared", name)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open err: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB err: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+// 执行版本号小于version的迁移, 模拟升级前的数据库
+func upTo(t *testing.T, db *gorm.DB, version uint32) {
+	t.Helper()
+
+	if _, err := appliedVersions(db); err != nil {
+		t.Fatalf("appliedVersions err: %v", err)
+	}
+	for _, m := range sortedMigrations() {
+		if m.Version >= version {
+			break
+		}
+		if err := m.Up(db); err != nil {
+			t.Fatalf("migration %d up err: %v", m.Version, err)
+		}
+		if err := db.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedOn: time.Now().Unix()}).Error; err != nil {
+			t.Fatalf("record migration %d err: %v", m.Version, err)
+		}
+	}
+}
+
+func TestHashBlogAuthSecret(t *testing.T) {
+	db := newTestDB(t)
+	upTo(t, db, 7)
+
+	if err := db.Create(&blogAuthV1{AppKey: "app-key", AppSecret: "app-secret"}).Error; err != nil {
+		t.Fatalf("create auth err: %v", err)
+	}
+
+	done, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up err: %v", err)
+	}
+	if len(done) == 0 || done[0].Version != 7 {
+		t.Fatalf("Up applied %v, want to start with version 7", done)
+	}
+
+	var auth blogAuthV2
+	if err := db.Where("app_key = ?", "app-key").Take(&auth).Error; err != nil {
+		t.Fatalf("query auth err: %v", err)
+	}
+	if auth.AppSecret != util.EncodeSHA256("app-secret") {
+		t.Errorf("AppSecret = %q, want SHA-256 of the original secret", auth.AppSecret)
+	}
+
+	// 编码不可逆, 不能回滚
+	if _, err := Down(db, 1); err == nil {
+		t.Errorf("Down err = nil, want error")
+	}
+}
+
+// 早期建表SQL创建的表, 缺少后来新增的字段
+var legacySchema = []string{
+	"CREATE TABLE `blog_tag` (`id` integer PRIMARY KEY AUTOINCREMENT, `name` varchar(100) DEFAULT '', " +
+		"`created_on` integer DEFAULT 0, `created_by` varchar(100) DEFAULT '', `modified_on` integer DEFAULT 0, " +
+		"`modified_by` varchar(100) DEFAULT '', `deleted_on` integer DEFAULT 0, `is_del` tinyint DEFAULT 0, `state` tinyint DEFAULT 1)",
+	"CREATE TABLE `blog_article` (`id` integer PRIMARY KEY AUTOINCREMENT, `title` varchar(100) DEFAULT '', " +
+		"`desc` varchar(255) DEFAULT '', `cover_image_url` varchar(255) DEFAULT '', `content` longtext, " +
+		"`created_on` integer DEFAULT 0, `created_by` varchar(100) DEFAULT '', `modified_on` integer DEFAULT 0, " +
+		"`modified_by` varchar(100) DEFAULT '', `deleted_on` integer DEFAULT 0, `is_del` tinyint DEFAULT 0, `state` tinyint DEFAULT 1)",
+	"CREATE TABLE `blog_article_tag` (`id` integer PRIMARY KEY AUTOINCREMENT, `article_id` integer NOT NULL, " +
+		"`tag_id` integer NOT NULL DEFAULT 0, `created_on` integer DEFAULT 0, `created_by` varchar(100) DEFAULT '', " +
+		"`modified_on` integer DEFAULT 0, `modified_by` varchar(100) DEFAULT '', `deleted_on` integer DEFAULT 0, `is_del` tinyint DEFAULT 0)",
+	"CREATE TABLE `blog_auth` (`id` integer PRIMARY KEY AUTOINCREMENT, `app_key` varchar(20) DEFAULT '', " +
+		"`app_secret` varchar(50) DEFAULT '', `created_on` integer DEFAULT 0, `created_by` varchar(100) DEFAULT '', " +
+		"`modified_on` integer DEFAULT 0, `modified_by` varchar(100) DEFAULT '', `deleted_on` integer DEFAULT 0, `is_del` tinyint DEFAULT 0)",
+	"INSERT INTO `blog_tag` (`name`, `created_by`) VALUES ('Go', 'eddycjy')",
+	"INSERT INTO `blog_article` (`title`, `desc`, `content`) VALUES ('Hello', 'desc', '# Hello')",
+	"INSERT INTO `blog_auth` (`app_key`, `app_secret`) VALUES ('eddycjy', 'go-programming-tour-book')",
+}
+
+func TestUpLegacySchema(t *testing.T) {
+	db := newTestDB(t)
+	for _, stmt := range legacySchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("exec %q err: %v", stmt, err)
+		}
+	}
+
+	done, err := Up(db)
+	if err != nil {
+		t.Fatalf("Up err: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Errorf("Up applied %d migrations, want %d", len(done), len(migrations))
+	}
+
+	for _, column := range []string{"content_html", "publish_at"} {
+		if !db.Migrator().HasColumn(&blogArticleV1{}, column) {
+			t.Errorf("blog_article has no %s column after Up", column)
+		}
+	}
+	if !db.Migrator().HasIndex(&blogArticleV1{}, "idx_blog_article_state_publish_at") {
+		t.Error("blog_article has no idx_blog_article_state_publish_at index after Up")
+	}
+	for _, table := range []string{"blog_comment", "blog_article_revision"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %s not created", table)
+		}
+	}
+
+	var tag blogTagV1
+	if err := db.First(&tag).Error; err != nil || tag.Name != "Go" || tag.CreatedBy != "eddycjy" {
+		t.Errorf("blog_tag row = %+v, %v, want the existing tag kept", tag, err)
+	}
+	var article blogArticleV1
+	if err := db.First(&article).Error; err != nil || article.Title != "Hello" || article.Content != "# Hello" {
+		t.Errorf("blog_article row = %+v, %v, want the existing article kept", article, err)
+	}
+	var auth blogAuthV2
+	if err := db.First(&auth).Error; err != nil || auth.AppSecret != util.EncodeSHA256("go-programming-tour-book") {
+		t.Errorf("blog_auth row = %+v, %v, want the existing secret hashed", auth, err)
+	}
+
+	// 再次执行时没有需要执行的迁移
+	if done, err := Up(db); err != nil || len(done) != 0 {
+		t.Errorf("second Up = %d migrations, %v, want none", len(done), err)
+	}
+}
+
+// 基线版本的表没有blog_前缀, 数据由gorm v1写入, 各字段都有值
+var baselineSchema = []string{
+	"CREATE TABLE `tag` (`id` integer PRIMARY KEY AUTOINCREMENT, `created_by` varchar(255), `modified_by` varchar(255), " +
+		"`created_on` integer, `modified_on` integer, `deleted_on` integer, `is_del` tinyint, `name` varchar(255), `state` tinyint)",
+	"CREATE TABLE `article` (`id` integer PRIMARY KEY AUTOINCREMENT, `created_by` varchar(255), `modified_by` varchar(255), " +
+		"`created_on` integer, `modified_on` integer, `deleted_on` integer, `is_del` tinyint, `title` varchar(255), " +
+		"`desc` varchar(255), `content` varchar(255), `cover_image_url` varchar(255), `state` tinyint)",
+	"CREATE TABLE `article_tag` (`id` integer PRIMARY KEY AUTOINCREMENT, `created_by` varchar(255), `modified_by` varchar(255), " +
+		"`created_on` integer, `modified_on` integer, `deleted_on` integer, `is_del` tinyint, `tag_id` integer, `article_id` integer)",
+	"INSERT INTO `tag` VALUES (1, 'eddycjy', '', 1600000000, 0, 0, 0, 'Go', 1)",
+	"INSERT INTO `article` VALUES (1, 'eddycjy', '', 1600000000, 0, 0, 0, 'Hello', 'desc', '# Hello', 'https://example.com/cover.png', 1)",
+	"INSERT INTO `article_tag` VALUES (1, 'eddycjy', '', 1600000000, 0, 0, 0, 1, 1)",
+}
+
+func TestUpBaselineSchema(t *testing.T) {
+	db := newTestDB(t)
+	for _, stmt := range baselineSchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("exec %q err: %v", stmt, err)
+		}
+	}
+
+	if _, err := Up(db); err != nil {
+		t.Fatalf("Up err: %v", err)
+	}
+
+	for _, table := range []string{"tag", "article", "article_tag"} {
+		if db.Migrator().HasTable(table) {
+			t.Errorf("legacy table %s still exists after Up", table)
+		}
+	}
+	if !db.Migrator().HasColumn(&blogArticleV1{}, "publish_at") {
+		t.Error("blog_article has no publish_at column after Up")
+	}
+
+	var tag blogTagV1
+	if err := db.First(&tag).Error; err != nil || tag.Name != "Go" {
+		t.Errorf("blog_tag row = %+v, %v, want the baseline tag", tag, err)
+	}
+	var article blogArticleV1
+	if err := db.First(&article).Error; err != nil || article.Title != "Hello" || article.Content != "# Hello" {
+		t.Errorf("blog_article row = %+v, %v, want the baseline article", article, err)
+	}
+	var link blogArticleTagV1
+	if err := db.First(&link).Error; err != nil || link.ArticleID != article.ID || link.TagID != tag.ID {
+		t.Errorf("blog_article_tag row = %+v, %v, want the baseline link", link, err)
+	}
+
+	// 新的表可以继续写入, 自增ID接着原有的数据
+	created := blogArticleV1{Title: "World"}
+	if err := db.Create(&created).Error; err != nil || created.ID != 2 {
+		t.Errorf("create article = %d, %v, want id 2", created.ID, err)
+	}
+}
+
+func TestPending(t *testing.T) {
+	db := newTestDB(t)
+
+	pending, err := Pending(db)
+	if err != nil || len(pending) != len(migrations) {
+		t.Fatalf("Pending on empty db = %d, %v, want %d", len(pending), err, len(migrations))
+	}
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		t.Error("Pending created the migration table")
+	}
+
+	upTo(t, db, 5)
+	pending, err = Pending(db)
+	if err != nil || len(pending) == 0 || pending[0].Version != 5 {
+		t.Fatalf("Pending after upTo 5 = %v, %v, want to start with version 5", pending, err)
+	}
+
+	if _, err := Up(db); err != nil {
+		t.Fatalf("Up err: %v", err)
+	}
+	if pending, err := Pending(db); err != nil || len(pending) != 0 {
+		t.Errorf("Pending after Up = %d, %v, want none", len(pending), err)
+	}
+}
+
+// MySQL中DDL会隐式提交, 迁移失败后重新执行时不能重复编码已经更新过的Secret
+func TestHashBlogAuthSecretRerun(t *testing.T) {
+	db := newTestDB(t)
+	upTo(t, db, 7)
+
+	if err := db.Create(&blogAuthV1{AppKey: "app-key", AppSecret: "app-secret"}).Error; err != nil {
+		t.Fatalf("create auth err: %v", err)
+	}
+	m := sortedMigrations()[6]
+	for i := 0; i < 2; i++ {
+		if err := m.Up(db); err != nil {
+			t.Fatalf("migration %d up #%d err: %v", m.Version, i+1, err)
+		}
+	}
+
+	var auth blogAuthV2
+	if err := db.Where("app_key = ?", "app-key").Take(&auth).Error; err != nil {
+		t.Fatalf("query auth err: %v", err)
+	}
+	if auth.AppSecret != util.EncodeSHA256("app-secret") {
+		t.Errorf("AppSecret = %q, want SHA-256 of the original secret applied once", auth.AppSecret)
+	}
+}
@@ -3,20 +3,31 @@ package model
 import (
 	"blog-service/pkg/app"
 
-	"github.com/jinzhu/gorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 文章的发布状态, 0和1与旧的禁用、启用状态保持一致
+const (
+	ARTICLE_STATE_DRAFT     = 0
+	ARTICLE_STATE_PUBLISHED = 1
+	ARTICLE_STATE_SCHEDULED = 2
+	ARTICLE_STATE_ARCHIVED  = 3
 )
 
 type Article struct {
 	*Model
 	Title         string `json:"name"`            // 文章标题
 	Desc          string `json:"desc"`            // 文章简述
-	Content       string `json:"content"`         // 文章内容
+	Content       string `json:"content"`         // 文章内容, Markdown格式
+	ContentHTML   string `json:"content_html"`    // 由文章内容渲染并过滤后的HTML
 	CoverImageUrl string `json:"cover_image_url"` // 封面图地址
-	State         uint8  `json:"state"`           // 状态：0为禁用、1为启用
+	State         uint8  `json:"state"`           // 状态：0为草稿、1为已发布、2为定时发布、3为已归档
+	PublishAt     uint32 `json:"publish_at"`      // 发布时间, 定时发布的文章为计划发布的时间
 }
 
 func (a Article) TableName() string {
-	return "article"
+	return "blog_article"
 }
 
 type ArticleSwagger struct {
@@ -32,15 +43,20 @@ func (a Article) Create(db *gorm.DB) (*Article, error) {
 }
 
 func (a Article) Update(db *gorm.DB, values interface{}) error {
-	if err := db.Model(&a).Update(values).Where("id = ? AND is_del = ?", a.ID).Error; err != nil {
+	if err := db.Model(&a).Where("id = ? AND is_del = ?", a.ID, 0).Updates(values).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
+// 只更新指定的字段, 不修改modified_on
+func (a Article) UpdateColumns(db *gorm.DB, values interface{}) error {
+	return db.Model(&a).Where("id = ? AND is_del = ?", a.ID, 0).UpdateColumns(values).Error
+}
+
 func (a Article) Get(db *gorm.DB) (Article, error) {
 	var article Article
-	db = db.Where("id = ? AND state = ? is_del = ?", a.ID, a.State, 0)
+	db = db.Where("id = ? AND state = ? AND is_del = ?", a.ID, a.State, 0)
 
 	// 查询单条记录 相当于Limit(1)的SQL语句
 	err := db.First(&article).Error
@@ -50,6 +66,72 @@ func (a Article) Get(db *gorm.DB) (Article, error) {
 	return article, nil
 }
 
+// 通过ID获取文章, 不限制状态
+func (a Article) GetByID(db *gorm.DB) (Article, error) {
+	var article Article
+	err := db.Where("id = ? AND is_del = ?", a.ID, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return article, err
+	}
+	return article, nil
+}
+
+// 通过标题获取文章, 不限制状态
+func (a Article) GetByTitle(db *gorm.DB) (Article, error) {
+	var article Article
+	err := db.Where("title = ? AND is_del = ?", a.Title, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return article, err
+	}
+	return article, nil
+}
+
+// 在事务中锁定文章所在的行, 同一文章的修改需要串行执行
+func (a Article) LockByID(db *gorm.DB) (Article, error) {
+	var article Article
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND is_del = ?", a.ID, 0).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return article, err
+	}
+	return article, nil
+}
+
+// 锁定已到发布时间的定时发布文章, 需要在事务中调用
+// 其他实例会阻塞在同一批行上, 等待提交后按最新的状态重新过滤, 因此不会重复发布
+func (a Article) LockDueScheduled(db *gorm.DB, publishAt uint32, limit int) ([]*Article, error) {
+	var articles []*Article
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("state = ? AND publish_at <= ? AND is_del = ?", ARTICLE_STATE_SCHEDULED, publishAt, 0).
+		Order("publish_at ASC").Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 通过ID列表获取文章
+func (a Article) ListByIDs(db *gorm.DB, ids []uint32) ([]*Article, error) {
+	var articles []*Article
+	err := db.Where("id IN (?) AND state = ? AND is_del = ?", ids, a.State, 0).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 获取全部状态的文章列表, 按ID升序
+func (a Article) List(db *gorm.DB, pageOffset, pageSize int) ([]*Article, error) {
+	var articles []*Article
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := db.Where("is_del = ?", 0).Order("id ASC").Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
 func (a Article) Delete(db *gorm.DB) error {
 	if err := db.Where("id = ? AND is_del = ?", a.Model.ID, 0).Delete(&a).Error; err != nil {
 		return err
@@ -65,26 +147,38 @@ type ArticleRow struct {
 	ArticleDesc   string
 	CoverImageUrl string
 	Content       string
+	State         uint8
+	PublishAt     uint32
 }
 
 // 通过TagID获取文章列表
-func (a Article) ListByTagID(db *gorm.DB, tagID uint32, pageOffset, pageSize int) ([]*ArticleRow, error) {
-	fields := []string{"ar.id AS article_id", "ar.title AS article_title", "ar.desc AS article_desc", "ar.cover_image_url", "ar.content"}
-	fields = append(fields, []string{"t.id AS tag_id", "t.name AS tag_name"}...)
-
+func (a Article) ListByTagID(db *gorm.DB, tagID uint32, states []uint8, pageOffset, pageSize int) ([]*ArticleRow, error) {
 	if pageOffset >= 0 && pageSize > 0 {
 		db = db.Offset(pageOffset).Limit(pageSize)
 	}
 
-	// Select: 指定要检索的数据库字段, 如果不指定默认检索全部,即SELECT *
-	// Joins: 指定关联查询的语句
-	// Rows: 扫描行为, 其作用是将当前行中的数据复制到给定的参数中, 常与Row/Rows等语句关联使用
-	rows, err := db.Select(fields).Table(ArticleTag{}.TableName()+" AS at").
-		Joins("LEFT JOIN `"+Tag{}.TableName()+"` AS t ON at.tag_id = t.id").
-		Joins("LEFT JOIN `"+Article{}.TableName()+"` AS ar ON at.article_id = ar.id").
-		Where("at.`tag_id` = ? AND ar.state = ? AND ar.is_del = ?", tagID, a.State, 0).
-		Rows()
+	return scanArticleRows(joinArticleTag(db.Select(articleRowFields), tagID, states))
+}
+
+// 通过TagID按游标获取文章列表, 返回ID小于cursor的文章并按ID倒序, cursor为0时从最新的文章开始
+// 使用键集分页代替OFFSET, 翻页深度不影响查询性能
+func (a Article) ListByTagIDBefore(db *gorm.DB, tagID uint32, states []uint8, cursor uint32, limit int) ([]*ArticleRow, error) {
+	db = joinArticleTag(db.Select(articleRowFields), tagID, states)
+	if cursor > 0 {
+		db = db.Where("ar.id < ?", cursor)
+	}
+
+	return scanArticleRows(db.Order("ar.id DESC").Limit(limit))
+}
+
+var articleRowFields = []string{
+	"ar.id AS article_id", "ar.title AS article_title", "ar.desc AS article_desc", "ar.cover_image_url", "ar.content", "ar.state", "ar.publish_at",
+	"t.id AS tag_id", "t.name AS tag_name",
+}
 
+// Rows: 扫描行为, 其作用是将当前行中的数据复制到给定的参数中, 常与Row/Rows等语句关联使用
+func scanArticleRows(db *gorm.DB) ([]*ArticleRow, error) {
+	rows, err := db.Rows()
 	if err != nil {
 		return nil, err
 	}
@@ -95,27 +189,48 @@ func (a Article) ListByTagID(db *gorm.DB, tagID uint32, pageOffset, pageSize int
 
 	for rows.Next() {
 		r := &ArticleRow{}
-		if err := rows.Scan(&r.ArticleID, &r.ArticleTitle, &r.ArticleDesc, &r.CoverImageUrl, &r.Content, &r.TagID, &r.TagName); err != nil {
+		if err := rows.Scan(&r.ArticleID, &r.ArticleTitle, &r.ArticleDesc, &r.CoverImageUrl, &r.Content, &r.State, &r.PublishAt, &r.TagID, &r.TagName); err != nil {
 			return nil, err
 		}
 
 		articles = append(articles, r)
 	}
 
-	return articles, nil
+	return articles, rows.Err()
 }
 
 // 查询文章列表总数的查询方法
-func (a Article) CountByTagID(db *gorm.DB, tagID uint32) (int, error) {
-	var count int
-	err := db.Table(ArticleTag{}.TableName()+" AS at").
-		Joins("LEFT JOIN `"+Tag{}.TableName()+"` AS t ON at.tag_id = t.id").
-		Joins("LEFT JOIN `"+Article{}.TableName()+"` AS ar ON at.article_id = ar.id").
-		Where("at.`tag_id` = ? AND ar.state = ? AND ar.is_del = ?", tagID, a.State, 0).
-		Count(&count).Error
+func (a Article) CountByTagID(db *gorm.DB, tagID uint32, states []uint8) (int, error) {
+	var count int64
+	err := joinArticleTag(db, tagID, states).Count(&count).Error
 	if err != nil {
 		return 0, err
 	}
 
-	return count, nil
+	return int(count), nil
+}
+
+// 获取最新发布的文章, tagID为0时不限制标签, 按发布时间倒序
+func (a Article) ListLatest(db *gorm.DB, tagID uint32, states []uint8, limit int) ([]*Article, error) {
+	var articles []*Article
+	if tagID > 0 {
+		db = joinArticleTag(db.Select("ar.*"), tagID, states)
+	} else {
+		db = db.Table(Article{}.TableName()+" AS ar").Where("ar.state IN (?) AND ar.is_del = ?", states, 0)
+	}
+
+	err := db.Order("ar.publish_at DESC, ar.id DESC").Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// 通过文章标签关联表查询指定标签下的文章, 表名和字段名不加引号以兼容各个数据库
+// Joins: 指定关联查询的语句
+func joinArticleTag(db *gorm.DB, tagID uint32, states []uint8) *gorm.DB {
+	return db.Table(ArticleTag{}.TableName()+" AS at").
+		Joins("LEFT JOIN "+Tag{}.TableName()+" AS t ON at.tag_id = t.id").
+		Joins("LEFT JOIN "+Article{}.TableName()+" AS ar ON at.article_id = ar.id").
+		Where("at.tag_id = ? AND at.is_del = ? AND ar.state IN (?) AND ar.is_del = ?", tagID, 0, states, 0)
 }
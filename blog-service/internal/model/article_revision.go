@@ -0,0 +1,77 @@
+package model
+
+import (
+	"blog-service/pkg/app"
+
+	"gorm.io/gorm"
+)
+
+// 文章的历史版本, 每次修改后记录一份完整的快照
+type ArticleRevision struct {
+	*Model
+	ArticleID     uint32 `json:"article_id"`      // 文章id
+	Revision      uint32 `json:"revision"`        // 版本号, 同一文章内从1开始递增
+	Title         string `json:"title"`           // 文章标题
+	Desc          string `json:"desc"`            // 文章简述
+	Content       string `json:"content"`         // 文章内容
+	CoverImageUrl string `json:"cover_image_url"` // 封面图地址
+	State         uint8  `json:"state"`           // 记录版本时文章的状态, 同Article.State
+}
+
+func (a ArticleRevision) TableName() string {
+	return "blog_article_revision"
+}
+
+type ArticleRevisionSwagger struct {
+	List  []*ArticleRevision
+	Pager *app.Pager
+}
+
+func (a ArticleRevision) Create(db *gorm.DB) error {
+	return db.Create(&a).Error
+}
+
+// 获取文章的指定版本
+func (a ArticleRevision) Get(db *gorm.DB) (ArticleRevision, error) {
+	var revision ArticleRevision
+	err := db.Where("article_id = ? AND revision = ? AND is_del = ?", a.ArticleID, a.Revision, 0).First(&revision).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return revision, err
+	}
+	return revision, nil
+}
+
+// 获取文章当前最大的版本号, 没有历史版本时返回0
+func (a ArticleRevision) MaxRevision(db *gorm.DB) (uint32, error) {
+	var revisions []uint32
+	err := db.Model(&a).Where("article_id = ? AND is_del = ?", a.ArticleID, 0).
+		Order("revision DESC").Limit(1).Pluck("revision", &revisions).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(revisions) == 0 {
+		return 0, nil
+	}
+	return revisions[0], nil
+}
+
+func (a ArticleRevision) CountByAID(db *gorm.DB) (int, error) {
+	var count int64
+	if err := db.Model(&a).Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// 按版本号倒序获取文章的历史版本
+func (a ArticleRevision) ListByAID(db *gorm.DB, pageOffset, pageSize int) ([]*ArticleRevision, error) {
+	var revisions []*ArticleRevision
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Order("revision DESC").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
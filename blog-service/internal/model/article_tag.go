@@ -1,6 +1,6 @@
 package model
 
-import "github.com/jinzhu/gorm"
+import "gorm.io/gorm"
 
 type ArticleTag struct {
 	*Model
@@ -9,22 +9,21 @@ type ArticleTag struct {
 }
 
 func (a ArticleTag) TableName() string {
-	return "article_tag"
+	return "blog_article_tag"
 }
 
-func (a ArticleTag) GetByAID(db *gorm.DB) (ArticleTag, error) {
-	var articleTag ArticleTag
-	err := db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).First(&articleTag).Error
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return articleTag, err
+func (a ArticleTag) ListByTID(db *gorm.DB) ([]*ArticleTag, error) {
+	var articleTags []*ArticleTag
+	if err := db.Where("tag_id = ? AND is_del = ?", a.TagID, 0).Find(&articleTags).Error; err != nil {
+		return nil, err
 	}
 
-	return articleTag, nil
+	return articleTags, nil
 }
 
-func (a ArticleTag) ListByTID(db *gorm.DB) ([]*ArticleTag, error) {
+func (a ArticleTag) ListByAID(db *gorm.DB) ([]*ArticleTag, error) {
 	var articleTags []*ArticleTag
-	if err := db.Where("tag_id = ? AND is_del = ?", a.TagID, 0).Find(&articleTags).Error; err != nil {
+	if err := db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Find(&articleTags).Error; err != nil {
 		return nil, err
 	}
 
@@ -49,24 +48,26 @@ func (a ArticleTag) Create(db *gorm.DB) error {
 	return nil
 }
 
-func (a ArticleTag) UpdateOne(db *gorm.DB, values interface{}) error {
-	if err := db.Model(&a).Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Limit(1).Updates(values).Error; err != nil {
+func (a ArticleTag) Delete(db *gorm.DB) error {
+	if err := db.Where("id = ? AND is_del = ?", a.Model.ID, 0).Delete(&a).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (a ArticleTag) Delete(db *gorm.DB) error {
-	if err := db.Where("id = ? AND is_del = ?", a.Model.ID, 0).Delete(&a).Error; err != nil {
+// 删除文章与指定标签之间的关联
+func (a ArticleTag) DeleteByTIDs(db *gorm.DB, tagIDs []uint32) error {
+	if err := db.Where("article_id = ? AND tag_id IN (?) AND is_del = ?", a.ArticleID, tagIDs, 0).Delete(&a).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (a ArticleTag) DeleteOne(db *gorm.DB) error {
-	if err := db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Delete(&a).Limit(1).Error; err != nil {
+// 删除文章的全部标签关联
+func (a ArticleTag) DeleteByAID(db *gorm.DB) error {
+	if err := db.Where("article_id = ? AND is_del = ?", a.ArticleID, 0).Delete(&a).Error; err != nil {
 		return err
 	}
 
@@ -0,0 +1,25 @@
+package model
+
+import "gorm.io/gorm"
+
+type Auth struct {
+	*Model
+	AppKey    string `json:"app_key"`    // Key
+	AppSecret string `json:"app_secret"` // SHA-256编码后的Secret
+}
+
+func (a Auth) TableName() string {
+	return "blog_auth"
+}
+
+// 通过AppKey获取认证信息, 由调用方校验Secret
+func (a Auth) Get(db *gorm.DB) (Auth, error) {
+	var auth Auth
+	db = db.Where("app_key = ? AND is_del = ?", a.AppKey, 0)
+	err := db.First(&auth).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return auth, err
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"blog-service/pkg/app"
+
+	"gorm.io/gorm"
+)
+
+// 评论的审核状态
+const (
+	COMMENT_STATE_PENDING  = 0
+	COMMENT_STATE_APPROVED = 1
+	COMMENT_STATE_REJECTED = 2
+)
+
+type Comment struct {
+	*Model
+	ArticleID uint32 `json:"article_id"` // 文章id
+	ParentID  uint32 `json:"parent_id"`  // 回复的评论id, 0为顶层评论
+	Content   string `json:"content"`    // 评论内容
+	State     uint8  `json:"state"`      // 状态：0为待审核、1为已通过、2为已拒绝
+}
+
+func (c Comment) TableName() string {
+	return "blog_comment"
+}
+
+type CommentSwagger struct {
+	List  []*Comment
+	Pager *app.Pager
+}
+
+func (c Comment) Create(db *gorm.DB) (*Comment, error) {
+	if err := db.Create(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// 获取文章下的指定评论
+func (c Comment) Get(db *gorm.DB) (Comment, error) {
+	var comment Comment
+	err := db.Where("id = ? AND article_id = ? AND is_del = ?", c.ID, c.ArticleID, 0).First(&comment).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return comment, err
+	}
+	return comment, nil
+}
+
+// 统计文章下的顶层评论数量
+func (c Comment) CountRoots(db *gorm.DB) (int, error) {
+	var count int64
+	err := db.Model(&c).Where("article_id = ? AND parent_id = ? AND state = ? AND is_del = ?", c.ArticleID, 0, c.State, 0).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// 获取文章下的顶层评论列表
+func (c Comment) ListRoots(db *gorm.DB, pageOffset, pageSize int) ([]*Comment, error) {
+	var comments []*Comment
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := db.Where("article_id = ? AND parent_id = ? AND state = ? AND is_del = ?", c.ArticleID, 0, c.State, 0).
+		Order("id ASC").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// 获取文章下直接回复parentIDs中评论的回复, limit大于0时最多返回limit条
+func (c Comment) ListReplies(db *gorm.DB, parentIDs []uint32, limit int) ([]*Comment, error) {
+	var comments []*Comment
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	err := db.Where("article_id = ? AND parent_id IN (?) AND state = ? AND is_del = ?", c.ArticleID, parentIDs, c.State, 0).
+		Order("id ASC").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// 获取文章下直接回复parentIDs中评论的全部状态的回复ID
+func (c Comment) ListReplyIDs(db *gorm.DB, parentIDs []uint32) ([]uint32, error) {
+	var ids []uint32
+	err := db.Model(&c).Where("article_id = ? AND parent_id IN (?) AND is_del = ?", c.ArticleID, parentIDs, 0).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (c Comment) Update(db *gorm.DB, values interface{}) error {
+	return db.Model(&c).Where("id = ? AND article_id = ? AND is_del = ?", c.ID, c.ArticleID, 0).Updates(values).Error
+}
+
+// 删除文章下的多条评论
+func (c Comment) DeleteByIDs(db *gorm.DB, ids []uint32) error {
+	return db.Where("id IN (?) AND article_id = ? AND is_del = ?", ids, c.ArticleID, 0).Delete(&c).Error
+}
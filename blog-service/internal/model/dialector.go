@@ -0,0 +1,53 @@
+package model
+
+import (
+	"blog-service/pkg/setting"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// 根据DBType选择数据库驱动: mysql、postgres或sqlite
+// sqlite的DBName为数据库文件路径, 也可以使用file::memory:?cache=shared这样的内存数据库
+func newDialector(databaseSetting *setting.DatabaseSettingS) (gorm.Dialector, error) {
+	switch databaseSetting.DBType {
+	case "mysql":
+		return mysql.Open(fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=%s&parseTime=%t&loc=Local",
+			databaseSetting.UserName,
+			databaseSetting.Password,
+			databaseSetting.Host,
+			databaseSetting.DBName,
+			databaseSetting.Charset,
+			databaseSetting.ParseTime,
+		)), nil
+	case "postgres":
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(databaseSetting.UserName, databaseSetting.Password),
+			Host:     databaseSetting.Host,
+			Path:     "/" + databaseSetting.DBName,
+			RawQuery: "sslmode=disable",
+		}
+		return postgres.Open(dsn.String()), nil
+	case "sqlite":
+		if !isSQLiteMemory(databaseSetting.DBName) {
+			if err := os.MkdirAll(filepath.Dir(databaseSetting.DBName), os.ModePerm); err != nil {
+				return nil, err
+			}
+		}
+		return sqlite.Open(databaseSetting.DBName), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", databaseSetting.DBType)
+	}
+}
+
+func isSQLiteMemory(dbName string) bool {
+	return dbName == ":memory:" || strings.Contains(dbName, "mode=memory") || strings.HasPrefix(dbName, "file::memory:")
+}
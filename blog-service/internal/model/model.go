@@ -3,11 +3,10 @@ package model
 import (
 	"blog-service/global"
 	"blog-service/pkg/setting"
-	"fmt"
-	"time"
 
-	"github.com/jinzhu/gorm"
-	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 )
 
 const (
@@ -16,102 +15,69 @@ const (
 )
 
 type Model struct {
-	ID         uint32 `gorm:"primary_key" json:"id"` // 自增长id
-	CreatedBy  string `json:"created_by"`            // 创建人
-	ModifiedBy string `json:"modified_by"`           // 修改人
-	CreatedOn  uint32 `json:"created_on"`            // 创建时间
-	ModifiedOn uint32 `json:"modified_on"`           // 修改时间
-	DeletedOn  uint32 `json:"deleted_on"`            // 删除时间
+	ID         uint32 `gorm:"primaryKey" json:"id"` // 自增长id
+	CreatedBy  string `json:"created_by"`           // 创建人
+	ModifiedBy string `json:"modified_by"`          // 修改人
+	CreatedOn  uint32 `json:"created_on"`           // 创建时间
+	ModifiedOn uint32 `json:"modified_on"`          // 修改时间
+	DeletedOn  uint32 `json:"deleted_on"`           // 删除时间
 	IsDel      uint8  `json:"is_del"`
 }
 
 func NewDBEngine(databaseSetting *setting.DatabaseSettingS) (*gorm.DB, error) {
-	db, err := gorm.Open(databaseSetting.DBType, fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=%s&parseTime=%t&loc=Local",
-		databaseSetting.UserName,
-		databaseSetting.Password,
-		databaseSetting.Host,
-		databaseSetting.DBName,
-		databaseSetting.Charset,
-		databaseSetting.ParseTime,
-	))
-
+	dialector, err := newDialector(databaseSetting)
 	if err != nil {
 		return nil, err
 	}
 
-	if global.ServerSetting.RunMode == "debug" {
-		db.LogMode(true)
+	config := &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+	}
+	if global.ServerSetting != nil && global.ServerSetting.RunMode == "debug" {
+		config.Logger = logger.Default.LogMode(logger.Info)
 	}
 
-	db.SingularTable(true)
+	db, err := gorm.Open(dialector, config)
+	if err != nil {
+		return nil, err
+	}
 
-	db.Callback().Create().Replace("gorm:update_time_stamp", updateTimeStampForCreateCallback)
-	db.Callback().Update().Replace("gorm:update_time_stamp", updateTimeStampForUpdateCallback)
-	db.Callback().Delete().Replace("gorm:delete", deleteCallback)
+	for _, plugin := range []gorm.Plugin{TimestampPlugin{}, SoftDeletePlugin{}, TracingPlugin{}} {
+		if err := db.Use(plugin); err != nil {
+			return nil, err
+		}
+	}
 
-	db.DB().SetMaxIdleConns(databaseSetting.MaxIdleConns)
-	db.DB().SetMaxOpenConns(databaseSetting.MaxOpenConns)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxIdleConns(databaseSetting.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(databaseSetting.MaxOpenConns)
 
 	return db, nil
 }
 
-// 新增行为的回调
-func updateTimeStampForCreateCallback(scope *gorm.Scope) {
-	if !scope.HasError() {
-		nowTime := time.Now().Unix()
-		// 获取当前是否包含CreatedOn字段
-		if createTimeField, ok := scope.FieldByName("CreatedOn"); ok {
-			// 判断该字段是否为空
-			if createTimeField.IsBlank {
-				_ = createTimeField.Set(nowTime)
-			}
+// 多个表中最近一次新增、修改或删除的时间, 包括已删除的记录
+func LastChangedOn(db *gorm.DB, tables ...string) (uint32, error) {
+	var last uint32
+	for _, table := range tables {
+		var row struct {
+			ModifiedOn uint32
+			DeletedOn  uint32
 		}
-
-		// ModifiedOn
-		if modifyTimeField, ok := scope.FieldByName("ModifiedOn"); ok {
-			if modifyTimeField.IsBlank {
-				_ = modifyTimeField.Set(nowTime)
-			}
+		err := db.Table(table).
+			Select("COALESCE(MAX(modified_on), 0) AS modified_on, COALESCE(MAX(deleted_on), 0) AS deleted_on").
+			Scan(&row).Error
+		if err != nil {
+			return 0, err
 		}
-	}
-}
-
-// 更新行为的回调
-func updateTimeStampForUpdateCallback(scope *gorm.Scope) {
-	if _, ok := scope.Get("gorm:update_column"); !ok {
-		_ = scope.SetColumn("ModifiedOn", time.Now().Unix())
-	}
-}
-
-// 删除行为的回调
-func deleteCallback(scope *gorm.Scope) {
-	if !scope.HasError() {
-		var extraOption string
-		if str, ok := scope.Get("gorm:delete_option"); ok {
-			extraOption = fmt.Sprint(str)
+		if row.ModifiedOn > last {
+			last = row.ModifiedOn
 		}
-
-		deleteOnField, hasDeletedField := scope.FieldByName("DeletedOn")
-		isDelField, hasIsDelField := scope.FieldByName("IsDel")
-		if !scope.Search.Unscoped && hasDeletedField && hasIsDelField {
-			now := time.Now().Unix()
-			scope.Raw(fmt.Sprintf(
-				"UPDATE %v SET %v=%v, %v=%v%v%v",
-				scope.QuotedTableName(),
-				scope.Quote(deleteOnField.DBName),
-				scope.AddToVars(now),
-				scope.Quote(isDelField.DBName),
-				scope.AddToVars(1),
-				addExtraSpaceIfExist(scope.CombinedConditionSql()),
-				addExtraSpaceIfExist(extraOption),
-			)).Exec()
+		if row.DeletedOn > last {
+			last = row.DeletedOn
 		}
 	}
-}
-
-func addExtraSpaceIfExist(str string) string {
-	if str != "" {
-		return "  " + str
-	}
-	return ""
+	return last, nil
 }
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// TimestampPlugin 新增时填充CreatedOn和ModifiedOn, 更新时刷新ModifiedOn
+// 通过UpdateColumn/UpdateColumns更新时不修改ModifiedOn
+type TimestampPlugin struct{}
+
+func (p TimestampPlugin) Name() string {
+	return "blog:timestamp"
+}
+
+func (p TimestampPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("blog:update_time_stamp", updateTimeStampForCreateCallback); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("blog:update_time_stamp", updateTimeStampForUpdateCallback)
+}
+
+// 新增行为的回调
+func updateTimeStampForCreateCallback(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	nowTime := time.Now().Unix()
+	for _, name := range []string{"CreatedOn", "ModifiedOn"} {
+		field := db.Statement.Schema.LookUpField(name)
+		if field == nil {
+			continue
+		}
+
+		// 只填充为空的字段
+		switch rv := db.Statement.ReflectValue; rv.Kind() {
+		case reflect.Struct:
+			setIfBlank(field, rv, nowTime)
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				setIfBlank(field, reflect.Indirect(rv.Index(i)), nowTime)
+			}
+		}
+	}
+}
+
+func setIfBlank(field *schema.Field, rv reflect.Value, value interface{}) {
+	if _, isZero := field.ValueOf(rv); isZero {
+		_ = field.Set(rv, value)
+	}
+}
+
+// 更新行为的回调
+func updateTimeStampForUpdateCallback(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.SkipHooks {
+		return
+	}
+
+	if field := db.Statement.Schema.LookUpField("ModifiedOn"); field != nil {
+		db.Statement.SetColumn(field.DBName, time.Now().Unix())
+	}
+}
+
+// SoftDeletePlugin 将包含IsDel和DeletedOn字段的模型的删除改写为更新这两个字段, Unscoped时执行真正的删除
+type SoftDeletePlugin struct{}
+
+func (p SoftDeletePlugin) Name() string {
+	return "blog:soft_delete"
+}
+
+func (p SoftDeletePlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Delete().Replace("gorm:delete", deleteCallback)
+}
+
+// 删除行为的回调
+func deleteCallback(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Unscoped {
+		callbacks.Delete(db)
+		return
+	}
+	deletedOnField := stmt.Schema.LookUpField("DeletedOn")
+	isDelField := stmt.Schema.LookUpField("IsDel")
+	if deletedOnField == nil || isDelField == nil {
+		callbacks.Delete(db)
+		return
+	}
+
+	if stmt.SQL.Len() == 0 {
+		stmt.AddClause(clause.Set{
+			{Column: clause.Column{Name: deletedOnField.DBName}, Value: time.Now().Unix()},
+			{Column: clause.Column{Name: isDelField.DBName}, Value: 1},
+		})
+
+		// 与默认的删除一样, 传入的模型带有主键时以主键作为条件
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.ReflectValue, stmt.Schema.PrimaryFields)
+		column, values := schema.ToQueryValues(stmt.Table, stmt.Schema.PrimaryFieldDBNames, queryValues)
+		if len(values) > 0 {
+			stmt.AddClause(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		}
+
+		stmt.AddClauseIfNotExists(clause.Update{})
+		stmt.Build("UPDATE", "SET", "WHERE")
+	}
+
+	if _, ok := stmt.Clauses["WHERE"]; !db.AllowGlobalUpdate && !ok {
+		_ = db.AddError(gorm.ErrMissingWhereClause)
+		return
+	}
+
+	if !db.DryRun && db.Error == nil {
+		result, err := stmt.ConnPool.ExecContext(stmt.Context, stmt.SQL.String(), stmt.Vars...)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+		db.RowsAffected, _ = result.RowsAffected()
+	}
+}
@@ -3,7 +3,7 @@ package model
 import (
 	"blog-service/pkg/app"
 
-	"github.com/jinzhu/gorm"
+	"gorm.io/gorm"
 )
 
 type Tag struct {
@@ -13,7 +13,7 @@ type Tag struct {
 }
 
 func (a Tag) TableName() string {
-	return "tag"
+	return "blog_tag"
 }
 
 type TagSwagger struct {
@@ -22,9 +22,9 @@ type TagSwagger struct {
 }
 
 func (t Tag) Count(db *gorm.DB) (int, error) {
-	var count int
+	var count int64
 	if t.Name != "" {
-		db = db.Where("state = ?", t.Name)
+		db = db.Where("name = ?", t.Name)
 	}
 
 	db = db.Where("state = ?", t.State)
@@ -33,7 +33,7 @@ func (t Tag) Count(db *gorm.DB) (int, error) {
 		return 0, err
 	}
 
-	return count, nil
+	return int(count), nil
 }
 
 func (t Tag) List(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
@@ -56,6 +56,41 @@ func (t Tag) List(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
 	return tags, nil
 }
 
+// 获取全部状态的标签列表, 按ID升序
+func (t Tag) ListAll(db *gorm.DB, pageOffset, pageSize int) ([]*Tag, error) {
+	var tags []*Tag
+	if pageOffset >= 0 && pageSize > 0 {
+		db = db.Offset(pageOffset).Limit(pageSize)
+	}
+	err := db.Where("is_del = ?", 0).Order("id ASC").Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// 通过名称获取标签, 不限制状态
+func (t Tag) GetByName(db *gorm.DB) (Tag, error) {
+	var tag Tag
+	err := db.Where("name = ? AND is_del = ?", t.Name, 0).First(&tag).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return tag, err
+	}
+
+	return tag, nil
+}
+
+// 通过ID列表获取标签
+func (t Tag) ListByIDs(db *gorm.DB, ids []uint32) ([]*Tag, error) {
+	var tags []*Tag
+	err := db.Where("id IN (?) AND state = ? AND is_del = ?", ids, t.State, 0).Find(&tags).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
 func (t Tag) Get(db *gorm.DB) (Tag, error) {
 	var tag Tag
 	err := db.Where("id = ? AND is_del = ? AND state = ?", t.ID, 0, t.State).First(&tag).Error
@@ -67,8 +102,11 @@ func (t Tag) Get(db *gorm.DB) (Tag, error) {
 }
 
 // 创建数据
-func (t Tag) Create(db *gorm.DB) error {
-	return db.Create(&t).Error
+func (t Tag) Create(db *gorm.DB) (*Tag, error) {
+	if err := db.Create(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
 }
 
 // 更新传入的字段
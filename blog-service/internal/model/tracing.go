@@ -0,0 +1,81 @@
+package model
+
+import (
+	"blog-service/global"
+	"errors"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanGormKey = "blog:span"
+
+// TracingPlugin 为每条SQL创建一个子span, 父span来自db.WithContext传入的ctx
+type TracingPlugin struct{}
+
+func (p TracingPlugin) Name() string {
+	return "blog:tracing"
+}
+
+func (p TracingPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	registers := []error{
+		callback.Create().Before("gorm:begin_transaction").Register("blog:before_create", newBeforeTracingCallback("gorm:create")),
+		callback.Create().After("gorm:commit_or_rollback_transaction").Register("blog:after_create", afterTracingCallback),
+		callback.Update().Before("gorm:begin_transaction").Register("blog:before_update", newBeforeTracingCallback("gorm:update")),
+		callback.Update().After("gorm:commit_or_rollback_transaction").Register("blog:after_update", afterTracingCallback),
+		callback.Delete().Before("gorm:begin_transaction").Register("blog:before_delete", newBeforeTracingCallback("gorm:delete")),
+		callback.Delete().After("gorm:commit_or_rollback_transaction").Register("blog:after_delete", afterTracingCallback),
+		callback.Query().Before("gorm:query").Register("blog:before_query", newBeforeTracingCallback("gorm:query")),
+		callback.Query().After("gorm:after_query").Register("blog:after_query", afterTracingCallback),
+		callback.Row().Before("gorm:row").Register("blog:before_row", newBeforeTracingCallback("gorm:row")),
+		callback.Row().After("gorm:row").Register("blog:after_row", afterTracingCallback),
+		callback.Raw().Before("gorm:raw").Register("blog:before_raw", newBeforeTracingCallback("gorm:raw")),
+		callback.Raw().After("gorm:raw").Register("blog:after_raw", afterTracingCallback),
+	}
+	for _, err := range registers {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newBeforeTracingCallback(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || global.Tracer == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return
+		}
+
+		_, span := global.Tracer.Start(ctx, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(db.Dialector.Name()),
+				semconv.DBSQLTableKey.String(db.Statement.Table),
+			),
+		)
+		db.InstanceSet(spanGormKey, span)
+	}
+}
+
+// 结束SQL对应的span, 记录执行的语句及错误
+func afterTracingCallback(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanGormKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(semconv.DBStatementKey.String(db.Statement.SQL.String()))
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 获取Token
+// @Produce json
+// @Param app_key body string true "Key"
+// @Param app_secret body string true "Secret"
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 401 {object} errcode.Error "鉴权失败"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /auth [post]
+func GetAuth(c *gin.Context) {
+	param := service.AuthRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.CheckAuth(&param)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.CheckAuth err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedAuthNotExist)
+		return
+	}
+
+	token, err := app.GenerateToken(param.AppKey)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("app.GenerateToken err: %v", err)
+		response.ToErrorResponse(errcode.UnauthorizedTokenGenerate)
+		return
+	}
+
+	response.ToResponse(gin.H{
+		"token": token,
+	})
+}
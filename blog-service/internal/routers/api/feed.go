@@ -0,0 +1,99 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/feed"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Feed struct{}
+
+func NewFeed() Feed {
+	return Feed{}
+}
+
+// @Summary RSS订阅源
+// @Produce xml
+// @Param tag_id query int false "标签ID"
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /feed/rss [get]
+func (f Feed) RSS(c *gin.Context) {
+	f.render(c, "application/rss+xml; charset=utf-8", feed.ToRSS)
+}
+
+// @Summary Atom订阅源
+// @Produce xml
+// @Param tag_id query int false "标签ID"
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /feed/atom [get]
+func (f Feed) Atom(c *gin.Context) {
+	f.render(c, "application/atom+xml; charset=utf-8", feed.ToAtom)
+}
+
+// @Summary 站点地图
+// @Produce xml
+// @Success 200 {string} string "成功"
+// @Success 304 {string} string "未修改"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /sitemap.xml [get]
+func (f Feed) Sitemap(c *gin.Context) {
+	response := app.NewResponse(c)
+	svc := service.New(c.Request.Context())
+	urls, lastModified, err := svc.GetSitemap()
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetSitemap err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+
+	body, err := feed.ToSitemap(urls)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("feed.ToSitemap err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+
+	response.ToConditionalResponse("application/xml; charset=utf-8", body, lastModified)
+}
+
+func (f Feed) render(c *gin.Context, contentType string, encode func(*feed.Feed) ([]byte, error)) {
+	param := service.FeedRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	articleFeed, lastModified, err := svc.GetFeed(&param)
+	if err == service.ErrTagNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetFeed err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+
+	body, err := encode(articleFeed)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("encode feed err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetFeedFail)
+		return
+	}
+
+	response.ToConditionalResponse(contentType, body, lastModified)
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 健康检查
+// @Produce json
+// @Success 200 {string} string "成功"
+// @Failure 503 {object} errcode.Error "服务不可用"
+// @Router /healthz [get]
+func Healthz(c *gin.Context) {
+	response := app.NewResponse(c)
+	if atomic.LoadInt32(&global.ServerReady) != 1 {
+		response.ToErrorResponse(errcode.ServiceUnavailable)
+		return
+	}
+
+	response.ToResponse(gin.H{"status": "ok"})
+}
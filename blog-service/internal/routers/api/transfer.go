@@ -0,0 +1,120 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/errcode"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 导入数据的大小上限
+const maxImportSize = 64 << 20
+
+type Transfer struct{}
+
+func NewTransfer() Transfer {
+	return Transfer{}
+}
+
+// @Summary 导出全部标签、文章及其标签关联
+// @Produce json,text/csv
+// @Param format query string false "导出格式" Enums(json, csv) default(json)
+// @Success 200 {object} service.TransferData "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /admin/export [get]
+func (t Transfer) Export(c *gin.Context) {
+	param := service.ExportRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if param.Format == service.TRANSFER_FORMAT_CSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	filename := fmt.Sprintf("blog-export-%s.%s", time.Now().Format("20060102150405"), param.Format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	svc := service.New(c.Request.Context())
+	err := svc.Export(param.Format, c.Writer)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.Export err: %v", err)
+		// 已经开始写出数据时无法再返回错误信息, 只能中断响应
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		response.ToErrorResponse(errcode.ErrorExportFail)
+	}
+}
+
+// @Summary 导入标签、文章及其标签关联, 标签按名称、文章按标题匹配已有的数据
+// @Accept multipart/form-data,json,text/csv
+// @Produce json
+// @Param format query string false "导入格式" Enums(json, csv) default(json)
+// @Param dry_run query bool false "试运行, 只返回将要执行的变更和冲突, 不写入数据"
+// @Param modified_by query string true "修改者"
+// @Param file formData file false "导入的文件, 不使用表单上传时直接在请求体中传入"
+// @Success 200 {object} service.ImportReport "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /admin/import [post]
+func (t Transfer) Import(c *gin.Context) {
+	param := service.ImportRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindQueryAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	var r io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			global.Logger.WithTrace(c).Errorf("fileHeader.Open err: %v", err)
+			response.ToErrorResponse(errcode.ErrorImportFail)
+			return
+		}
+		defer file.Close()
+		r = file
+	}
+
+	svc := service.New(c.Request.Context())
+	report, err := svc.Import(&param, r)
+	if errors.Is(err, service.ErrInvalidTransferData) {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.Import err: %v", err)
+		response.ToErrorResponse(errcode.ErrorImportFail)
+		return
+	}
+
+	response.ToResponse(report)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+	"blog-service/pkg/upload"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Upload struct{}
+
+func NewUpload() Upload {
+	return Upload{}
+}
+
+// @Summary 上传文件
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "文件"
+// @Param type formData int true "文件类型：1为图片、2为文档"
+// @Success 200 {object} service.FileInfo "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /upload/file [post]
+func (u Upload) UploadFile(c *gin.Context) {
+	response := app.NewResponse(c)
+	// 在解析表单之前限制请求体的大小, 避免超大的文件被完整读取并写入临时文件
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, upload.MaxRequestSize())
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+
+	fileType := convert.StrTo(c.PostForm("type")).MustInt()
+	if fileHeader == nil || fileType <= 0 {
+		response.ToErrorResponse(errcode.InvalidParams)
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	fileInfo, err := svc.UploadFile(upload.FileType(fileType), fileHeader)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.UploadFile err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUploadFileFail.WithDetails(err.Error()))
+		return
+	}
+
+	response.ToResponse(gin.H{
+		"file_access_url": fileInfo.AccessUrl,
+	})
+}
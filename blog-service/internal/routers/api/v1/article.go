@@ -1,22 +1,16 @@
 package v1
 
 import (
-	"blog-service/internal/model"
+	"blog-service/global"
+	"blog-service/internal/service"
 	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
 	"blog-service/pkg/errcode"
 
 	"github.com/gin-gonic/gin"
 )
 
-type Article struct {
-	ID             uint32     `json:"id"`
-	Title          string     `json:"title"`
-	Desc           string     `json:"desc"`
-	Content        string     `json:"content"`
-	ConverImageUrl string     `json:"conver_image_url"`
-	State          uint8      `json:"state"`
-	Tag            *model.Tag `json:"tag"`
-}
+type Article struct{}
 
 func NewArticle() Article {
 	return Article{}
@@ -25,55 +19,195 @@ func NewArticle() Article {
 // @Summary 获取单个文章
 // @Produce json
 // @Param id path int true "文章ID"
+// @Param format query string false "内容格式, html时返回渲染后的content_html" Enums(raw, html) default(raw)
 // @Success 200 {object} model.Article "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "找不到"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [get]
 func (a Article) Get(c *gin.Context) {
-	app.NewResponse(c).ToErrorResponse(errcode.ServerError)
+	param := service.ArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	article, err := svc.GetArticle(&param)
+	if err == service.ErrArticleNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticleFail)
+		return
+	}
+
+	response.ToResponse(article)
 }
 
 // @Summary 获取多个文章
 // @Produce json
-// @Param name query string false "文章名称"
-// @Param tag_id query int false "标签ID"
-// @Param state query int false "状态"
+// @Param tag_id query int true "标签ID"
+// @Param state query []int false "状态, 可传入多个" Enums(0, 1, 2, 3) default(1)
 // @Param page query int false "页码"
+// @Param cursor query string false "游标, 传入时按游标分页并忽略页码, 首页传空值, 之后传上一页返回的next_cursor"
 // @Param page_size query int false "每页数量"
 // @Success 200 {object} model.ArticleSwagger "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles [get]
-func (a Article) List(c *gin.Context) {}
+func (a Article) List(c *gin.Context) {
+	param := service.ArticleListRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	cursor, useCursor, err := app.GetCursor(c)
+	if err != nil {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c), UseCursor: useCursor, Cursor: cursor}
+	articles, totalRows, err := svc.GetArticleList(&param, &pager)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetArticleList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticlesFail)
+		return
+	}
+
+	// 游标分页时不使用页码
+	if useCursor {
+		pager.Page = 0
+	}
+	pager.TotalRows = totalRows
+	response.ToPagerResponseList(articles, &pager)
+}
+
+// @Summary 搜索文章
+// @Produce json
+// @Param q query string true "关键字" maxlength(100)
+// @Param state query int false "状态" Enums(0, 1, 2, 3) default(1)
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.ArticleSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/search [get]
+func (a Article) Search(c *gin.Context) {
+	param := service.SearchArticleRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	articles, totalRows, err := svc.SearchArticles(&param, &pager)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.SearchArticles err: %v", err)
+		response.ToErrorResponse(errcode.ErrorSearchArticleFail)
+		return
+	}
+
+	response.ToResponseList(articles, totalRows)
+}
 
 // @Summary 创建文章
 // @Produce json
-// @Param tag_id body string true "标签ID"
+// @Param tag_ids body []int true "标签ID列表"
 // @Param title body string true "文章标题"
 // @Param desc body string false "文章简述"
 // @Param cover_image_url body string true "封面图片地址"
 // @Param content body string true "文章内容"
 // @Param created_by body int true "创建者"
-// @Param state body int false "状态"
+// @Param state body int false "状态" Enums(0, 1, 2, 3) default(1)
+// @Param publish_at body int false "发布时间, 定时发布时必填"
 // @Success 200 {object} model.Article "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles [post]
-func (a Article) Create(c *gin.Context) {}
+func (a Article) Create(c *gin.Context) {
+	param := service.CreateArticleRequest{}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.CreateArticle(&param)
+	if err == service.ErrInvalidPublishAt || err == service.ErrInvalidTagIDs {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.CreateArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCreateArticleFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
 
 // @Summary 更新文章
 // @Produce json
-// @Param tag_id body string false "标签ID"
+// @Param id path int true "文章ID"
+// @Param tag_ids body []int false "标签ID列表, 传入时覆盖原有的标签关联"
 // @Param title body string false "文章标题"
 // @Param desc body string false "文章简述"
 // @Param cover_image_url body string false "封面图片地址"
 // @Param content body string false "文章内容"
 // @Param modified_by body string true "修改者"
+// @Param state body int false "状态, 不传时保持不变" Enums(0, 1, 2, 3)
+// @Param publish_at body int false "发布时间, 改为定时发布时必填"
 // @Success 200 {object} model.Article "成功"
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [put]
-func (a Article) Update(c *gin.Context) {}
+func (a Article) Update(c *gin.Context) {
+	param := service.UpdateArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateArticle(&param)
+	if err == service.ErrInvalidPublishAt || err == service.ErrInvalidTagIDs {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err == service.ErrArticleNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.UpdateArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateArticleFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
 
 // @Summary 删除文章
 // @Produce  json
@@ -82,4 +216,23 @@ func (a Article) Update(c *gin.Context) {}
 // @Failure 400 {object} errcode.Error "请求错误"
 // @Failure 500 {object} errcode.Error "内部错误"
 // @Router /api/v1/articles/{id} [delete]
-func (a Article) Delete(c *gin.Context) {}
+func (a Article) Delete(c *gin.Context) {
+	param := service.DeleteArticleRequest{ID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.DeleteArticle(&param)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.DeleteArticle err: %v", err)
+		response.ToErrorResponse(errcode.ErrorDeleteArticleFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
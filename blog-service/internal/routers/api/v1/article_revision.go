@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArticleRevision struct{}
+
+func NewArticleRevision() ArticleRevision {
+	return ArticleRevision{}
+}
+
+// @Summary 获取文章的历史版本列表
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.ArticleRevisionSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions [get]
+func (r ArticleRevision) List(c *gin.Context) {
+	param := service.ArticleRevisionListRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	revisions, totalRows, err := svc.GetArticleRevisionList(&param, &pager)
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetArticleRevisionList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticleRevisionListFail)
+		return
+	}
+
+	response.ToResponseList(revisions, totalRows)
+}
+
+// @Summary 对比历史版本与文章当前内容
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param revision path int true "版本号"
+// @Success 200 {object} service.ArticleRevisionDiff "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions/{revision}/diff [get]
+func (r ArticleRevision) Diff(c *gin.Context) {
+	param := service.ArticleRevisionRequest{
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+		Revision:  convert.StrTo(c.Param("revision")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	diff, err := svc.GetArticleRevisionDiff(&param)
+	if err == service.ErrArticleNotFound || err == service.ErrArticleRevisionNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetArticleRevisionDiff err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetArticleRevisionDiffFail)
+		return
+	}
+
+	response.ToResponse(diff)
+}
+
+// @Summary 恢复文章到指定历史版本
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param revision path int true "版本号"
+// @Param modified_by body string true "修改者" minlength(2) maxlength(100)
+// @Param state body int false "状态, 不传时保持文章当前的状态" Enums(0, 1, 2, 3)
+// @Param publish_at body int false "发布时间, 改为定时发布时必填"
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/revisions/{revision}/restore [post]
+func (r ArticleRevision) Restore(c *gin.Context) {
+	param := service.RestoreArticleRevisionRequest{
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+		Revision:  convert.StrTo(c.Param("revision")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.RestoreArticleRevision(&param)
+	if err == service.ErrArticleNotFound || err == service.ErrArticleRevisionNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err == service.ErrInvalidPublishAt {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(err.Error()))
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.RestoreArticleRevision err: %v", err)
+		response.ToErrorResponse(errcode.ErrorRestoreArticleRevisionFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/app"
+	"blog-service/pkg/convert"
+	"blog-service/pkg/errcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Comment struct{}
+
+func NewComment() Comment {
+	return Comment{}
+}
+
+// @Summary 获取文章的评论列表
+// @Description 只有审核员可以查看待审核和已拒绝的评论, 每条顶层评论下最多展开5层回复, 每页最多返回200条回复
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param state query int false "状态" Enums(0, 1, 2) default(1)
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} model.CommentSwagger "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments [get]
+func (cm Comment) List(c *gin.Context) {
+	param := service.CommentListRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	param.Moderator = app.IsModerator(c)
+	svc := service.New(c.Request.Context())
+	pager := app.Pager{Page: app.GetPage(c), PageSize: app.GetPageSize(c)}
+	comments, totalRows, err := svc.GetCommentList(&param, &pager)
+	if err == service.ErrCommentForbidden {
+		response.ToErrorResponse(errcode.Forbidden)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.GetCommentList err: %v", err)
+		response.ToErrorResponse(errcode.ErrorGetCommentListFail)
+		return
+	}
+
+	response.ToResponseList(comments, totalRows)
+}
+
+// @Summary 发表评论
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param parent_id body int false "回复的评论ID"
+// @Param content body string true "评论内容" maxlength(1000)
+// @Param created_by body string true "创建者" minlength(2) maxlength(100)
+// @Success 200 {object} model.Comment "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments [post]
+func (cm Comment) Create(c *gin.Context) {
+	param := service.CreateCommentRequest{ArticleID: convert.StrTo(c.Param("id")).MustUInt32()}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	comment, err := svc.CreateComment(&param)
+	if err == service.ErrArticleNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err == service.ErrCommentNotFound {
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails("parent_id对应的评论不存在"))
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.CreateComment err: %v", err)
+		response.ToErrorResponse(errcode.ErrorCreateCommentFail)
+		return
+	}
+
+	response.ToResponse(comment)
+}
+
+// @Summary 审核评论
+// @Description 只有审核员可以审核评论
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param comment_id path int true "评论ID"
+// @Param state body int true "状态" Enums(0, 1, 2)
+// @Param modified_by body string true "修改者" minlength(2) maxlength(100)
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments/{comment_id}/state [patch]
+func (cm Comment) UpdateState(c *gin.Context) {
+	param := service.UpdateCommentStateRequest{
+		ID:        convert.StrTo(c.Param("comment_id")).MustUInt32(),
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.UpdateCommentState(&param)
+	if err == service.ErrCommentNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.UpdateCommentState err: %v", err)
+		response.ToErrorResponse(errcode.ErrorUpdateCommentFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
+
+// @Summary 删除评论
+// @Description 只有审核员可以删除评论, 评论下的回复会一起删除
+// @Produce json
+// @Param id path int true "文章ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {string} string "成功"
+// @Failure 400 {object} errcode.Error "请求错误"
+// @Failure 403 {object} errcode.Error "没有权限"
+// @Failure 404 {object} errcode.Error "找不到"
+// @Failure 500 {object} errcode.Error "内部错误"
+// @Router /api/v1/articles/{id}/comments/{comment_id} [delete]
+func (cm Comment) Delete(c *gin.Context) {
+	param := service.DeleteCommentRequest{
+		ID:        convert.StrTo(c.Param("comment_id")).MustUInt32(),
+		ArticleID: convert.StrTo(c.Param("id")).MustUInt32(),
+	}
+	response := app.NewResponse(c)
+	valid, errs := app.BindAndValid(c, &param)
+	if !valid {
+		global.Logger.WithTrace(c).Errorf("app.BindAndValid errs: %v", errs)
+		response.ToErrorResponse(errcode.InvalidParams.WithDetails(errs.Errors()...))
+		return
+	}
+
+	svc := service.New(c.Request.Context())
+	err := svc.DeleteComment(&param)
+	if err == service.ErrCommentNotFound {
+		response.ToErrorResponse(errcode.NotFound)
+		return
+	}
+	if err != nil {
+		global.Logger.WithTrace(c).Errorf("svc.DeleteComment err: %v", err)
+		response.ToErrorResponse(errcode.ErrorDeleteCommentFail)
+		return
+	}
+
+	response.ToResponse(gin.H{})
+}
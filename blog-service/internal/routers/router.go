@@ -1,24 +1,62 @@
 package routers
 
 import (
+	"blog-service/global"
 	"blog-service/internal/middleware"
+	"blog-service/internal/routers/api"
 	v1 "blog-service/internal/routers/api/v1"
+	"blog-service/pkg/limiter"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "blog-service/docs"
+
 	"github.com/gin-gonic/gin"
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 )
 
 func NewRouter() *gin.Engine {
 	r := gin.New()
-	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
+	if global.ServerSetting.RunMode == "debug" {
+		r.Use(gin.Logger())
+	}
+	// 最先注册, 捕获之后所有中间件和处理函数中的panic
+	r.Use(middleware.Recovery())
+	r.Use(middleware.Tracing())
+	// 需要在其他可能返回错误的中间件之前确定语言
 	r.Use(middleware.Translations())
+	r.Use(middleware.AccessLog())
+	r.Use(middleware.RateLimiter(newMethodLimiter()))
+	r.Use(middleware.ContextTimeout(global.AppSetting.DefaultContextTimeout))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/healthz", api.Healthz)
+	r.POST("/auth", api.GetAuth)
+
+	upload := api.NewUpload()
+	r.POST("/upload/file", middleware.JWT(), upload.UploadFile)
+	// 不允许列出目录内容
+	r.Static("/static", global.AppSetting.UploadSavePath)
+
+	// 订阅源和站点地图不需要鉴权
+	feed := api.NewFeed()
+	r.GET("/feed/rss", feed.RSS)
+	r.GET("/feed/atom", feed.Atom)
+	r.GET("/sitemap.xml", feed.Sitemap)
+
+	// 数据导入导出
+	transfer := api.NewTransfer()
+	admin := r.Group("/admin")
+	admin.Use(middleware.JWT())
+	{
+		admin.GET("/export", transfer.Export)
+		admin.POST("/import", transfer.Import)
+	}
+
 	apiv1 := r.Group("/api/v1")
+	apiv1.Use(middleware.JWT())
 	tag := v1.NewTag()
 	article := v1.NewArticle()
+	comment := v1.NewComment()
+	revision := v1.NewArticleRevision()
 
 	{
 		// 标签管理
@@ -37,15 +75,50 @@ func NewRouter() *gin.Engine {
 		// 新增文章
 		apiv1.POST("/articles", article.Create)
 		//删除指定文章
-		apiv1.DELETE("/articles/:id", article.Detele)
+		apiv1.DELETE("/articles/:id", article.Delete)
 		//更新指定文章
 		apiv1.PUT("/articles/:id", article.Update)
 		// 更新指定文章状态
 		apiv1.PATCH("/articles/:id/state", article.Update)
+		// 搜索文章
+		apiv1.GET("/articles/search", article.Search)
 		// 获取指定文章
 		apiv1.GET("/articles/:id", article.Get)
 		// 获取文章列表
 		apiv1.GET("/articles", article.List)
+
+		// 评论管理
+		// 发表评论
+		apiv1.POST("/articles/:id/comments", comment.Create)
+		// 获取文章的评论列表
+		apiv1.GET("/articles/:id/comments", comment.List)
+		// 删除指定评论
+		apiv1.DELETE("/articles/:id/comments/:comment_id", middleware.Moderator(), comment.Delete)
+		// 审核指定评论
+		apiv1.PATCH("/articles/:id/comments/:comment_id/state", middleware.Moderator(), comment.UpdateState)
+
+		// 文章历史版本
+		// 获取文章的历史版本列表
+		apiv1.GET("/articles/:id/revisions", revision.List)
+		// 对比历史版本与当前内容
+		apiv1.GET("/articles/:id/revisions/:revision/diff", revision.Diff)
+		// 恢复到指定历史版本
+		apiv1.POST("/articles/:id/revisions/:revision/restore", revision.Restore)
 	}
 	return r
 }
+
+func newMethodLimiter() limiter.LimiterIface {
+	l := limiter.NewMethodLimiter()
+	for _, rule := range global.LimiterSetting.Rules {
+		l.AddBuckets(limiter.LimiterBucketRule{
+			Key:          rule.Key,
+			FillInterval: rule.FillInterval,
+			Capacity:     rule.Capacity,
+			Quantum:      rule.Quantum,
+			KeyBy:        rule.KeyBy,
+		})
+	}
+
+	return l
+}
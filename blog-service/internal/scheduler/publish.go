@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"blog-service/global"
+	"blog-service/internal/service"
+	"blog-service/pkg/setting"
+	"context"
+	"sync"
+	"time"
+)
+
+// 未配置时使用的默认值
+const (
+	defaultPublishInterval  = 30 * time.Second
+	defaultPublishBatchSize = 100
+)
+
+// PublishScheduler 定期将已到发布时间的定时发布文章改为已发布
+// 待发布的文章保存在数据库中, 重启后启动时会先处理一次积压的文章;
+// 多个实例同时运行时依靠行锁保证每篇文章只会被发布一次
+type PublishScheduler struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewPublishScheduler() *PublishScheduler {
+	return &PublishScheduler{stop: make(chan struct{})}
+}
+
+func (s *PublishScheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			s.publish()
+
+			setting.RLock()
+			interval := global.SchedulerSetting.PublishInterval
+			setting.RUnlock()
+			if interval <= 0 {
+				interval = defaultPublishInterval
+			}
+
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+}
+
+// Stop 等待正在执行的发布任务完成后返回
+func (s *PublishScheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// 每批处理完后立即检查下一批, 直到没有已到期的文章
+func (s *PublishScheduler) publish() {
+	setting.RLock()
+	batchSize := global.SchedulerSetting.PublishBatchSize
+	setting.RUnlock()
+	if batchSize <= 0 {
+		batchSize = defaultPublishBatchSize
+	}
+
+	ctx := context.Background()
+	svc := service.New(ctx)
+	for {
+		count, err := svc.PublishDueArticles(batchSize)
+		if err != nil {
+			global.Logger.Errorf("svc.PublishDueArticles err: %v", err)
+			return
+		}
+		if count > 0 {
+			global.Logger.Infof("published %d scheduled articles", count)
+		}
+		if count < batchSize {
+			return
+		}
+
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+	}
+}
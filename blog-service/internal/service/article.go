@@ -1,40 +1,56 @@
 package service
 
 import (
+	"blog-service/global"
 	"blog-service/internal/dao"
 	"blog-service/internal/model"
 	"blog-service/pkg/app"
+	"blog-service/pkg/markdown"
+	"errors"
+	"time"
 )
 
+var (
+	ErrArticleNotFound  = errors.New("article not found")
+	ErrInvalidPublishAt = errors.New("publish_at must be a future time for scheduled articles")
+	ErrInvalidTagIDs    = errors.New("tag_ids contains tags that do not exist or have been deleted")
+)
+
+// format为html时额外返回渲染后的content_html
 type ArticleRequest struct {
-	ID    uint32 `form:"id" binding:"required,gte=1"`
-	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	ID     uint32 `form:"id" binding:"required,gte=1"`
+	State  uint8  `form:"state,default=1" binding:"oneof=0 1 2 3"`
+	Format string `form:"format,default=raw" binding:"oneof=raw html"`
 }
 
+// 可以同时传入多个state筛选不同状态的文章, 如state=0&state=2
 type ArticleListRequest struct {
-	TagID uint32 `form:"tag_id" binding:"gte=1"`
-	State uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	TagID  uint32  `form:"tag_id" binding:"gte=1"`
+	States []uint8 `form:"state,default=1" binding:"min=1,dive,oneof=0 1 2 3"`
 }
 
 type CreateArticleRequest struct {
-	TagID         uint32 `form:"tag_id" binding:"required,gte=1"`
-	Title         string `form:"title" binding:"required,min=2,max=100"`
-	Desc          string `form:"desc" binding:"required,min=2,max=255"`
-	Content       string `form:"content" binding:"required,min=2,max=4294967295"`
-	CoverImageUrl string `form:"cover_image_url" binding:"required,url"`
-	CreatedBy     string `form:"created_by" binding:"required,min=2,max=100"`
-	State         uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"required,min=1,dive,gte=1"`
+	Title         string   `form:"title" binding:"required,min=2,max=100"`
+	Desc          string   `form:"desc" binding:"required,min=2,max=255"`
+	Content       string   `form:"content" binding:"required,min=2,max=4294967295"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"required,url"`
+	CreatedBy     string   `form:"created_by" binding:"required,min=2,max=100"`
+	State         uint8    `form:"state,default=1" binding:"oneof=0 1 2 3"`
+	PublishAt     uint32   `form:"publish_at" binding:"required_if=State 2"`
 }
 
 type UpdateArticleRequest struct {
-	ID            uint32 `form:"id" binding:"required,gte=1"`
-	TagID         uint32 `form:"tag_id" binding:"required,gte=1"`
-	Title         string `form:"title" binding:"min=2,max=100"`
-	Desc          string `form:"desc" binding:"min=2,max=255"`
-	Content       string `form:"content" binding:"min=2,max=4294967295"`
-	CoverImageUrl string `form:"cover_image_url" binding:"url"`
-	ModifiedBy    string `form:"modified_by" binding:"required,min=2,max=100"`
-	State         uint8  `form:"state,default=1" binding:"oneof=0 1"`
+	ID            uint32   `form:"id" binding:"required,gte=1"`
+	TagIDs        []uint32 `form:"tag_ids" binding:"omitempty,dive,gte=1"`
+	Title         string   `form:"title" binding:"omitempty,min=2,max=100"`
+	Desc          string   `form:"desc" binding:"omitempty,min=2,max=255"`
+	Content       string   `form:"content" binding:"omitempty,min=2,max=4294967295"`
+	CoverImageUrl string   `form:"cover_image_url" binding:"omitempty,url"`
+	ModifiedBy    string   `form:"modified_by" binding:"required,min=2,max=100"`
+	// 未传入state时保持文章原来的状态
+	State     *uint8 `form:"state" binding:"omitempty,oneof=0 1 2 3"`
+	PublishAt uint32 `form:"publish_at"`
 }
 
 type DeleteArticleRequest struct {
@@ -42,29 +58,57 @@ type DeleteArticleRequest struct {
 }
 
 type Article struct {
-	ID            uint32     `json:"id"`
-	Title         string     `json:"title"`
-	Desc          string     `json:"desc"`
-	Content       string     `json:"content"`
-	CoverImageUrl string     `json:"cover_image_url"`
-	State         uint8      `json:"state"`
-	Tag           *model.Tag `json:"tag"`
+	ID            uint32       `json:"id"`
+	Title         string       `json:"title"`
+	Desc          string       `json:"desc"`
+	Content       string       `json:"content"`
+	ContentHTML   string       `json:"content_html,omitempty"`
+	CoverImageUrl string       `json:"cover_image_url"`
+	State         uint8        `json:"state"`
+	PublishAt     uint32       `json:"publish_at"`
+	Tags          []*model.Tag `json:"tags"`
+}
+
+// 缓存的文章及其标签, 文章不存在时Article为nil
+type articleCacheEntry struct {
+	Article *model.Article `json:"article"`
+	Tags    []*model.Tag   `json:"tags"`
 }
 
 func (svc *Service) GetArticle(param *ArticleRequest) (*Article, error) {
-	article, err := svc.dao.GetArticle(param.ID, param.State)
+	var entry articleCacheEntry
+	ttl, _ := cacheTTL()
+	err := svc.cacheLoad(svc.articleCacheKey(param.ID), ttl, &entry, func(svc *Service) (interface{}, error) {
+		article, err := svc.dao.GetArticleByID(param.ID)
+		if err != nil {
+			return nil, err
+		}
+		if article.Model == nil || article.ID == 0 {
+			return &articleCacheEntry{}, nil
+		}
+
+		tags, err := svc.getArticleTags([]uint32{article.ID})
+		if err != nil {
+			return nil, err
+		}
+		return &articleCacheEntry{Article: &article, Tags: tags[article.ID]}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	articleTag, err := svc.dao.GetArticleTagByAID(article.ID)
-	if err != nil {
-		return nil, err
+	// 缓存中保存全部状态的文章, 在这里按请求的状态过滤
+	article := entry.Article
+	if article == nil || article.State != param.State {
+		return nil, ErrArticleNotFound
 	}
 
-	tag, err := svc.dao.GetTag(articleTag.TagID, model.STATE_OPEN)
-	if err != nil {
-		return nil, err
+	var contentHTML string
+	if param.Format == "html" {
+		contentHTML, err = svc.getArticleContentHTML(article)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &Article{
@@ -72,19 +116,39 @@ func (svc *Service) GetArticle(param *ArticleRequest) (*Article, error) {
 		Title:         article.Title,
 		Desc:          article.Desc,
 		Content:       article.Content,
+		ContentHTML:   contentHTML,
 		CoverImageUrl: article.CoverImageUrl,
 		State:         article.State,
-		Tag:           &tag,
+		PublishAt:     article.PublishAt,
+		Tags:          entry.Tags,
 	}, nil
 }
 
 func (svc *Service) GetArticleList(param *ArticleListRequest, pager *app.Pager) ([]*Article, int, error) {
-	articleCount, err := svc.dao.CountArticleListByTagID(param.TagID, param.State)
+	articleCount, err := svc.dao.CountArticleListByTagID(param.TagID, param.States)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	articles, err := svc.dao.GetArticleListByTagID(param.TagID, param.State, pager.Page, pager.PageSize)
+	var articles []*model.ArticleRow
+	if pager.UseCursor {
+		articles, err = svc.dao.GetArticleListByTagIDBefore(param.TagID, param.States, pager.Cursor, pager.PageSize)
+	} else {
+		articles, err = svc.dao.GetArticleListByTagID(param.TagID, param.States, pager.Page, pager.PageSize)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	// 取满一页时才可能还有下一页
+	if pager.UseCursor && len(articles) == pager.PageSize {
+		pager.NextCursor = app.EncodeCursor(articles[len(articles)-1].ArticleID)
+	}
+
+	articleIDs := make([]uint32, 0, len(articles))
+	for _, article := range articles {
+		articleIDs = append(articleIDs, article.ArticleID)
+	}
+	tags, err := svc.getArticleTags(articleIDs)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -97,7 +161,9 @@ func (svc *Service) GetArticleList(param *ArticleListRequest, pager *app.Pager)
 			Desc:          article.ArticleDesc,
 			Content:       article.Content,
 			CoverImageUrl: article.CoverImageUrl,
-			Tag:           &model.Tag{Model: &model.Model{ID: article.TagID}, Name: article.TagName},
+			State:         article.State,
+			PublishAt:     article.PublishAt,
+			Tags:          tags[article.ArticleID],
 		})
 	}
 
@@ -105,58 +171,242 @@ func (svc *Service) GetArticleList(param *ArticleListRequest, pager *app.Pager)
 }
 
 func (svc *Service) CreateArticle(param *CreateArticleRequest) error {
-	article, err := svc.dao.CreateArticle(&dao.Article{
-		Title:         param.Title,
-		Desc:          param.Desc,
-		Content:       param.Content,
-		CoverImageUrl: param.CoverImageUrl,
-		State:         param.State,
-		CreatedBy:     param.CreatedBy,
-	})
+	if err := checkPublishAt(param.State, param.PublishAt); err != nil {
+		return err
+	}
+
+	// 直接发布的文章以创建时间作为发布时间
+	publishAt := param.PublishAt
+	if param.State == model.ARTICLE_STATE_PUBLISHED && publishAt == 0 {
+		publishAt = uint32(time.Now().Unix())
+	}
+
+	contentHTML, err := markdown.Render(param.Content)
 	if err != nil {
 		return err
 	}
 
-	err = svc.dao.CreateArticleTag(article.ID, param.TagID, param.CreatedBy)
+	var articleID uint32
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		article, err := tx.CreateArticle(&dao.Article{
+			Title:         param.Title,
+			Desc:          param.Desc,
+			Content:       param.Content,
+			ContentHTML:   contentHTML,
+			CoverImageUrl: param.CoverImageUrl,
+			State:         param.State,
+			PublishAt:     publishAt,
+			CreatedBy:     param.CreatedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		articleID = article.ID
+		if err := tx.CreateArticleRevision(article, 1, param.CreatedBy); err != nil {
+			return err
+		}
+
+		return syncArticleTags(tx, article.ID, param.TagIDs, param.CreatedBy)
+	})
 	if err != nil {
 		return err
 	}
 
+	svc.invalidateArticleCache(articleID)
+	svc.indexArticle(articleID)
 	return nil
 }
 
 func (svc *Service) UpdateArticle(param *UpdateArticleRequest) error {
-	err := svc.dao.UpdateArticle(&dao.Article{
-		ID:            param.ID,
-		Title:         param.Title,
-		Desc:          param.Desc,
-		Content:       param.Content,
-		CoverImageUrl: param.CoverImageUrl,
-		State:         param.State,
-		ModifiedBy:    param.ModifiedBy,
+	if param.State != nil {
+		if err := checkPublishAt(*param.State, param.PublishAt); err != nil {
+			return err
+		}
+	}
+
+	err := svc.dao.Transaction(func(tx *dao.Dao) error {
+		err := updateArticleWithRevision(tx, &dao.Article{
+			ID:            param.ID,
+			Title:         param.Title,
+			Desc:          param.Desc,
+			Content:       param.Content,
+			CoverImageUrl: param.CoverImageUrl,
+			PublishAt:     param.PublishAt,
+			ModifiedBy:    param.ModifiedBy,
+		}, param.State)
+		if err != nil {
+			return err
+		}
+
+		// 未传入tag_ids时保留原有的标签关联
+		if len(param.TagIDs) == 0 {
+			return nil
+		}
+
+		return syncArticleTags(tx, param.ID, param.TagIDs, param.ModifiedBy)
 	})
 	if err != nil {
 		return err
 	}
 
-	err = svc.dao.UpdateArticleTag(param.ID, param.TagID, param.ModifiedBy)
+	svc.invalidateArticleCache(param.ID)
+	svc.indexArticle(param.ID)
+	return nil
+}
+
+func (svc *Service) DeleteArticle(param *DeleteArticleRequest) error {
+	err := svc.dao.Transaction(func(tx *dao.Dao) error {
+		err := tx.DeleteArticle(param.ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.DeleteArticleTag(param.ID)
+	})
 	if err != nil {
 		return err
 	}
 
+	svc.invalidateArticleCache(param.ID)
+	svc.unindexArticle(param.ID)
 	return nil
 }
 
-func (svc *Service) DeleteArticle(param *DeleteArticleRequest) error {
-	err := svc.dao.DeleteArticle(param.ID)
+// 获取文章渲染后的HTML, 没有缓存的旧文章渲染后写回数据库
+func (svc *Service) getArticleContentHTML(article *model.Article) (string, error) {
+	if article.ContentHTML != "" || article.Content == "" {
+		return article.ContentHTML, nil
+	}
+
+	contentHTML, err := markdown.Render(article.Content)
 	if err != nil {
-		return err
+		return "", err
 	}
+	if err := svc.dao.UpdateArticleContentHTML(article.ID, contentHTML); err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("svc.dao.UpdateArticleContentHTML err: %v", err)
+	} else {
+		svc.invalidateArticleCache(article.ID)
+	}
+
+	return contentHTML, nil
+}
+
+// 获取文章对应的标签列表, 以文章ID为键
+func (svc *Service) getArticleTags(articleIDs []uint32) (map[uint32][]*model.Tag, error) {
+	articleTags := map[uint32][]*model.Tag{}
+	if len(articleIDs) == 0 {
+		return articleTags, nil
+	}
+
+	links, err := svc.dao.GetArticleTagListByAIDs(articleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagIDs []uint32
+	seen := map[uint32]bool{}
+	for _, link := range links {
+		if !seen[link.TagID] {
+			seen[link.TagID] = true
+			tagIDs = append(tagIDs, link.TagID)
+		}
+	}
+	if len(tagIDs) == 0 {
+		return articleTags, nil
+	}
+
+	tags, err := svc.dao.GetTagListByIDs(tagIDs, model.STATE_OPEN)
+	if err != nil {
+		return nil, err
+	}
+
+	tagMap := make(map[uint32]*model.Tag, len(tags))
+	for _, tag := range tags {
+		tagMap[tag.ID] = tag
+	}
+	for _, link := range links {
+		if tag, ok := tagMap[link.TagID]; ok {
+			articleTags[link.ArticleID] = append(articleTags[link.ArticleID], tag)
+		}
+	}
+
+	return articleTags, nil
+}
 
-	err = svc.dao.DeleteArticleTag(param.ID)
+// 对比文章现有的标签关联, 新增缺少的关联并删除多余的关联
+func syncArticleTags(tx *dao.Dao, articleID uint32, tagIDs []uint32, operator string) error {
+	links, err := tx.GetArticleTagListByAID(articleID)
 	if err != nil {
 		return err
 	}
 
+	existing := map[uint32]bool{}
+	for _, link := range links {
+		existing[link.TagID] = true
+	}
+
+	var added []uint32
+	wanted := map[uint32]bool{}
+	for _, tagID := range tagIDs {
+		if wanted[tagID] {
+			continue
+		}
+		wanted[tagID] = true
+		if !existing[tagID] {
+			added = append(added, tagID)
+		}
+	}
+	if err := checkTagIDs(tx, added); err != nil {
+		return err
+	}
+	for _, tagID := range added {
+		if err := tx.CreateArticleTag(articleID, tagID, operator); err != nil {
+			return err
+		}
+	}
+
+	var removed []uint32
+	for tagID := range existing {
+		if !wanted[tagID] {
+			removed = append(removed, tagID)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	return tx.DeleteArticleTagByTIDs(articleID, removed)
+}
+
+// 新关联的标签必须存在且未删除, 已禁用的标签也可以关联
+func checkTagIDs(tx *dao.Dao, tagIDs []uint32) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	found := map[uint32]bool{}
+	for _, state := range []uint8{model.STATE_OPEN, model.STATE_CLOSE} {
+		tags, err := tx.GetTagListByIDs(tagIDs, state)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			found[tag.ID] = true
+		}
+	}
+	for _, tagID := range tagIDs {
+		if !found[tagID] {
+			return ErrInvalidTagIDs
+		}
+	}
+	return nil
+}
+
+// 定时发布的文章必须指定一个未来的发布时间
+func checkPublishAt(state uint8, publishAt uint32) error {
+	if state == model.ARTICLE_STATE_SCHEDULED && int64(publishAt) <= time.Now().Unix() {
+		return ErrInvalidPublishAt
+	}
 	return nil
 }
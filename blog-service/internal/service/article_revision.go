@@ -0,0 +1,198 @@
+package service
+
+import (
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/markdown"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var ErrArticleRevisionNotFound = errors.New("article revision not found")
+
+type ArticleRevisionListRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+}
+
+type ArticleRevisionRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	Revision  uint32 `form:"revision" binding:"required,gte=1"`
+}
+
+// 恢复的是历史版本的内容, 未传入state时保持文章当前的状态和发布时间
+type RestoreArticleRevisionRequest struct {
+	ArticleID  uint32 `form:"article_id" binding:"required,gte=1"`
+	Revision   uint32 `form:"revision" binding:"required,gte=1"`
+	ModifiedBy string `form:"modified_by" binding:"required,min=2,max=100"`
+	State      *uint8 `form:"state" binding:"omitempty,oneof=0 1 2 3"`
+	PublishAt  uint32 `form:"publish_at"`
+}
+
+// 历史版本与文章当前内容之间的unified diff, 内容相同的字段为空
+type ArticleRevisionDiff struct {
+	Revision      uint32 `json:"revision"`
+	Title         string `json:"title"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content"`
+	CoverImageUrl string `json:"cover_image_url"`
+}
+
+func (svc *Service) GetArticleRevisionList(param *ArticleRevisionListRequest, pager *app.Pager) ([]*model.ArticleRevision, int, error) {
+	totalRows, err := svc.dao.CountArticleRevision(param.ArticleID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	revisions, err := svc.dao.GetArticleRevisionList(param.ArticleID, pager.Page, pager.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, totalRows, nil
+}
+
+func (svc *Service) GetArticleRevisionDiff(param *ArticleRevisionRequest) (*ArticleRevisionDiff, error) {
+	revision, err := svc.dao.GetArticleRevision(param.ArticleID, param.Revision)
+	if err != nil {
+		return nil, err
+	}
+	if revision.Model == nil || revision.ID == 0 {
+		return nil, ErrArticleRevisionNotFound
+	}
+
+	article, err := svc.dao.GetArticleByID(param.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Model == nil || article.ID == 0 {
+		return nil, ErrArticleNotFound
+	}
+
+	from := fmt.Sprintf("revision %d", revision.Revision)
+	diff := &ArticleRevisionDiff{Revision: revision.Revision}
+	fields := []struct {
+		dst  *string
+		a, b string
+	}{
+		{&diff.Title, revision.Title, article.Title},
+		{&diff.Desc, revision.Desc, article.Desc},
+		{&diff.Content, revision.Content, article.Content},
+		{&diff.CoverImageUrl, revision.CoverImageUrl, article.CoverImageUrl},
+	}
+	for _, field := range fields {
+		*field.dst, err = unifiedDiff(from, "current", field.a, field.b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diff, nil
+}
+
+// 将文章恢复到指定版本, 恢复本身也会记录为一个新版本
+func (svc *Service) RestoreArticleRevision(param *RestoreArticleRevisionRequest) error {
+	if param.State != nil {
+		if err := checkPublishAt(*param.State, param.PublishAt); err != nil {
+			return err
+		}
+	}
+
+	err := svc.dao.Transaction(func(tx *dao.Dao) error {
+		revision, err := tx.GetArticleRevision(param.ArticleID, param.Revision)
+		if err != nil {
+			return err
+		}
+		if revision.Model == nil || revision.ID == 0 {
+			return ErrArticleRevisionNotFound
+		}
+
+		return updateArticleWithRevision(tx, &dao.Article{
+			ID:            param.ArticleID,
+			Title:         revision.Title,
+			Desc:          revision.Desc,
+			Content:       revision.Content,
+			CoverImageUrl: revision.CoverImageUrl,
+			PublishAt:     param.PublishAt,
+			ModifiedBy:    param.ModifiedBy,
+		}, param.State)
+	})
+	if err != nil {
+		return err
+	}
+
+	svc.invalidateArticleCache(param.ArticleID)
+	svc.indexArticle(param.ArticleID)
+	return nil
+}
+
+// 修改文章并记录修改后的快照, 需要在事务中调用
+// state为nil时保持文章原来的状态, param.PublishAt为0时保持原来的发布时间
+func updateArticleWithRevision(tx *dao.Dao, param *dao.Article, state *uint8) error {
+	current, err := tx.LockArticle(param.ID)
+	if err != nil {
+		return err
+	}
+	if current.Model == nil || current.ID == 0 {
+		return ErrArticleNotFound
+	}
+
+	param.State = current.State
+	if state != nil {
+		param.State = *state
+	} else if param.PublishAt != 0 {
+		// 只修改发布时间时, 定时发布的文章仍然要求发布时间在未来
+		if err := checkPublishAt(current.State, param.PublishAt); err != nil {
+			return err
+		}
+	}
+
+	revision, err := tx.GetArticleMaxRevision(param.ID)
+	if err != nil {
+		return err
+	}
+	// 记录版本之前创建的文章没有历史版本, 先保存修改前的内容作为第一个版本
+	if revision == 0 {
+		revision = 1
+		if err := tx.CreateArticleRevision(&current, revision, current.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	// 文章首次发布时记录发布时间
+	if param.State == model.ARTICLE_STATE_PUBLISHED && current.State != model.ARTICLE_STATE_PUBLISHED && param.PublishAt == 0 {
+		param.PublishAt = uint32(time.Now().Unix())
+	}
+
+	// 修改了文章内容时重新渲染HTML
+	if param.Content != "" {
+		param.ContentHTML, err = markdown.Render(param.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.UpdateArticle(param); err != nil {
+		return err
+	}
+
+	updated, err := tx.GetArticleByID(param.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.CreateArticleRevision(&updated, revision+1, param.ModifiedBy)
+}
+
+func unifiedDiff(from, to, a, b string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	})
+}
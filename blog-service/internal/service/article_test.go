@@ -0,0 +1,317 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"blog-service/internal/model"
+)
+
+func uint8Ptr(v uint8) *uint8 {
+	return &v
+}
+
+// 只修改标题时不能改变文章的状态和发布时间
+func TestUpdateArticleKeepsState(t *testing.T) {
+	svc := newTestService(t)
+
+	future := uint32(time.Now().Add(time.Hour).Unix())
+	tests := []struct {
+		name      string
+		state     uint8
+		publishAt uint32
+	}{
+		{"draft", model.ARTICLE_STATE_DRAFT, 0},
+		{"scheduled", model.ARTICLE_STATE_SCHEDULED, future},
+		{"archived", model.ARTICLE_STATE_ARCHIVED, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := createTestArticle(t, svc, tt.name, tt.state, tt.publishAt)
+
+			err := svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, Title: tt.name + " v2", ModifiedBy: "editor"})
+			if err != nil {
+				t.Fatalf("UpdateArticle err: %v", err)
+			}
+
+			got := getTestArticle(t, svc, article.ID)
+			if got.Title != tt.name+" v2" {
+				t.Errorf("Title = %q, want %q", got.Title, tt.name+" v2")
+			}
+			if got.State != tt.state || got.PublishAt != tt.publishAt {
+				t.Errorf("state, publish_at = %d, %d, want %d, %d", got.State, got.PublishAt, tt.state, tt.publishAt)
+			}
+		})
+	}
+}
+
+func TestUpdateArticleStateTransitions(t *testing.T) {
+	svc := newTestService(t)
+
+	now := time.Now()
+	past := uint32(now.Add(-time.Hour).Unix())
+	future := uint32(now.Add(time.Hour).Unix())
+	tests := []struct {
+		name          string
+		state         uint8
+		publishAt     uint32
+		req           UpdateArticleRequest
+		wantErr       error
+		wantState     uint8
+		wantPublishAt func(publishAt uint32) bool
+	}{
+		{
+			name:          "draft to published records publish time",
+			state:         model.ARTICLE_STATE_DRAFT,
+			req:           UpdateArticleRequest{State: uint8Ptr(model.ARTICLE_STATE_PUBLISHED)},
+			wantState:     model.ARTICLE_STATE_PUBLISHED,
+			wantPublishAt: func(p uint32) bool { return int64(p) >= now.Unix() },
+		},
+		{
+			name:          "draft to scheduled",
+			state:         model.ARTICLE_STATE_DRAFT,
+			req:           UpdateArticleRequest{State: uint8Ptr(model.ARTICLE_STATE_SCHEDULED), PublishAt: future},
+			wantState:     model.ARTICLE_STATE_SCHEDULED,
+			wantPublishAt: func(p uint32) bool { return p == future },
+		},
+		{
+			name:      "scheduled without publish_at",
+			state:     model.ARTICLE_STATE_DRAFT,
+			req:       UpdateArticleRequest{State: uint8Ptr(model.ARTICLE_STATE_SCHEDULED)},
+			wantErr:   ErrInvalidPublishAt,
+			wantState: model.ARTICLE_STATE_DRAFT,
+		},
+		{
+			name:      "scheduled in the past",
+			state:     model.ARTICLE_STATE_DRAFT,
+			req:       UpdateArticleRequest{State: uint8Ptr(model.ARTICLE_STATE_SCHEDULED), PublishAt: past},
+			wantErr:   ErrInvalidPublishAt,
+			wantState: model.ARTICLE_STATE_DRAFT,
+		},
+		{
+			name:          "reschedule",
+			state:         model.ARTICLE_STATE_SCHEDULED,
+			publishAt:     future,
+			req:           UpdateArticleRequest{PublishAt: future + 60},
+			wantState:     model.ARTICLE_STATE_SCHEDULED,
+			wantPublishAt: func(p uint32) bool { return p == future+60 },
+		},
+		{
+			name:      "reschedule into the past",
+			state:     model.ARTICLE_STATE_SCHEDULED,
+			publishAt: future,
+			req:       UpdateArticleRequest{PublishAt: past},
+			wantErr:   ErrInvalidPublishAt,
+			wantState: model.ARTICLE_STATE_SCHEDULED,
+		},
+		{
+			name:          "published to draft keeps publish time",
+			state:         model.ARTICLE_STATE_PUBLISHED,
+			publishAt:     1000,
+			req:           UpdateArticleRequest{State: uint8Ptr(model.ARTICLE_STATE_DRAFT)},
+			wantState:     model.ARTICLE_STATE_DRAFT,
+			wantPublishAt: func(p uint32) bool { return p == 1000 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := createTestArticle(t, svc, tt.name, tt.state, tt.publishAt)
+
+			req := tt.req
+			req.ID = article.ID
+			req.ModifiedBy = "editor"
+			if err := svc.UpdateArticle(&req); err != tt.wantErr {
+				t.Fatalf("UpdateArticle err = %v, want %v", err, tt.wantErr)
+			}
+
+			got := getTestArticle(t, svc, article.ID)
+			if got.State != tt.wantState {
+				t.Errorf("State = %d, want %d", got.State, tt.wantState)
+			}
+			if tt.wantPublishAt != nil && !tt.wantPublishAt(got.PublishAt) {
+				t.Errorf("PublishAt = %d is unexpected", got.PublishAt)
+			}
+		})
+	}
+}
+
+func TestUpdateArticleNotFound(t *testing.T) {
+	svc := newTestService(t)
+
+	err := svc.UpdateArticle(&UpdateArticleRequest{ID: 100, Title: "missing", ModifiedBy: "editor"})
+	if err != ErrArticleNotFound {
+		t.Fatalf("UpdateArticle err = %v, want %v", err, ErrArticleNotFound)
+	}
+}
+
+// 关联不存在或已删除的标签时不创建文章, 也不修改已有的标签关联
+func TestArticleTagIDsMustExist(t *testing.T) {
+	svc := newTestService(t)
+
+	var tagIDs []uint32
+	for _, name := range []string{"Go", "Rust", "Deleted"} {
+		tag, err := svc.dao.CreateTag(name, model.STATE_OPEN, "tester")
+		if err != nil {
+			t.Fatalf("CreateTag(%s) err: %v", name, err)
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := svc.dao.UpdateTag(tagIDs[1], "", model.STATE_CLOSE, "tester"); err != nil {
+		t.Fatalf("UpdateTag err: %v", err)
+	}
+	if err := svc.dao.DeleteTag(tagIDs[2]); err != nil {
+		t.Fatalf("DeleteTag err: %v", err)
+	}
+
+	create := func(title string, tagIDs ...uint32) error {
+		return svc.CreateArticle(&CreateArticleRequest{
+			TagIDs:        tagIDs,
+			Title:         title,
+			Desc:          title + " desc",
+			Content:       "# " + title,
+			CoverImageUrl: "https://example.com/cover.png",
+			CreatedBy:     "tester",
+			State:         model.ARTICLE_STATE_DRAFT,
+		})
+	}
+	for _, tt := range []struct {
+		name   string
+		tagIDs []uint32
+	}{
+		{"missing", []uint32{tagIDs[0], 100}},
+		{"deleted", []uint32{tagIDs[2]}},
+	} {
+		if err := create(tt.name, tt.tagIDs...); err != ErrInvalidTagIDs {
+			t.Errorf("CreateArticle with %s tag err = %v, want %v", tt.name, err, ErrInvalidTagIDs)
+		}
+		if article, err := svc.dao.GetArticleByTitle(tt.name); err != nil || (article.Model != nil && article.ID != 0) {
+			t.Errorf("GetArticleByTitle(%s) = %+v, %v, want not found", tt.name, article.Model, err)
+		}
+	}
+
+	// 已禁用的标签可以关联
+	if err := create("valid", tagIDs[0], tagIDs[1]); err != nil {
+		t.Fatalf("CreateArticle err: %v", err)
+	}
+	article, err := svc.dao.GetArticleByTitle("valid")
+	if err != nil {
+		t.Fatalf("GetArticleByTitle err: %v", err)
+	}
+
+	err = svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, TagIDs: []uint32{tagIDs[0], tagIDs[2]}, ModifiedBy: "editor"})
+	if err != ErrInvalidTagIDs {
+		t.Errorf("UpdateArticle with deleted tag err = %v, want %v", err, ErrInvalidTagIDs)
+	}
+	links, err := svc.dao.GetArticleTagListByAID(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleTagListByAID err: %v", err)
+	}
+	if len(links) != 2 {
+		t.Errorf("article tags = %+v after a rejected update, want the 2 original tags", links)
+	}
+}
+
+func TestPublishDueArticles(t *testing.T) {
+	svc := newTestService(t)
+
+	now := time.Now()
+	due := []*model.Article{
+		createTestArticle(t, svc, "due 1", model.ARTICLE_STATE_SCHEDULED, uint32(now.Add(-2*time.Minute).Unix())),
+		createTestArticle(t, svc, "due 2", model.ARTICLE_STATE_SCHEDULED, uint32(now.Add(-time.Minute).Unix())),
+		createTestArticle(t, svc, "due 3", model.ARTICLE_STATE_SCHEDULED, uint32(now.Unix())),
+	}
+	notDue := createTestArticle(t, svc, "not due", model.ARTICLE_STATE_SCHEDULED, uint32(now.Add(time.Hour).Unix()))
+	draft := createTestArticle(t, svc, "draft", model.ARTICLE_STATE_DRAFT, uint32(now.Add(-time.Hour).Unix()))
+
+	// 每次最多发布limit篇, 剩余的在下一次发布
+	count, err := svc.PublishDueArticles(2)
+	if err != nil {
+		t.Fatalf("PublishDueArticles err: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("PublishDueArticles = %d, want 2", count)
+	}
+	count, err = svc.PublishDueArticles(2)
+	if err != nil {
+		t.Fatalf("PublishDueArticles err: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("PublishDueArticles = %d, want 1", count)
+	}
+	count, err = svc.PublishDueArticles(2)
+	if err != nil || count != 0 {
+		t.Fatalf("PublishDueArticles = %d, %v, want 0, nil", count, err)
+	}
+
+	for _, article := range due {
+		got := getTestArticle(t, svc, article.ID)
+		if got.State != model.ARTICLE_STATE_PUBLISHED {
+			t.Errorf("%s State = %d, want %d", article.Title, got.State, model.ARTICLE_STATE_PUBLISHED)
+		}
+		// 保留计划的发布时间
+		if got.PublishAt != article.PublishAt {
+			t.Errorf("%s PublishAt = %d, want %d", article.Title, got.PublishAt, article.PublishAt)
+		}
+		if got.ModifiedBy != publishOperator {
+			t.Errorf("%s ModifiedBy = %q, want %q", article.Title, got.ModifiedBy, publishOperator)
+		}
+
+		revisions, err := svc.dao.GetArticleRevisionList(article.ID, 1, 10)
+		if err != nil {
+			t.Fatalf("GetArticleRevisionList err: %v", err)
+		}
+		if len(revisions) != 2 {
+			t.Errorf("%s has %d revisions, want 2", article.Title, len(revisions))
+		}
+	}
+	if got := getTestArticle(t, svc, notDue.ID); got.State != model.ARTICLE_STATE_SCHEDULED {
+		t.Errorf("not due State = %d, want %d", got.State, model.ARTICLE_STATE_SCHEDULED)
+	}
+	if got := getTestArticle(t, svc, draft.ID); got.State != model.ARTICLE_STATE_DRAFT {
+		t.Errorf("draft State = %d, want %d", got.State, model.ARTICLE_STATE_DRAFT)
+	}
+}
+
+// 恢复定时发布期间记录的版本时, 已发布的文章保持发布状态和发布时间
+func TestRestoreArticleRevisionKeepsState(t *testing.T) {
+	svc := newTestService(t)
+
+	future := uint32(time.Now().Add(time.Hour).Unix())
+	article := createTestArticle(t, svc, "scheduled", model.ARTICLE_STATE_SCHEDULED, future)
+	// 第1个版本记录修改前的定时发布状态, 第2个版本为修改后的标题, 第3个版本为发布
+	err := svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, Title: "scheduled v2", ModifiedBy: "editor"})
+	if err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	err = svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, State: uint8Ptr(model.ARTICLE_STATE_PUBLISHED), ModifiedBy: "editor"})
+	if err != nil {
+		t.Fatalf("UpdateArticle publish err: %v", err)
+	}
+	published := getTestArticle(t, svc, article.ID)
+
+	err = svc.RestoreArticleRevision(&RestoreArticleRevisionRequest{ArticleID: article.ID, Revision: 1, ModifiedBy: "editor"})
+	if err != nil {
+		t.Fatalf("RestoreArticleRevision err: %v", err)
+	}
+	got := getTestArticle(t, svc, article.ID)
+	if got.Title != "scheduled" {
+		t.Errorf("Title = %q, want the revision title", got.Title)
+	}
+	if got.State != model.ARTICLE_STATE_PUBLISHED || got.PublishAt != published.PublishAt {
+		t.Errorf("state, publish_at = %d, %d, want %d, %d", got.State, got.PublishAt, model.ARTICLE_STATE_PUBLISHED, published.PublishAt)
+	}
+
+	// 指定状态时按修改文章的规则校验发布时间
+	req := &RestoreArticleRevisionRequest{ArticleID: article.ID, Revision: 2, ModifiedBy: "editor", State: uint8Ptr(model.ARTICLE_STATE_SCHEDULED)}
+	if err := svc.RestoreArticleRevision(req); err != ErrInvalidPublishAt {
+		t.Fatalf("RestoreArticleRevision without publish_at err = %v, want %v", err, ErrInvalidPublishAt)
+	}
+	req.PublishAt = future + 60
+	if err := svc.RestoreArticleRevision(req); err != nil {
+		t.Fatalf("RestoreArticleRevision err: %v", err)
+	}
+	got = getTestArticle(t, svc, article.ID)
+	if got.Title != "scheduled v2" || got.State != model.ARTICLE_STATE_SCHEDULED || got.PublishAt != future+60 {
+		t.Errorf("article = %q, %d, %d, want the revision 2 title rescheduled", got.Title, got.State, got.PublishAt)
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"blog-service/pkg/util"
+	"crypto/subtle"
+	"errors"
+)
+
+type AuthRequest struct {
+	AppKey    string `form:"app_key" binding:"required"`
+	AppSecret string `form:"app_secret" binding:"required"`
+}
+
+func (svc *Service) CheckAuth(param *AuthRequest) error {
+	auth, err := svc.dao.GetAuth(param.AppKey)
+	if err != nil {
+		return err
+	}
+
+	// 数据库中只保存Secret的SHA-256编码, 使用固定时间比较避免泄露匹配的长度
+	secret := util.EncodeSHA256(param.AppSecret)
+	if auth.Model != nil && auth.ID > 0 && subtle.ConstantTimeCompare([]byte(auth.AppSecret), []byte(secret)) == 1 {
+		return nil
+	}
+
+	return errors.New("auth info does not exist")
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/util"
+)
+
+func TestCheckAuth(t *testing.T) {
+	svc := newTestService(t)
+
+	auths := []*model.Auth{
+		{Model: &model.Model{}, AppKey: "app-key", AppSecret: util.EncodeSHA256("app-secret")},
+		{Model: &model.Model{IsDel: 1}, AppKey: "deleted-key", AppSecret: util.EncodeSHA256("app-secret")},
+	}
+	for _, auth := range auths {
+		if err := global.DBEngine.Create(auth).Error; err != nil {
+			t.Fatalf("create auth %s err: %v", auth.AppKey, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     AuthRequest
+		wantErr bool
+	}{
+		{"valid", AuthRequest{AppKey: "app-key", AppSecret: "app-secret"}, false},
+		{"wrong secret", AuthRequest{AppKey: "app-key", AppSecret: "wrong-secret"}, true},
+		// 数据库中保存的是编码后的值, 直接传入编码后的值不能通过校验
+		{"hashed secret", AuthRequest{AppKey: "app-key", AppSecret: util.EncodeSHA256("app-secret")}, true},
+		{"unknown key", AuthRequest{AppKey: "unknown-key", AppSecret: "app-secret"}, true},
+		{"deleted", AuthRequest{AppKey: "deleted-key", AppSecret: "app-secret"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CheckAuth(&tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAuth err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/setting"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 标签变更时更新版本号, 包含版本号的缓存键随之失效
+const tagVersionKey = "tag:version"
+
+// 同一个键只允许一个请求回源, 其他请求等待并共享结果
+var cacheGroup singleflight.Group
+
+// 读取缓存到dest中, 未命中时调用load回源并写入缓存, 未启用缓存时直接回源
+// 同一个键的并发请求共享一次回源, 回源使用传给load的Service, 不受发起回源的请求取消的影响
+func (svc *Service) cacheLoad(key string, ttl time.Duration, dest interface{}, load func(svc *Service) (interface{}, error)) error {
+	if global.Cache != nil {
+		data, ok, err := global.Cache.Get(svc.ctx, key)
+		if err != nil {
+			global.Logger.WithTrace(svc.ctx).Errorf("Cache.Get %s err: %v", key, err)
+		}
+		if ok {
+			return json.Unmarshal(data, dest)
+		}
+	}
+
+	data, err, _ := cacheGroup.Do(key, func() (interface{}, error) {
+		ctx, cancel := loadContext(svc.ctx)
+		defer cancel()
+
+		loader := New(ctx)
+		value, err := load(&loader)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if global.Cache != nil {
+			if err := global.Cache.Set(ctx, key, data, ttl); err != nil {
+				global.Logger.WithTrace(ctx).Errorf("Cache.Set %s err: %v", key, err)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+// 回源使用的context, 保留请求中的值(如链路追踪信息), 但不随请求取消或超时
+// 否则第一个请求的客户端断开时, 等待同一个键的其他请求会一起失败
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c detachedContext) Done() <-chan struct{}             { return nil }
+func (c detachedContext) Err() error                        { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+// 回源的超时时间与请求的默认超时时间相同
+func loadContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx := context.Context(detachedContext{parent: parent})
+
+	setting.RLock()
+	appSetting := global.AppSetting
+	setting.RUnlock()
+	if appSetting == nil || appSetting.DefaultContextTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, appSetting.DefaultContextTimeout)
+}
+
+// 获取当前的标签版本号, 不存在时生成新的版本号
+func (svc *Service) tagVersion() string {
+	return svc.cacheVersion(tagVersionKey)
+}
+
+func (svc *Service) bumpTagVersion() string {
+	return svc.bumpCacheVersion(tagVersionKey)
+}
+
+// 文章变更时更新该文章的版本号, 使文章详情的缓存失效
+// 与直接删除缓存不同, 变更前开始的回源即使在变更后才写入缓存, 写入的也是旧版本号的缓存键, 不会再被读取
+func (svc *Service) invalidateArticleCache(ids ...uint32) {
+	for _, id := range ids {
+		svc.bumpCacheVersion(articleVersionKey(id))
+	}
+}
+
+func articleVersionKey(id uint32) string {
+	return fmt.Sprintf("article:%d:version", id)
+}
+
+// 获取版本号, 不存在时生成新的版本号
+func (svc *Service) cacheVersion(key string) string {
+	if global.Cache == nil {
+		return ""
+	}
+
+	data, ok, err := global.Cache.Get(svc.ctx, key)
+	if err == nil && ok {
+		return string(data)
+	}
+	return svc.bumpCacheVersion(key)
+}
+
+// 写入新的版本号, 写入失败只记录日志
+func (svc *Service) bumpCacheVersion(key string) string {
+	if global.Cache == nil {
+		return ""
+	}
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := global.Cache.Set(svc.ctx, key, []byte(version), 0); err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("Cache.Set %s err: %v", key, err)
+	}
+	return version
+}
+
+// 文章详情中包含标签信息, 因此缓存键中带有文章和标签的版本号
+func (svc *Service) articleCacheKey(id uint32) string {
+	return fmt.Sprintf("article:%d:%s:%s", id, svc.cacheVersion(articleVersionKey(id)), svc.tagVersion())
+}
+
+func (svc *Service) tagListCacheKey(kind, name string, state uint8, page, pageSize int) string {
+	return fmt.Sprintf("tags:%s:%s:%s:%d:%d:%d", kind, svc.tagVersion(), url.QueryEscape(name), state, page, pageSize)
+}
+
+func cacheTTL() (article, tagList time.Duration) {
+	setting.RLock()
+	defer setting.RUnlock()
+	return global.CacheSetting.ArticleTTL, global.CacheSetting.TagListTTL
+}
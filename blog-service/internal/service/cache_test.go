@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/cache"
+	"blog-service/pkg/setting"
+)
+
+func newTestCachedService(t *testing.T) Service {
+	t.Helper()
+
+	svc := newTestService(t)
+	global.Cache = cache.NewMemoryCache(100)
+	global.CacheSetting = &setting.CacheSettingS{ArticleTTL: time.Minute, TagListTTL: time.Minute}
+	return svc
+}
+
+func TestCacheLoadSingleflight(t *testing.T) {
+	svc := newTestCachedService(t)
+
+	var calls int32
+	release := make(chan struct{})
+	load := func(svc *Service) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := svc.cacheLoad("singleflight", time.Minute, &results[i], load); err != nil {
+				t.Errorf("cacheLoad err: %v", err)
+			}
+		}(i)
+	}
+	// 等待所有请求进入singleflight后再让回源返回
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("load called %d times, want 1", calls)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("results[%d] = %d, want 42", i, v)
+		}
+	}
+
+	// 之后的请求直接命中缓存
+	var v int
+	err := svc.cacheLoad("singleflight", time.Minute, &v, func(svc *Service) (interface{}, error) {
+		return nil, errors.New("should hit cache")
+	})
+	if err != nil || v != 42 {
+		t.Errorf("cacheLoad = %d, %v, want 42, nil", v, err)
+	}
+}
+
+// 发起回源的请求被取消时, 回源和等待同一个键的其他请求不受影响
+func TestCacheLoadDetachedFromRequest(t *testing.T) {
+	newTestCachedService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := New(ctx)
+	second := New(context.Background())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var loadErr error
+	load := func(svc *Service) (interface{}, error) {
+		close(started)
+		<-release
+		loadErr = svc.ctx.Err()
+		// 回源使用的数据库连接也不能随请求取消
+		return svc.dao.CountTag("", model.STATE_OPEN)
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		var count int
+		errs <- first.cacheLoad("detached", time.Minute, &count, load)
+	}()
+	<-started
+	go func() {
+		var count int
+		errs <- second.cacheLoad("detached", time.Minute, &count, load)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("cacheLoad err: %v", err)
+		}
+	}
+	if loadErr != nil {
+		t.Errorf("load context err = %v, want nil", loadErr)
+	}
+}
+
+func TestArticleCacheInvalidation(t *testing.T) {
+	svc := newTestCachedService(t)
+
+	if err := svc.CreateTag(&CreateTagRequest{Name: "golang", CreatedBy: "tester", State: model.STATE_OPEN}); err != nil {
+		t.Fatalf("CreateTag err: %v", err)
+	}
+	tags, err := svc.GetTagList(&TagListRequest{State: model.STATE_OPEN}, &app.Pager{Page: 1, PageSize: 10})
+	if err != nil || len(tags) != 1 {
+		t.Fatalf("GetTagList = %v, %v, want 1 tag", tags, err)
+	}
+	tagID := tags[0].ID
+
+	err = svc.CreateArticle(&CreateArticleRequest{
+		TagIDs:        []uint32{tagID},
+		Title:         "cached",
+		Desc:          "cached desc",
+		Content:       "# cached",
+		CoverImageUrl: "https://example.com/cover.png",
+		CreatedBy:     "tester",
+		State:         model.ARTICLE_STATE_PUBLISHED,
+	})
+	if err != nil {
+		t.Fatalf("CreateArticle err: %v", err)
+	}
+	article, err := svc.dao.GetArticleByTitle("cached")
+	if err != nil {
+		t.Fatalf("GetArticleByTitle err: %v", err)
+	}
+
+	get := func() *Article {
+		t.Helper()
+		got, err := svc.GetArticle(&ArticleRequest{ID: article.ID, State: model.ARTICLE_STATE_PUBLISHED, Format: "raw"})
+		if err != nil {
+			t.Fatalf("GetArticle err: %v", err)
+		}
+		return got
+	}
+	get()
+
+	// 绕过service直接修改数据库, 缓存未失效时仍返回旧的内容
+	if err := global.DBEngine.Model(&model.Article{}).Where("id = ?", article.ID).Update("desc", "changed in db").Error; err != nil {
+		t.Fatalf("update desc err: %v", err)
+	}
+	if got := get(); got.Desc != "cached desc" {
+		t.Errorf("Desc = %q, want cached value", got.Desc)
+	}
+
+	// 修改文章后缓存失效
+	if err := svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, Title: "updated", ModifiedBy: "tester"}); err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	if got := get(); got.Title != "updated" || got.Desc != "changed in db" {
+		t.Errorf("Title, Desc = %q, %q after update, want updated, changed in db", got.Title, got.Desc)
+	}
+
+	// 修改标签后文章详情中的标签也会更新
+	if err := svc.UpdateTag(&UpdateTagRequest{ID: tagID, Name: "go", State: model.STATE_OPEN, ModifiedBy: "tester"}); err != nil {
+		t.Fatalf("UpdateTag err: %v", err)
+	}
+	if got := get(); len(got.Tags) != 1 || got.Tags[0].Name != "go" {
+		t.Errorf("Tags = %v after tag update, want [go]", got.Tags)
+	}
+	tags, err = svc.GetTagList(&TagListRequest{State: model.STATE_OPEN}, &app.Pager{Page: 1, PageSize: 10})
+	if err != nil || len(tags) != 1 || tags[0].Name != "go" {
+		t.Errorf("GetTagList = %v, %v after tag update, want [go]", tags, err)
+	}
+
+	// 删除文章后不再返回
+	if err := svc.DeleteArticle(&DeleteArticleRequest{ID: article.ID}); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+	if _, err := svc.GetArticle(&ArticleRequest{ID: article.ID, State: model.ARTICLE_STATE_PUBLISHED, Format: "raw"}); err != ErrArticleNotFound {
+		t.Errorf("GetArticle after delete err = %v, want %v", err, ErrArticleNotFound)
+	}
+}
+
+// 修改文章前开始的回源在修改后才写入缓存时, 之后的请求不能读到旧的内容
+func TestArticleCacheStaleLoad(t *testing.T) {
+	svc := newTestCachedService(t)
+	article := createTestArticle(t, svc, "cached", model.ARTICLE_STATE_PUBLISHED, 0)
+
+	loaded := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		var entry articleCacheEntry
+		done <- svc.cacheLoad(svc.articleCacheKey(article.ID), time.Minute, &entry, func(svc *Service) (interface{}, error) {
+			stale, err := svc.dao.GetArticleByID(article.ID)
+			close(loaded)
+			<-release
+			return &articleCacheEntry{Article: &stale}, err
+		})
+	}()
+
+	<-loaded
+	if err := svc.UpdateArticle(&UpdateArticleRequest{ID: article.ID, Title: "updated", ModifiedBy: "tester"}); err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("cacheLoad err: %v", err)
+	}
+
+	got, err := svc.GetArticle(&ArticleRequest{ID: article.ID, State: model.ARTICLE_STATE_PUBLISHED, Format: "raw"})
+	if err != nil {
+		t.Fatalf("GetArticle err: %v", err)
+	}
+	if got.Title != "updated" {
+		t.Errorf("Title = %q, want the value written after the stale load", got.Title)
+	}
+}
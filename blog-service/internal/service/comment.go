@@ -0,0 +1,187 @@
+package service
+
+import (
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"errors"
+)
+
+// 每页评论下最多展开的回复层数和回复总数, 超出的回复不在列表中返回
+const (
+	maxCommentReplyDepth = 5
+	maxCommentReplies    = 200
+)
+
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrCommentForbidden = errors.New("only moderators can list unapproved comments")
+)
+
+// 只有审核员可以查看待审核和已拒绝的评论
+type CommentListRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	State     uint8  `form:"state,default=1" binding:"oneof=0 1 2"`
+	Moderator bool   `form:"-"`
+}
+
+type CreateCommentRequest struct {
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+	ParentID  uint32 `form:"parent_id"`
+	Content   string `form:"content" binding:"required,min=1,max=1000"`
+	CreatedBy string `form:"created_by" binding:"required,min=2,max=100"`
+}
+
+type UpdateCommentStateRequest struct {
+	ID         uint32 `form:"id" binding:"required,gte=1"`
+	ArticleID  uint32 `form:"article_id" binding:"required,gte=1"`
+	State      *uint8 `form:"state" binding:"required,oneof=0 1 2"`
+	ModifiedBy string `form:"modified_by" binding:"required,min=2,max=100"`
+}
+
+type DeleteCommentRequest struct {
+	ID        uint32 `form:"id" binding:"required,gte=1"`
+	ArticleID uint32 `form:"article_id" binding:"required,gte=1"`
+}
+
+type Comment struct {
+	ID        uint32     `json:"id"`
+	ArticleID uint32     `json:"article_id"`
+	ParentID  uint32     `json:"parent_id"`
+	Content   string     `json:"content"`
+	State     uint8      `json:"state"`
+	CreatedBy string     `json:"created_by"`
+	CreatedOn uint32     `json:"created_on"`
+	Replies   []*Comment `json:"replies"`
+}
+
+// 分页获取文章的顶层评论, 并将同一状态的回复嵌套到对应的评论下, 回复的层数和数量受maxCommentReplyDepth和maxCommentReplies限制
+func (svc *Service) GetCommentList(param *CommentListRequest, pager *app.Pager) ([]*Comment, int, error) {
+	if param.State != model.COMMENT_STATE_APPROVED && !param.Moderator {
+		return nil, 0, ErrCommentForbidden
+	}
+
+	totalRows, err := svc.dao.CountCommentRoots(param.ArticleID, param.State)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	roots, err := svc.dao.GetCommentRoots(param.ArticleID, param.State, pager.Page, pager.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 逐层获取当前页评论下的回复
+	children := map[uint32][]*model.Comment{}
+	parentIDs := make([]uint32, 0, len(roots))
+	for _, root := range roots {
+		parentIDs = append(parentIDs, root.ID)
+	}
+	remaining := maxCommentReplies
+	for depth := 0; depth < maxCommentReplyDepth && len(parentIDs) > 0 && remaining > 0; depth++ {
+		replies, err := svc.dao.GetCommentReplies(param.ArticleID, param.State, parentIDs, remaining)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		remaining -= len(replies)
+		parentIDs = parentIDs[:0]
+		for _, reply := range replies {
+			children[reply.ParentID] = append(children[reply.ParentID], reply)
+			parentIDs = append(parentIDs, reply.ID)
+		}
+	}
+
+	commentList := make([]*Comment, 0, len(roots))
+	for _, root := range roots {
+		commentList = append(commentList, buildCommentTree(root, children))
+	}
+
+	return commentList, totalRows, nil
+}
+
+// 新评论为待审核状态, 审核通过后才会展示
+func (svc *Service) CreateComment(param *CreateCommentRequest) (*Comment, error) {
+	article, err := svc.dao.GetArticle(param.ArticleID, model.ARTICLE_STATE_PUBLISHED)
+	if err != nil {
+		return nil, err
+	}
+	if article.Model == nil || article.ID == 0 {
+		return nil, ErrArticleNotFound
+	}
+
+	if param.ParentID > 0 {
+		parent, err := svc.dao.GetComment(param.ParentID, param.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Model == nil || parent.ID == 0 {
+			return nil, ErrCommentNotFound
+		}
+	}
+
+	comment, err := svc.dao.CreateComment(param.ArticleID, param.ParentID, param.Content, model.COMMENT_STATE_PENDING, param.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return newComment(comment), nil
+}
+
+func (svc *Service) UpdateCommentState(param *UpdateCommentStateRequest) error {
+	comment, err := svc.dao.GetComment(param.ID, param.ArticleID)
+	if err != nil {
+		return err
+	}
+	if comment.Model == nil || comment.ID == 0 {
+		return ErrCommentNotFound
+	}
+
+	return svc.dao.UpdateCommentState(param.ID, param.ArticleID, *param.State, param.ModifiedBy)
+}
+
+// 删除评论及其下的全部回复
+func (svc *Service) DeleteComment(param *DeleteCommentRequest) error {
+	return svc.dao.Transaction(func(tx *dao.Dao) error {
+		comment, err := tx.GetComment(param.ID, param.ArticleID)
+		if err != nil {
+			return err
+		}
+		if comment.Model == nil || comment.ID == 0 {
+			return ErrCommentNotFound
+		}
+
+		ids := []uint32{comment.ID}
+		parentIDs := ids
+		for len(parentIDs) > 0 {
+			parentIDs, err = tx.GetCommentReplyIDs(param.ArticleID, parentIDs)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, parentIDs...)
+		}
+
+		return tx.DeleteComments(ids, param.ArticleID)
+	})
+}
+
+func buildCommentTree(comment *model.Comment, children map[uint32][]*model.Comment) *Comment {
+	node := newComment(comment)
+	for _, child := range children[comment.ID] {
+		node.Replies = append(node.Replies, buildCommentTree(child, children))
+	}
+	return node
+}
+
+func newComment(comment *model.Comment) *Comment {
+	return &Comment{
+		ID:        comment.ID,
+		ArticleID: comment.ArticleID,
+		ParentID:  comment.ParentID,
+		Content:   comment.Content,
+		State:     comment.State,
+		CreatedBy: comment.CreatedBy,
+		CreatedOn: comment.CreatedOn,
+		Replies:   []*Comment{},
+	}
+}
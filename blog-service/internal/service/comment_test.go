@@ -0,0 +1,183 @@
+package service
+
+import (
+	"testing"
+
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func createTestComment(t *testing.T, svc Service, articleID, parentID uint32, state uint8) *model.Comment {
+	t.Helper()
+
+	comment, err := svc.dao.CreateComment(articleID, parentID, "comment", state, "reader")
+	if err != nil {
+		t.Fatalf("CreateComment err: %v", err)
+	}
+	return comment
+}
+
+func TestGetCommentListModerator(t *testing.T) {
+	svc := newTestService(t)
+	article := createTestArticle(t, svc, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+	createTestComment(t, svc, article.ID, 0, model.COMMENT_STATE_PENDING)
+
+	tests := []struct {
+		name      string
+		state     uint8
+		moderator bool
+		wantErr   error
+		wantRows  int
+	}{
+		{"approved", model.COMMENT_STATE_APPROVED, false, nil, 0},
+		{"pending", model.COMMENT_STATE_PENDING, false, ErrCommentForbidden, 0},
+		{"rejected", model.COMMENT_STATE_REJECTED, false, ErrCommentForbidden, 0},
+		{"pending by moderator", model.COMMENT_STATE_PENDING, true, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := &CommentListRequest{ArticleID: article.ID, State: tt.state, Moderator: tt.moderator}
+			_, totalRows, err := svc.GetCommentList(param, &app.Pager{Page: 1, PageSize: 10})
+			if err != tt.wantErr {
+				t.Fatalf("GetCommentList err = %v, want %v", err, tt.wantErr)
+			}
+			if totalRows != tt.wantRows {
+				t.Errorf("GetCommentList totalRows = %d, want %d", totalRows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestGetCommentListPagesReplies(t *testing.T) {
+	svc := newTestService(t)
+	article := createTestArticle(t, svc, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+
+	first := createTestComment(t, svc, article.ID, 0, model.COMMENT_STATE_APPROVED)
+	second := createTestComment(t, svc, article.ID, 0, model.COMMENT_STATE_APPROVED)
+	reply := createTestComment(t, svc, article.ID, first.ID, model.COMMENT_STATE_APPROVED)
+	nested := createTestComment(t, svc, article.ID, reply.ID, model.COMMENT_STATE_APPROVED)
+	createTestComment(t, svc, article.ID, first.ID, model.COMMENT_STATE_PENDING)
+	createTestComment(t, svc, article.ID, second.ID, model.COMMENT_STATE_APPROVED)
+
+	param := &CommentListRequest{ArticleID: article.ID, State: model.COMMENT_STATE_APPROVED}
+	comments, totalRows, err := svc.GetCommentList(param, &app.Pager{Page: 1, PageSize: 1})
+	if err != nil {
+		t.Fatalf("GetCommentList err: %v", err)
+	}
+	if totalRows != 2 || len(comments) != 1 || comments[0].ID != first.ID {
+		t.Fatalf("GetCommentList = %+v, %d, want the first of 2 root comments", comments, totalRows)
+	}
+
+	replies := comments[0].Replies
+	if len(replies) != 1 || replies[0].ID != reply.ID {
+		t.Fatalf("replies = %+v, want only the approved reply %d", replies, reply.ID)
+	}
+	if len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != nested.ID {
+		t.Errorf("nested replies = %+v, want %d", replies[0].Replies, nested.ID)
+	}
+}
+
+// 回复按层数和总数截断, 超出限制的回复不返回
+func TestGetCommentListCapsReplies(t *testing.T) {
+	svc := newTestService(t)
+
+	deepArticle := createTestArticle(t, svc, "Deep", model.ARTICLE_STATE_PUBLISHED, 0)
+	parentID := uint32(0)
+	for i := 0; i <= maxCommentReplyDepth+1; i++ {
+		parentID = createTestComment(t, svc, deepArticle.ID, parentID, model.COMMENT_STATE_APPROVED).ID
+	}
+	wideArticle := createTestArticle(t, svc, "Wide", model.ARTICLE_STATE_PUBLISHED, 0)
+	wide := createTestComment(t, svc, wideArticle.ID, 0, model.COMMENT_STATE_APPROVED)
+	for i := 0; i <= maxCommentReplies; i++ {
+		createTestComment(t, svc, wideArticle.ID, wide.ID, model.COMMENT_STATE_APPROVED)
+	}
+
+	getRoot := func(articleID uint32) *Comment {
+		t.Helper()
+
+		param := &CommentListRequest{ArticleID: articleID, State: model.COMMENT_STATE_APPROVED}
+		comments, _, err := svc.GetCommentList(param, &app.Pager{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("GetCommentList err: %v", err)
+		}
+		if len(comments) != 1 {
+			t.Fatalf("GetCommentList = %d comments, want 1", len(comments))
+		}
+		return comments[0]
+	}
+
+	depth := 0
+	for node := getRoot(deepArticle.ID); len(node.Replies) > 0; node = node.Replies[0] {
+		depth++
+	}
+	if depth != maxCommentReplyDepth {
+		t.Errorf("reply depth = %d, want %d", depth, maxCommentReplyDepth)
+	}
+	if got := len(getRoot(wideArticle.ID).Replies); got != maxCommentReplies {
+		t.Errorf("replies = %d, want %d", got, maxCommentReplies)
+	}
+}
+
+// 0为待审核状态, 必须能够通过校验, 未传入state时校验失败
+func TestUpdateCommentStateRequestValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *uint8
+		valid bool
+	}{
+		{"pending", uint8Ptr(model.COMMENT_STATE_PENDING), true},
+		{"rejected", uint8Ptr(model.COMMENT_STATE_REJECTED), true},
+		{"missing", nil, false},
+		{"invalid", uint8Ptr(3), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := &UpdateCommentStateRequest{ID: 1, ArticleID: 1, State: tt.state, ModifiedBy: "admin"}
+			err := binding.Validator.ValidateStruct(param)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateStruct err = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	svc := newTestService(t)
+	article := createTestArticle(t, svc, "Hello", model.ARTICLE_STATE_PUBLISHED, 0)
+
+	root := createTestComment(t, svc, article.ID, 0, model.COMMENT_STATE_APPROVED)
+	reply := createTestComment(t, svc, article.ID, root.ID, model.COMMENT_STATE_PENDING)
+	nested := createTestComment(t, svc, article.ID, reply.ID, model.COMMENT_STATE_APPROVED)
+	other := createTestComment(t, svc, article.ID, 0, model.COMMENT_STATE_APPROVED)
+
+	if err := svc.DeleteComment(&DeleteCommentRequest{ID: root.ID, ArticleID: article.ID}); err != nil {
+		t.Fatalf("DeleteComment err: %v", err)
+	}
+	for _, id := range []uint32{root.ID, reply.ID, nested.ID} {
+		comment, err := svc.dao.GetComment(id, article.ID)
+		if err != nil {
+			t.Fatalf("GetComment(%d) err: %v", id, err)
+		}
+		if comment.Model != nil {
+			t.Errorf("GetComment(%d) = %+v after deleting its root, want not found", id, comment)
+		}
+	}
+	comment, err := svc.dao.GetComment(other.ID, article.ID)
+	if err != nil {
+		t.Fatalf("GetComment(%d) err: %v", other.ID, err)
+	}
+	if comment.Model == nil {
+		t.Errorf("GetComment(%d) not found, want the unrelated comment kept", other.ID)
+	}
+
+	err = svc.DeleteComment(&DeleteCommentRequest{ID: root.ID, ArticleID: article.ID})
+	if err != ErrCommentNotFound {
+		t.Errorf("DeleteComment deleted comment err = %v, want %v", err, ErrCommentNotFound)
+	}
+	err = svc.DeleteComment(&DeleteCommentRequest{ID: other.ID, ArticleID: article.ID + 1})
+	if err != ErrCommentNotFound {
+		t.Errorf("DeleteComment other article err = %v, want %v", err, ErrCommentNotFound)
+	}
+}
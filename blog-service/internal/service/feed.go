@@ -0,0 +1,142 @@
+package service
+
+import (
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/feed"
+	"blog-service/pkg/setting"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+// 未配置FeedSize时订阅源中的文章数量
+const defaultFeedSize = 20
+
+type FeedRequest struct {
+	TagID uint32 `form:"tag_id"`
+}
+
+// 获取已发布文章的订阅源, 指定tag_id时只包含该标签下的文章, 返回内容的最后修改时间
+func (svc *Service) GetFeed(param *FeedRequest) (*feed.Feed, time.Time, error) {
+	setting.RLock()
+	site := *global.SiteSetting
+	setting.RUnlock()
+
+	if site.FeedSize <= 0 {
+		site.FeedSize = defaultFeedSize
+	}
+
+	baseUrl := strings.TrimRight(site.BaseUrl, "/")
+	f := &feed.Feed{
+		Title:       site.Title,
+		Link:        baseUrl,
+		Description: site.Description,
+	}
+	if param.TagID > 0 {
+		tag, err := svc.dao.GetTag(param.TagID, model.STATE_OPEN)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if tag.Model == nil || tag.ID == 0 {
+			return nil, time.Time{}, ErrTagNotFound
+		}
+		f.Title = fmt.Sprintf("%s - %s", site.Title, tag.Name)
+		f.Link = fmt.Sprintf("%s/tags/%d", baseUrl, tag.ID)
+	}
+
+	articles, err := svc.dao.GetLatestArticleList(param.TagID, []uint8{model.ARTICLE_STATE_PUBLISHED}, site.FeedSize)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	f.Items = make([]*feed.Item, 0, len(articles))
+	for _, article := range articles {
+		link := articleUrl(baseUrl, article.ID)
+		item := &feed.Item{
+			ID:          link,
+			Title:       article.Title,
+			Link:        link,
+			Description: article.Desc,
+			Author:      article.CreatedBy,
+			Published:   articlePublishedAt(article),
+			Updated:     articleUpdatedAt(article),
+		}
+		if item.Updated.After(f.Updated) {
+			f.Updated = item.Updated
+		}
+		f.Items = append(f.Items, item)
+	}
+
+	lastModified, err := svc.contentLastModified(f.Updated)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return f, lastModified, nil
+}
+
+// 获取sitemap中的站点首页和全部已发布的文章, 返回内容的最后修改时间
+func (svc *Service) GetSitemap() ([]*feed.URL, time.Time, error) {
+	setting.RLock()
+	baseUrl := strings.TrimRight(global.SiteSetting.BaseUrl, "/")
+	setting.RUnlock()
+
+	articles, err := svc.dao.GetLatestArticleList(0, []uint8{model.ARTICLE_STATE_PUBLISHED}, feed.MaxSitemapURLs-1)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var lastModified time.Time
+	urls := make([]*feed.URL, 0, len(articles)+1)
+	urls = append(urls, &feed.URL{Loc: baseUrl + "/"})
+	for _, article := range articles {
+		updated := articleUpdatedAt(article)
+		if updated.After(lastModified) {
+			lastModified = updated
+		}
+		urls = append(urls, &feed.URL{Loc: articleUrl(baseUrl, article.ID), LastMod: updated})
+	}
+	urls[0].LastMod = lastModified
+
+	lastModified, err = svc.contentLastModified(lastModified)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return urls, lastModified, nil
+}
+
+// 条件请求使用的最后修改时间, 在内容中最新的时间基础上考虑取消发布和删除等不再出现在内容中的修改
+func (svc *Service) contentLastModified(updated time.Time) (time.Time, error) {
+	changedOn, err := svc.dao.GetContentLastChangedOn()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if changed := time.Unix(int64(changedOn), 0); changed.After(updated) {
+		return changed, nil
+	}
+	return updated, nil
+}
+
+func articleUrl(baseUrl string, id uint32) string {
+	return fmt.Sprintf("%s/articles/%d", baseUrl, id)
+}
+
+// 旧文章没有记录发布时间, 以创建时间代替
+func articlePublishedAt(article *model.Article) time.Time {
+	if article.PublishAt > 0 {
+		return time.Unix(int64(article.PublishAt), 0)
+	}
+	return time.Unix(int64(article.CreatedOn), 0)
+}
+
+func articleUpdatedAt(article *model.Article) time.Time {
+	published := articlePublishedAt(article)
+	modified := time.Unix(int64(article.ModifiedOn), 0)
+	if modified.After(published) {
+		return modified
+	}
+	return published
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/setting"
+)
+
+func setupSiteSetting(t *testing.T) {
+	t.Helper()
+
+	siteSetting := global.SiteSetting
+	global.SiteSetting = &setting.SiteSettingS{BaseUrl: "https://example.com/", Title: "blog", FeedSize: 10}
+	t.Cleanup(func() { global.SiteSetting = siteSetting })
+}
+
+// 将文章的修改时间改到过去, 使之后的修改一定晚于订阅源原来的最后修改时间
+func backdateArticles(t *testing.T, at time.Time) {
+	t.Helper()
+
+	for _, table := range []string{model.Article{}.TableName(), model.Tag{}.TableName(), model.ArticleTag{}.TableName()} {
+		err := global.DBEngine.Table(table).Where("1 = 1").
+			UpdateColumns(map[string]interface{}{"created_on": at.Unix(), "modified_on": at.Unix()}).Error
+		if err != nil {
+			t.Fatalf("backdate %s err: %v", table, err)
+		}
+	}
+}
+
+func TestGetFeedLastModified(t *testing.T) {
+	svc := newTestService(t)
+	setupSiteSetting(t)
+
+	past := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	first := createTestArticle(t, svc, "first", model.ARTICLE_STATE_PUBLISHED, uint32(past.Unix()))
+	second := createTestArticle(t, svc, "second", model.ARTICLE_STATE_PUBLISHED, uint32(past.Unix()))
+	backdateArticles(t, past)
+
+	f, lastModified, err := svc.GetFeed(&FeedRequest{})
+	if err != nil {
+		t.Fatalf("GetFeed err: %v", err)
+	}
+	if len(f.Items) != 2 || !lastModified.Equal(past) {
+		t.Fatalf("GetFeed = %d items, last modified %v, want 2 items at %v", len(f.Items), lastModified, past)
+	}
+	if f.Items[0].Link != articleUrl("https://example.com", second.ID) || f.Items[0].Author != "tester" {
+		t.Errorf("first item = %+v, want the newest article by tester", f.Items[0])
+	}
+
+	// 取消发布的文章不再出现在订阅源中, 最后修改时间随之更新
+	err = svc.UpdateArticle(&UpdateArticleRequest{ID: first.ID, State: uint8Ptr(model.ARTICLE_STATE_DRAFT), ModifiedBy: "editor"})
+	if err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	f, unpublished, err := svc.GetFeed(&FeedRequest{})
+	if err != nil {
+		t.Fatalf("GetFeed err: %v", err)
+	}
+	if len(f.Items) != 1 || !unpublished.After(past) {
+		t.Errorf("GetFeed after unpublish = %d items, last modified %v, want 1 item after %v", len(f.Items), unpublished, past)
+	}
+
+	// 删除同样会更新sitemap的最后修改时间
+	backdateArticles(t, past)
+	if _, lastModified, err := svc.GetSitemap(); err != nil || !lastModified.Equal(past) {
+		t.Fatalf("GetSitemap = %v, %v, want %v", lastModified, err, past)
+	}
+	if err := svc.DeleteArticle(&DeleteArticleRequest{ID: second.ID}); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+	urls, deleted, err := svc.GetSitemap()
+	if err != nil {
+		t.Fatalf("GetSitemap err: %v", err)
+	}
+	if len(urls) != 1 || !deleted.After(past) {
+		t.Errorf("GetSitemap after delete = %d urls, last modified %v, want only the home page after %v", len(urls), deleted, past)
+	}
+}
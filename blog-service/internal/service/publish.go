@@ -0,0 +1,44 @@
+package service
+
+import (
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"time"
+)
+
+// 定时发布时记录的修改者
+const publishOperator = "scheduler"
+
+// 将已到发布时间的定时发布文章改为已发布, 每次最多处理limit篇, 返回发布的文章数量
+func (svc *Service) PublishDueArticles(limit int) (int, error) {
+	var articleIDs []uint32
+	err := svc.dao.Transaction(func(tx *dao.Dao) error {
+		articles, err := tx.LockDueScheduledArticles(uint32(time.Now().Unix()), limit)
+		if err != nil {
+			return err
+		}
+
+		published := uint8(model.ARTICLE_STATE_PUBLISHED)
+		for _, article := range articles {
+			err := updateArticleWithRevision(tx, &dao.Article{
+				ID:         article.ID,
+				PublishAt:  article.PublishAt,
+				ModifiedBy: publishOperator,
+			}, &published)
+			if err != nil {
+				return err
+			}
+			articleIDs = append(articleIDs, article.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range articleIDs {
+		svc.invalidateArticleCache(id)
+		svc.indexArticle(id)
+	}
+	return len(articleIDs), nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/search"
+	"errors"
+)
+
+// 对账时每批读取的文章数量
+const reindexBatchSize = 200
+
+type SearchArticleRequest struct {
+	Q     string `form:"q" binding:"required,min=1,max=100"`
+	State uint8  `form:"state,default=1" binding:"oneof=0 1 2 3"`
+}
+
+type SearchArticle struct {
+	*Article
+	Score      float64             `json:"score"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+func (svc *Service) SearchArticles(param *SearchArticleRequest, pager *app.Pager) ([]*SearchArticle, int, error) {
+	if global.SearchIndexer == nil {
+		return nil, 0, errors.New("search indexer is not initialized")
+	}
+
+	result, err := global.SearchIndexer.Search(&search.Request{
+		Query:  param.Q,
+		State:  param.State,
+		Offset: app.GetPageOffset(pager.Page, pager.PageSize),
+		Size:   pager.PageSize,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(result.Hits) == 0 {
+		return []*SearchArticle{}, result.Total, nil
+	}
+
+	ids := make([]uint32, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	articles, err := svc.dao.GetArticleListByIDs(ids, param.State)
+	if err != nil {
+		return nil, 0, err
+	}
+	tags, err := svc.getArticleTags(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	articleMap := make(map[uint32]*model.Article, len(articles))
+	for _, article := range articles {
+		articleMap[article.ID] = article
+	}
+
+	// 按检索结果的相关度排序, 跳过索引中存在但数据库中已不存在的文章
+	articleList := make([]*SearchArticle, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		article, ok := articleMap[hit.ID]
+		if !ok {
+			continue
+		}
+		articleList = append(articleList, &SearchArticle{
+			Article: &Article{
+				ID:            article.ID,
+				Title:         article.Title,
+				Desc:          article.Desc,
+				Content:       article.Content,
+				CoverImageUrl: article.CoverImageUrl,
+				State:         article.State,
+				PublishAt:     article.PublishAt,
+				Tags:          tags[article.ID],
+			},
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		})
+	}
+
+	return articleList, result.Total, nil
+}
+
+// 使全文检索索引与数据库一致: 重新索引缺少或modified_on与数据库不同的文章, 并删除数据库中已不存在的文章
+// 返回重新索引的文章数量和删除的文档数量
+func (svc *Service) SyncSearchIndex() (indexed, removed int, err error) {
+	versions, err := global.SearchIndexer.Versions()
+	if err != nil {
+		return 0, 0, err
+	}
+	stale := make(map[uint32]bool, len(versions))
+	for id := range versions {
+		stale[id] = true
+	}
+
+	for page := 1; ; page++ {
+		articles, err := svc.dao.GetArticleList(page, reindexBatchSize)
+		if err != nil {
+			return indexed, removed, err
+		}
+		for _, article := range articles {
+			delete(stale, article.ID)
+			if modifiedOn, ok := versions[article.ID]; ok && modifiedOn == article.ModifiedOn {
+				continue
+			}
+			if err := global.SearchIndexer.Index(newSearchDocument(article)); err != nil {
+				return indexed, removed, err
+			}
+			indexed++
+		}
+		if len(articles) < reindexBatchSize {
+			break
+		}
+	}
+
+	for id := range stale {
+		if err := global.SearchIndexer.Delete(id); err != nil {
+			return indexed, removed, err
+		}
+		removed++
+	}
+	return indexed, removed, nil
+}
+
+// 同步文章到全文检索索引, 索引失败不影响文章本身的写入
+func (svc *Service) indexArticle(id uint32) {
+	if global.SearchIndexer == nil {
+		return
+	}
+
+	article, err := svc.dao.GetArticleByID(id)
+	if err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("svc.indexArticle err: %v", err)
+		return
+	}
+	if article.Model == nil || article.ID == 0 {
+		svc.unindexArticle(id)
+		return
+	}
+
+	if err := global.SearchIndexer.Index(newSearchDocument(&article)); err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("SearchIndexer.Index err: %v", err)
+	}
+}
+
+func (svc *Service) unindexArticle(id uint32) {
+	if global.SearchIndexer == nil {
+		return
+	}
+
+	if err := global.SearchIndexer.Delete(id); err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("SearchIndexer.Delete err: %v", err)
+	}
+}
+
+func newSearchDocument(article *model.Article) *search.Document {
+	return &search.Document{
+		ID:         article.ID,
+		Title:      article.Title,
+		Desc:       article.Desc,
+		Content:    article.Content,
+		State:      article.State,
+		ModifiedOn: article.ModifiedOn,
+	}
+}
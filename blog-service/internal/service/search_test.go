@@ -0,0 +1,154 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"blog-service/global"
+	"blog-service/internal/model"
+	"blog-service/pkg/app"
+	"blog-service/pkg/search"
+)
+
+// 为测试替换全局的检索索引, newTestService会在测试结束时恢复
+func useTestIndexer(t *testing.T) *search.BleveIndexer {
+	t.Helper()
+
+	indexer, err := search.NewBleveIndexer("")
+	if err != nil {
+		t.Fatalf("NewBleveIndexer err: %v", err)
+	}
+	t.Cleanup(func() { indexer.Close() })
+	global.SearchIndexer = indexer
+	return indexer
+}
+
+func searchTestArticles(t *testing.T, svc Service, q string) []uint32 {
+	t.Helper()
+
+	articles, _, err := svc.SearchArticles(&SearchArticleRequest{Q: q, State: model.ARTICLE_STATE_PUBLISHED}, &app.Pager{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("SearchArticles(%s) err: %v", q, err)
+	}
+	ids := make([]uint32, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+	return ids
+}
+
+func indexedVersions(t *testing.T, indexer search.Indexer) map[uint32]uint32 {
+	t.Helper()
+
+	versions, err := indexer.Versions()
+	if err != nil {
+		t.Fatalf("Versions err: %v", err)
+	}
+	return versions
+}
+
+// 已有的索引与数据库不一致时, 对账会补齐缺少的文章、更新过期的内容并删除多余的文档
+func TestSyncSearchIndexReconciles(t *testing.T) {
+	svc := newTestService(t)
+	indexer := useTestIndexer(t)
+
+	kept := createTestArticle(t, svc, "kept", model.ARTICLE_STATE_PUBLISHED, 1000)
+	unchanged := createTestArticle(t, svc, "unchanged", model.ARTICLE_STATE_PUBLISHED, 1000)
+	missing := createTestArticle(t, svc, "missing", model.ARTICLE_STATE_PUBLISHED, 1000)
+	deleted := createTestArticle(t, svc, "deleted", model.ARTICLE_STATE_PUBLISHED, 1000)
+	if err := svc.dao.DeleteArticle(deleted.ID); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+
+	// 模拟停机期间错过的修改: 索引中是旧版本的标题, 缺少一篇文章, 并保留了已删除和不存在的文章
+	// modified_on与数据库相同的文档视为最新, 即使内容不同也不会重新索引
+	stale := []*search.Document{
+		{ID: kept.ID, Title: "outdated", State: model.ARTICLE_STATE_PUBLISHED, ModifiedOn: kept.ModifiedOn - 1},
+		{ID: unchanged.ID, Title: "untouched", State: model.ARTICLE_STATE_PUBLISHED, ModifiedOn: unchanged.ModifiedOn},
+		{ID: deleted.ID, Title: "deleted", State: model.ARTICLE_STATE_PUBLISHED, ModifiedOn: deleted.ModifiedOn},
+		{ID: 999, Title: "orphan", State: model.ARTICLE_STATE_PUBLISHED},
+	}
+	for _, doc := range stale {
+		if err := indexer.Index(doc); err != nil {
+			t.Fatalf("Index(%d) err: %v", doc.ID, err)
+		}
+	}
+
+	indexed, removed, err := svc.SyncSearchIndex()
+	if err != nil {
+		t.Fatalf("SyncSearchIndex err: %v", err)
+	}
+	if indexed != 2 || removed != 2 {
+		t.Errorf("SyncSearchIndex = %d, %d, want 2, 2", indexed, removed)
+	}
+
+	want := map[uint32]uint32{kept.ID: kept.ModifiedOn, unchanged.ID: unchanged.ModifiedOn, missing.ID: missing.ModifiedOn}
+	if versions := indexedVersions(t, indexer); !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions = %v, want %v", versions, want)
+	}
+	if got := searchTestArticles(t, svc, "outdated"); len(got) != 0 {
+		t.Errorf("search outdated = %v, want none", got)
+	}
+	if got := searchTestArticles(t, svc, "kept"); !reflect.DeepEqual(got, []uint32{kept.ID}) {
+		t.Errorf("search kept = %v, want [%d]", got, kept.ID)
+	}
+	if got := searchTestArticles(t, svc, "untouched"); !reflect.DeepEqual(got, []uint32{unchanged.ID}) {
+		t.Errorf("search untouched = %v, want [%d] from the document that was not reindexed", got, unchanged.ID)
+	}
+
+	articles, _, err := svc.SearchArticles(&SearchArticleRequest{Q: "kept", State: model.ARTICLE_STATE_PUBLISHED}, &app.Pager{Page: 1, PageSize: 10})
+	if err != nil || len(articles) != 1 || articles[0].PublishAt != 1000 {
+		t.Errorf("SearchArticles(kept) = %+v, %v, want the article published at 1000", articles, err)
+	}
+
+	// 再次对账时没有需要重新索引或删除的文档
+	if indexed, removed, err := svc.SyncSearchIndex(); err != nil || indexed != 0 || removed != 0 {
+		t.Errorf("SyncSearchIndex again = %d, %d, %v, want 0, 0, nil", indexed, removed, err)
+	}
+}
+
+// 文章的新增、修改和删除同步到索引
+func TestSearchArticlesFollowsWrites(t *testing.T) {
+	svc := newTestService(t)
+	useTestIndexer(t)
+
+	tag, err := svc.dao.CreateTag("Go", model.STATE_OPEN, "tester")
+	if err != nil {
+		t.Fatalf("CreateTag err: %v", err)
+	}
+	err = svc.CreateArticle(&CreateArticleRequest{
+		TagIDs:        []uint32{tag.ID},
+		Title:         "golang",
+		Desc:          "golang desc",
+		Content:       "golang content",
+		CoverImageUrl: "https://example.com/cover.png",
+		CreatedBy:     "tester",
+		State:         model.ARTICLE_STATE_PUBLISHED,
+	})
+	if err != nil {
+		t.Fatalf("CreateArticle err: %v", err)
+	}
+	ids := searchTestArticles(t, svc, "golang")
+	if len(ids) != 1 {
+		t.Fatalf("search golang = %v, want one article", ids)
+	}
+	id := ids[0]
+
+	err = svc.UpdateArticle(&UpdateArticleRequest{ID: id, Title: "rust", Desc: "rust desc", Content: "rust content", ModifiedBy: "editor"})
+	if err != nil {
+		t.Fatalf("UpdateArticle err: %v", err)
+	}
+	if got := searchTestArticles(t, svc, "golang"); len(got) != 0 {
+		t.Errorf("search golang after update = %v, want none", got)
+	}
+	if got := searchTestArticles(t, svc, "rust"); !reflect.DeepEqual(got, []uint32{id}) {
+		t.Errorf("search rust = %v, want [%d]", got, id)
+	}
+
+	if err := svc.DeleteArticle(&DeleteArticleRequest{ID: id}); err != nil {
+		t.Fatalf("DeleteArticle err: %v", err)
+	}
+	if got := searchTestArticles(t, svc, "rust"); len(got) != 0 {
+		t.Errorf("search rust after delete = %v, want none", got)
+	}
+}
@@ -13,6 +13,6 @@ type Service struct {
 
 func New(ctx context.Context) Service {
 	svc := Service{ctx: ctx}
-	svc.dao = dao.New(global.DBEngine)
+	svc.dao = dao.New(global.DBEngine.WithContext(ctx))
 	return svc
 }
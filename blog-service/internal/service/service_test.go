@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"blog-service/global"
+	"blog-service/internal/dao"
+	"blog-service/internal/migration"
+	"blog-service/internal/model"
+	"blog-service/pkg/setting"
+)
+
+// 每个测试使用独立的SQLite内存数据库, 并替换测试期间用到的全局变量
+func newTestService(t *testing.T) Service {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := model.NewDBEngine(&setting.DatabaseSettingS{
+		DBType:       "sqlite",
+		DBName:       fmt.Sprintf("file:svc_%s?mode=memory&cache=shared", name),
+		MaxIdleConns: 1,
+		MaxOpenConns: 1,
+	})
+	if err != nil {
+		t.Fatalf("model.NewDBEngine err: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("db.DB err: %v", err)
+	}
+	if _, err := migration.Up(db); err != nil {
+		t.Fatalf("migration.Up err: %v", err)
+	}
+
+	dbEngine, cache, cacheSetting, indexer := global.DBEngine, global.Cache, global.CacheSetting, global.SearchIndexer
+	global.DBEngine = db
+	global.Cache = nil
+	global.CacheSetting = &setting.CacheSettingS{}
+	global.SearchIndexer = nil
+	t.Cleanup(func() {
+		global.DBEngine, global.Cache, global.CacheSetting, global.SearchIndexer = dbEngine, cache, cacheSetting, indexer
+		sqlDB.Close()
+	})
+
+	return New(context.Background())
+}
+
+// 绕过service的校验直接写入文章, 用于构造已过发布时间的定时发布文章
+func createTestArticle(t *testing.T, svc Service, title string, state uint8, publishAt uint32) *model.Article {
+	t.Helper()
+
+	article, err := svc.dao.CreateArticle(&dao.Article{
+		Title:         title,
+		Desc:          title + " desc",
+		Content:       "# " + title,
+		CoverImageUrl: "https://example.com/cover.png",
+		CreatedBy:     "tester",
+		State:         state,
+		PublishAt:     publishAt,
+	})
+	if err != nil {
+		t.Fatalf("CreateArticle(%s) err: %v", title, err)
+	}
+	return article
+}
+
+func getTestArticle(t *testing.T, svc Service, id uint32) model.Article {
+	t.Helper()
+
+	article, err := svc.dao.GetArticleByID(id)
+	if err != nil {
+		t.Fatalf("GetArticleByID(%d) err: %v", id, err)
+	}
+	if article.Model == nil || article.ID == 0 {
+		t.Fatalf("GetArticleByID(%d) not found", id)
+	}
+	return article
+}
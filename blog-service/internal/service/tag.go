@@ -33,21 +33,48 @@ type DeleteTagRequest struct {
 }
 
 func (svc *Service) CountTag(param *CountTagRequest) (int, error) {
-	return svc.dao.CountTag(param.Name, param.State)
+	var count int
+	_, ttl := cacheTTL()
+	err := svc.cacheLoad(svc.tagListCacheKey("count", param.Name, param.State, 0, 0), ttl, &count, func(svc *Service) (interface{}, error) {
+		return svc.dao.CountTag(param.Name, param.State)
+	})
+	return count, err
 }
 
 func (svc *Service) GetTagList(param *TagListRequest, pager *app.Pager) ([]*model.Tag, error) {
-	return svc.dao.GetTagList(param.Name, param.State, pager.Page, pager.PageSize)
+	var tags []*model.Tag
+	_, ttl := cacheTTL()
+	key := svc.tagListCacheKey("list", param.Name, param.State, pager.Page, pager.PageSize)
+	err := svc.cacheLoad(key, ttl, &tags, func(svc *Service) (interface{}, error) {
+		return svc.dao.GetTagList(param.Name, param.State, pager.Page, pager.PageSize)
+	})
+	return tags, err
 }
 
+// 标签变更后更新标签版本号, 使标签列表和文章详情的缓存失效
 func (svc *Service) CreateTag(param *CreateTagRequest) error {
-	return svc.dao.CreateTag(param.Name, param.State, param.CreatedBy)
+	if _, err := svc.dao.CreateTag(param.Name, param.State, param.CreatedBy); err != nil {
+		return err
+	}
+
+	svc.bumpTagVersion()
+	return nil
 }
 
 func (svc *Service) UpdateTag(param *UpdateTagRequest) error {
-	return svc.dao.UpdateTag(param.ID, param.Name, param.State, param.ModifiedBy)
+	if err := svc.dao.UpdateTag(param.ID, param.Name, param.State, param.ModifiedBy); err != nil {
+		return err
+	}
+
+	svc.bumpTagVersion()
+	return nil
 }
 
 func (svc *Service) DeleteTag(param *DeleteTagRequest) error {
-	return svc.dao.DeleteTag(param.ID)
+	if err := svc.dao.DeleteTag(param.ID); err != nil {
+		return err
+	}
+
+	svc.bumpTagVersion()
+	return nil
 }
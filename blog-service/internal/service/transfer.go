@@ -0,0 +1,380 @@
+package service
+
+import (
+	"blog-service/internal/dao"
+	"blog-service/internal/model"
+	"blog-service/pkg/markdown"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 导出时每批读取的记录数量
+const exportBatchSize = 200
+
+type ExportRequest struct {
+	Format string `form:"format,default=json" binding:"oneof=json csv"`
+}
+
+type ImportRequest struct {
+	Format     string `form:"format,default=json" binding:"oneof=json csv"`
+	DryRun     bool   `form:"dry_run"`
+	ModifiedBy string `form:"modified_by" binding:"required,min=2,max=100"`
+}
+
+// 导入结果, 试运行时为将要执行的变更
+type ImportReport struct {
+	DryRun      bool              `json:"dry_run"`
+	Tags        ImportStat        `json:"tags"`
+	Articles    ImportStat        `json:"articles"`
+	ArticleTags int               `json:"article_tags"`
+	Conflicts   []*ImportConflict `json:"conflicts"`
+}
+
+type ImportStat struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// 与已有数据同名但内容不同的记录, 导入时会覆盖已有数据中的这些字段
+type ImportConflict struct {
+	Type       string   `json:"type"`
+	ID         uint32   `json:"id"`
+	Key        string   `json:"key"`
+	ExistingID uint32   `json:"existing_id"`
+	Fields     []string `json:"fields"`
+}
+
+// 试运行时用于回滚事务
+var errImportDryRun = errors.New("import dry run")
+
+// 按标签、文章、文章标签关联的顺序写出全部未删除的数据
+func (svc *Service) Export(format string, w io.Writer) error {
+	enc := newTransferEncoder(format, w)
+
+	for page := 1; ; page++ {
+		tags, err := svc.dao.GetAllTagList(page, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			err := enc.Tag(&TransferTag{ID: tag.ID, Name: tag.Name, State: tag.State, CreatedBy: tag.CreatedBy})
+			if err != nil {
+				return err
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+		if len(tags) < exportBatchSize {
+			break
+		}
+	}
+
+	// 文章标签关联需要在全部文章之后写出, 只保存ID, 占用的内存很少
+	var articleTags []*TransferArticleTag
+	for page := 1; ; page++ {
+		articles, err := svc.dao.GetArticleList(page, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		articleIDs := make([]uint32, 0, len(articles))
+		for _, article := range articles {
+			articleIDs = append(articleIDs, article.ID)
+			err := enc.Article(&TransferArticle{
+				ID:            article.ID,
+				Title:         article.Title,
+				Desc:          article.Desc,
+				Content:       article.Content,
+				CoverImageUrl: article.CoverImageUrl,
+				State:         article.State,
+				PublishAt:     article.PublishAt,
+				CreatedBy:     article.CreatedBy,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			return err
+		}
+
+		if len(articleIDs) > 0 {
+			links, err := svc.dao.GetArticleTagListByAIDs(articleIDs)
+			if err != nil {
+				return err
+			}
+			for _, link := range links {
+				articleTags = append(articleTags, &TransferArticleTag{ArticleID: link.ArticleID, TagID: link.TagID})
+			}
+		}
+		if len(articles) < exportBatchSize {
+			break
+		}
+	}
+
+	for _, articleTag := range articleTags {
+		if err := enc.ArticleTag(articleTag); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+// 在一个事务中导入数据, 标签按名称、文章按标题匹配已有的数据, 存在时更新, 不存在时创建
+// 试运行时执行同样的操作后回滚, 返回将要执行的变更和冲突
+func (svc *Service) Import(param *ImportRequest, r io.Reader) (*ImportReport, error) {
+	data, err := decodeTransferData(param.Format, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTransferData(data); err != nil {
+		return nil, err
+	}
+
+	var report *ImportReport
+	var articleIDs []uint32
+	err = svc.dao.Transaction(func(tx *dao.Dao) error {
+		report = &ImportReport{DryRun: param.DryRun, Conflicts: []*ImportConflict{}}
+		tagIDs, err := importTags(tx, data.Tags, param.ModifiedBy, report)
+		if err != nil {
+			return err
+		}
+		articleIDMap, err := importArticles(tx, data.Articles, param.ModifiedBy, report)
+		if err != nil {
+			return err
+		}
+
+		// 导入的文章的标签关联与导入的数据保持一致
+		linkedTags := map[uint32][]uint32{}
+		for _, articleTag := range data.ArticleTags {
+			linkedTags[articleTag.ArticleID] = append(linkedTags[articleTag.ArticleID], tagIDs[articleTag.TagID])
+		}
+		articleIDs = make([]uint32, 0, len(data.Articles))
+		for _, article := range data.Articles {
+			articleID := articleIDMap[article.ID]
+			articleIDs = append(articleIDs, articleID)
+			if err := syncArticleTags(tx, articleID, linkedTags[article.ID], param.ModifiedBy); err != nil {
+				return err
+			}
+		}
+		report.ArticleTags = len(data.ArticleTags)
+
+		if param.DryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err == errImportDryRun {
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data.Tags) > 0 {
+		svc.bumpTagVersion()
+	}
+	for _, id := range articleIDs {
+		svc.invalidateArticleCache(id)
+		svc.indexArticle(id)
+	}
+	return report, nil
+}
+
+// 检查导入的数据, 与创建标签和文章时的参数校验保持一致
+func validateTransferData(data *TransferData) error {
+	tagIDs := map[uint32]bool{}
+	tagNames := map[string]bool{}
+	for _, tag := range data.Tags {
+		switch {
+		case tag.ID == 0 || tagIDs[tag.ID]:
+			return fmt.Errorf("%w: tag %q: id %d is empty or duplicated", ErrInvalidTransferData, tag.Name, tag.ID)
+		case len(tag.Name) == 0 || len(tag.Name) > 100:
+			return fmt.Errorf("%w: tag %d: name must be 1-100 characters", ErrInvalidTransferData, tag.ID)
+		case tagNames[tag.Name]:
+			return fmt.Errorf("%w: tag %d: name %q is duplicated", ErrInvalidTransferData, tag.ID, tag.Name)
+		case tag.State != model.STATE_OPEN && tag.State != model.STATE_CLOSE:
+			return fmt.Errorf("%w: tag %d: invalid state %d", ErrInvalidTransferData, tag.ID, tag.State)
+		}
+		tagIDs[tag.ID] = true
+		tagNames[tag.Name] = true
+	}
+
+	articleIDs := map[uint32]bool{}
+	articleTitles := map[string]bool{}
+	for _, article := range data.Articles {
+		switch {
+		case article.ID == 0 || articleIDs[article.ID]:
+			return fmt.Errorf("%w: article %q: id %d is empty or duplicated", ErrInvalidTransferData, article.Title, article.ID)
+		case len(article.Title) < 2 || len(article.Title) > 100:
+			return fmt.Errorf("%w: article %d: title must be 2-100 characters", ErrInvalidTransferData, article.ID)
+		case articleTitles[article.Title]:
+			return fmt.Errorf("%w: article %d: title %q is duplicated", ErrInvalidTransferData, article.ID, article.Title)
+		case article.Content == "":
+			return fmt.Errorf("%w: article %d: content is empty", ErrInvalidTransferData, article.ID)
+		case article.State > model.ARTICLE_STATE_ARCHIVED:
+			return fmt.Errorf("%w: article %d: invalid state %d", ErrInvalidTransferData, article.ID, article.State)
+		}
+		articleIDs[article.ID] = true
+		articleTitles[article.Title] = true
+	}
+
+	for _, articleTag := range data.ArticleTags {
+		if !articleIDs[articleTag.ArticleID] || !tagIDs[articleTag.TagID] {
+			return fmt.Errorf("%w: article tag (%d, %d) refers to a missing article or tag", ErrInvalidTransferData, articleTag.ArticleID, articleTag.TagID)
+		}
+	}
+	return nil
+}
+
+// 导入标签, 返回导入数据中的标签ID到本地标签ID的映射
+func importTags(tx *dao.Dao, tags []*TransferTag, modifiedBy string, report *ImportReport) (map[uint32]uint32, error) {
+	tagIDs := make(map[uint32]uint32, len(tags))
+	for _, tag := range tags {
+		existing, err := tx.GetTagByName(tag.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.Model == nil || existing.ID == 0 {
+			created, err := tx.CreateTag(tag.Name, tag.State, operatorOr(tag.CreatedBy, modifiedBy))
+			if err != nil {
+				return nil, err
+			}
+			tagIDs[tag.ID] = created.ID
+			report.Tags.Created++
+			continue
+		}
+
+		tagIDs[tag.ID] = existing.ID
+		if existing.State == tag.State {
+			report.Tags.Unchanged++
+			continue
+		}
+
+		report.Conflicts = append(report.Conflicts, &ImportConflict{
+			Type:       "tag",
+			ID:         tag.ID,
+			Key:        tag.Name,
+			ExistingID: existing.ID,
+			Fields:     []string{"state"},
+		})
+		if err := tx.UpdateTag(existing.ID, "", tag.State, modifiedBy); err != nil {
+			return nil, err
+		}
+		report.Tags.Updated++
+	}
+	return tagIDs, nil
+}
+
+// 导入文章, 返回导入数据中的文章ID到本地文章ID的映射, 新建和修改都会记录历史版本
+func importArticles(tx *dao.Dao, articles []*TransferArticle, modifiedBy string, report *ImportReport) (map[uint32]uint32, error) {
+	articleIDs := make(map[uint32]uint32, len(articles))
+	for _, article := range articles {
+		existing, err := tx.GetArticleByTitle(article.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.Model == nil || existing.ID == 0 {
+			id, err := importNewArticle(tx, article, operatorOr(article.CreatedBy, modifiedBy))
+			if err != nil {
+				return nil, err
+			}
+			articleIDs[article.ID] = id
+			report.Articles.Created++
+			continue
+		}
+
+		articleIDs[article.ID] = existing.ID
+		fields := articleConflictFields(&existing, article)
+		if len(fields) == 0 {
+			report.Articles.Unchanged++
+			continue
+		}
+
+		report.Conflicts = append(report.Conflicts, &ImportConflict{
+			Type:       "article",
+			ID:         article.ID,
+			Key:        article.Title,
+			ExistingID: existing.ID,
+			Fields:     fields,
+		})
+		err = updateArticleWithRevision(tx, &dao.Article{
+			ID:            existing.ID,
+			Desc:          article.Desc,
+			Content:       article.Content,
+			CoverImageUrl: article.CoverImageUrl,
+			PublishAt:     article.PublishAt,
+			ModifiedBy:    modifiedBy,
+		}, &article.State)
+		if err != nil {
+			return nil, err
+		}
+		report.Articles.Updated++
+	}
+	return articleIDs, nil
+}
+
+func importNewArticle(tx *dao.Dao, article *TransferArticle, createdBy string) (uint32, error) {
+	publishAt := article.PublishAt
+	if article.State == model.ARTICLE_STATE_PUBLISHED && publishAt == 0 {
+		publishAt = uint32(time.Now().Unix())
+	}
+
+	contentHTML, err := markdown.Render(article.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := tx.CreateArticle(&dao.Article{
+		Title:         article.Title,
+		Desc:          article.Desc,
+		Content:       article.Content,
+		ContentHTML:   contentHTML,
+		CoverImageUrl: article.CoverImageUrl,
+		State:         article.State,
+		PublishAt:     publishAt,
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.CreateArticleRevision(created, 1, createdBy); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// 导入的文章中与已有文章不同的字段, 空值不会覆盖已有的内容因此不算冲突
+func articleConflictFields(existing *model.Article, article *TransferArticle) []string {
+	var fields []string
+	if article.Desc != "" && article.Desc != existing.Desc {
+		fields = append(fields, "desc")
+	}
+	if article.Content != existing.Content {
+		fields = append(fields, "content")
+	}
+	if article.CoverImageUrl != "" && article.CoverImageUrl != existing.CoverImageUrl {
+		fields = append(fields, "cover_image_url")
+	}
+	if article.State != existing.State {
+		fields = append(fields, "state")
+	}
+	if article.PublishAt != 0 && article.PublishAt != existing.PublishAt {
+		fields = append(fields, "publish_at")
+	}
+	return fields
+}
+
+func operatorOr(operator, fallback string) string {
+	if operator != "" {
+		return operator
+	}
+	return fallback
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	TRANSFER_FORMAT_JSON = "json"
+	TRANSFER_FORMAT_CSV  = "csv"
+)
+
+// 导入导出的数据中ID为导出环境中的ID, 只用于关联标签和文章, 导入时按标签名称和文章标题匹配已有的数据
+type TransferTag struct {
+	ID        uint32 `json:"id"`
+	Name      string `json:"name"`
+	State     uint8  `json:"state"`
+	CreatedBy string `json:"created_by"`
+}
+
+type TransferArticle struct {
+	ID            uint32 `json:"id"`
+	Title         string `json:"title"`
+	Desc          string `json:"desc"`
+	Content       string `json:"content"`
+	CoverImageUrl string `json:"cover_image_url"`
+	State         uint8  `json:"state"`
+	PublishAt     uint32 `json:"publish_at"`
+	CreatedBy     string `json:"created_by"`
+}
+
+type TransferArticleTag struct {
+	ArticleID uint32 `json:"article_id"`
+	TagID     uint32 `json:"tag_id"`
+}
+
+type TransferData struct {
+	Tags        []*TransferTag        `json:"tags"`
+	Articles    []*TransferArticle    `json:"articles"`
+	ArticleTags []*TransferArticleTag `json:"article_tags"`
+}
+
+var ErrInvalidTransferData = errors.New("invalid transfer data")
+
+// 逐条写出导出的数据, 需要按标签、文章、文章标签关联的顺序写入
+type transferEncoder interface {
+	Tag(tag *TransferTag) error
+	Article(article *TransferArticle) error
+	ArticleTag(articleTag *TransferArticleTag) error
+	// 每批数据写完后调用, 将缓冲的数据发送给客户端
+	Flush() error
+	Close() error
+}
+
+func newTransferEncoder(format string, w io.Writer) transferEncoder {
+	if format == TRANSFER_FORMAT_CSV {
+		return &csvTransferEncoder{w: csv.NewWriter(w)}
+	}
+	return &jsonTransferEncoder{w: w}
+}
+
+func decodeTransferData(format string, r io.Reader) (*TransferData, error) {
+	if format == TRANSFER_FORMAT_CSV {
+		return decodeCSVTransferData(r)
+	}
+
+	data := &TransferData{}
+	if err := json.NewDecoder(r).Decode(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransferData, err)
+	}
+	return data, nil
+}
+
+// JSON格式为 {"tags":[...],"articles":[...],"article_tags":[...]}, 边查询边写出, 不在内存中保存全部数据
+type jsonTransferEncoder struct {
+	w io.Writer
+	// 已经打开的部分数量, 最后一个打开的部分尚未关闭
+	opened int
+	count  int
+}
+
+var jsonTransferSections = []string{"tags", "articles", "article_tags"}
+
+func (e *jsonTransferEncoder) Tag(tag *TransferTag) error {
+	return e.write(0, tag)
+}
+
+func (e *jsonTransferEncoder) Article(article *TransferArticle) error {
+	return e.write(1, article)
+}
+
+func (e *jsonTransferEncoder) ArticleTag(articleTag *TransferArticleTag) error {
+	return e.write(2, articleTag)
+}
+
+func (e *jsonTransferEncoder) write(section int, v interface{}) error {
+	if err := e.openSection(section); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		b = append([]byte(","), b...)
+	}
+	e.count++
+	_, err = e.w.Write(b)
+	return err
+}
+
+// 关闭当前的部分并依次打开到指定的部分, 中间跳过的部分写为空数组
+func (e *jsonTransferEncoder) openSection(section int) error {
+	if section < e.opened-1 {
+		return fmt.Errorf("%s must be written before %s", jsonTransferSections[section], jsonTransferSections[e.opened-1])
+	}
+
+	for e.opened <= section {
+		prefix := "{"
+		if e.opened > 0 {
+			prefix = "],"
+		}
+		if _, err := fmt.Fprintf(e.w, "%s%q:[", prefix, jsonTransferSections[e.opened]); err != nil {
+			return err
+		}
+		e.opened++
+		e.count = 0
+	}
+	return nil
+}
+
+func (e *jsonTransferEncoder) Flush() error {
+	return nil
+}
+
+func (e *jsonTransferEncoder) Close() error {
+	if err := e.openSection(len(jsonTransferSections) - 1); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "]}")
+	return err
+}
+
+// CSV格式中每行的第一列为记录类型, 其余列按类型填写, 不相关的列为空
+type csvTransferEncoder struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+const (
+	csvRecordTag        = "tag"
+	csvRecordArticle    = "article"
+	csvRecordArticleTag = "article_tag"
+)
+
+var csvTransferHeader = []string{
+	"type", "id", "name", "title", "desc", "content", "cover_image_url", "state", "publish_at", "created_by", "article_id", "tag_id",
+}
+
+func (e *csvTransferEncoder) Tag(tag *TransferTag) error {
+	return e.write(map[string]string{
+		"type":       csvRecordTag,
+		"id":         formatUint(tag.ID),
+		"name":       tag.Name,
+		"state":      formatUint(uint32(tag.State)),
+		"created_by": tag.CreatedBy,
+	})
+}
+
+func (e *csvTransferEncoder) Article(article *TransferArticle) error {
+	return e.write(map[string]string{
+		"type":            csvRecordArticle,
+		"id":              formatUint(article.ID),
+		"title":           article.Title,
+		"desc":            article.Desc,
+		"content":         article.Content,
+		"cover_image_url": article.CoverImageUrl,
+		"state":           formatUint(uint32(article.State)),
+		"publish_at":      formatUint(article.PublishAt),
+		"created_by":      article.CreatedBy,
+	})
+}
+
+func (e *csvTransferEncoder) ArticleTag(articleTag *TransferArticleTag) error {
+	return e.write(map[string]string{
+		"type":       csvRecordArticleTag,
+		"article_id": formatUint(articleTag.ArticleID),
+		"tag_id":     formatUint(articleTag.TagID),
+	})
+}
+
+func (e *csvTransferEncoder) write(fields map[string]string) error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvTransferHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	record := make([]string, len(csvTransferHeader))
+	for i, column := range csvTransferHeader {
+		record[i] = fields[column]
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvTransferEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvTransferEncoder) Close() error {
+	if !e.headerWritten {
+		if err := e.w.Write(csvTransferHeader); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+	return e.Flush()
+}
+
+// 按表头的列名读取, 列的顺序可以与导出时不同
+func decodeCSVTransferData(r io.Reader) (*TransferData, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &TransferData{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransferData, err)
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[column] = i
+	}
+	if _, ok := columns["type"]; !ok {
+		return nil, fmt.Errorf("%w: missing column type", ErrInvalidTransferData)
+	}
+
+	data := &TransferData{}
+	// 内容中可能包含换行, 按记录计数而不是行号
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return data, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransferData, err)
+		}
+
+		row := csvRow{record: record, columns: columns}
+		switch recordType := row.get("type"); recordType {
+		case csvRecordTag:
+			data.Tags = append(data.Tags, &TransferTag{
+				ID:        row.uint32("id"),
+				Name:      row.get("name"),
+				State:     row.uint8("state"),
+				CreatedBy: row.get("created_by"),
+			})
+		case csvRecordArticle:
+			data.Articles = append(data.Articles, &TransferArticle{
+				ID:            row.uint32("id"),
+				Title:         row.get("title"),
+				Desc:          row.get("desc"),
+				Content:       row.get("content"),
+				CoverImageUrl: row.get("cover_image_url"),
+				State:         row.uint8("state"),
+				PublishAt:     row.uint32("publish_at"),
+				CreatedBy:     row.get("created_by"),
+			})
+		case csvRecordArticleTag:
+			data.ArticleTags = append(data.ArticleTags, &TransferArticleTag{
+				ArticleID: row.uint32("article_id"),
+				TagID:     row.uint32("tag_id"),
+			})
+		default:
+			return nil, fmt.Errorf("%w: record %d: unknown record type %q", ErrInvalidTransferData, n, recordType)
+		}
+		if row.err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidTransferData, n, row.err)
+		}
+	}
+}
+
+type csvRow struct {
+	record  []string
+	columns map[string]int
+	err     error
+}
+
+func (r *csvRow) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return r.record[i]
+}
+
+func (r *csvRow) uint32(column string) uint32 {
+	return uint32(r.parseUint(column, 32))
+}
+
+func (r *csvRow) uint8(column string) uint8 {
+	return uint8(r.parseUint(column, 8))
+}
+
+// 空值视为0, 解析失败时记录第一个错误
+func (r *csvRow) parseUint(column string, bitSize int) uint64 {
+	value := r.get(column)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("invalid %s %q", column, value)
+	}
+	return n
+}
+
+func formatUint(n uint32) string {
+	return strconv.FormatUint(uint64(n), 10)
+}
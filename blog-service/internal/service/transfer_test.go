@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"blog-service/internal/model"
+)
+
+// 写入两个标签和两篇文章, 第一篇文章关联两个标签, 第二篇文章关联一个标签
+func seedTransferData(t *testing.T, svc Service) {
+	t.Helper()
+
+	var tagIDs []uint32
+	for _, name := range []string{"Go", "Rust"} {
+		tag, err := svc.dao.CreateTag(name, model.STATE_OPEN, "tester")
+		if err != nil {
+			t.Fatalf("CreateTag(%s) err: %v", name, err)
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := svc.dao.UpdateTag(tagIDs[1], "", model.STATE_CLOSE, "tester"); err != nil {
+		t.Fatalf("UpdateTag err: %v", err)
+	}
+
+	first := createTestArticle(t, svc, "first, with \"quotes\"", model.ARTICLE_STATE_PUBLISHED, 1000)
+	second := createTestArticle(t, svc, "second", model.ARTICLE_STATE_DRAFT, 0)
+	links := [][2]uint32{{first.ID, tagIDs[0]}, {first.ID, tagIDs[1]}, {second.ID, tagIDs[0]}}
+	for _, link := range links {
+		if err := svc.dao.CreateArticleTag(link[0], link[1], "tester"); err != nil {
+			t.Fatalf("CreateArticleTag(%d, %d) err: %v", link[0], link[1], err)
+		}
+	}
+}
+
+func exportTransferData(t *testing.T, svc Service, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := svc.Export(format, &buf); err != nil {
+		t.Fatalf("Export(%s) err: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func decodeTestTransferData(t *testing.T, format string, b []byte) *TransferData {
+	t.Helper()
+
+	data, err := decodeTransferData(format, bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("decodeTransferData(%s) err: %v", format, err)
+	}
+	return data
+}
+
+func encodeTestTransferData(t *testing.T, data *TransferData) *bytes.Reader {
+	t.Helper()
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("json.Marshal err: %v", err)
+	}
+	return bytes.NewReader(b)
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newTestService(t)
+	seedTransferData(t, src)
+
+	for _, format := range []string{TRANSFER_FORMAT_JSON, TRANSFER_FORMAT_CSV} {
+		t.Run(format, func(t *testing.T) {
+			exported := exportTransferData(t, src, format)
+
+			dst := newTestService(t)
+			report, err := dst.Import(&ImportRequest{Format: format, ModifiedBy: "importer"}, bytes.NewReader(exported))
+			if err != nil {
+				t.Fatalf("Import err: %v", err)
+			}
+			if report.Tags.Created != 2 || report.Articles.Created != 2 || report.ArticleTags != 3 || len(report.Conflicts) != 0 {
+				t.Errorf("Import report = %+v, want 2 tags, 2 articles and 3 links created without conflicts", report)
+			}
+
+			// 空库中导入后的ID与导出环境一致, 再次导出的内容应当完全相同
+			want := decodeTestTransferData(t, format, exported)
+			got := decodeTestTransferData(t, format, exportTransferData(t, dst, format))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("re-exported data differs:\ngot  %+v\nwant %+v", got, want)
+			}
+
+			// 再次导入相同的数据没有变更
+			report, err = dst.Import(&ImportRequest{Format: format, ModifiedBy: "importer"}, bytes.NewReader(exported))
+			if err != nil {
+				t.Fatalf("second Import err: %v", err)
+			}
+			if report.Tags.Unchanged != 2 || report.Articles.Unchanged != 2 || len(report.Conflicts) != 0 {
+				t.Errorf("second Import report = %+v, want everything unchanged", report)
+			}
+		})
+	}
+}
+
+// 标签名称和文章标题与已有数据相同时更新已有数据, 并记录冲突的字段
+func TestImportConflicts(t *testing.T) {
+	svc := newTestService(t)
+	tag, err := svc.dao.CreateTag("Go", model.STATE_OPEN, "tester")
+	if err != nil {
+		t.Fatalf("CreateTag err: %v", err)
+	}
+	article := createTestArticle(t, svc, "Hello", model.ARTICLE_STATE_PUBLISHED, 1000)
+	if err := svc.dao.CreateArticleTag(article.ID, tag.ID, "tester"); err != nil {
+		t.Fatalf("CreateArticleTag err: %v", err)
+	}
+
+	data := &TransferData{
+		Tags: []*TransferTag{
+			{ID: 10, Name: "Go", State: model.STATE_CLOSE},
+			{ID: 11, Name: "Rust", State: model.STATE_OPEN},
+		},
+		Articles: []*TransferArticle{
+			{ID: 20, Title: "Hello", Content: "# Hello again", State: model.ARTICLE_STATE_PUBLISHED},
+			{ID: 21, Title: "World", Desc: "world desc", Content: "# World", State: model.ARTICLE_STATE_DRAFT},
+		},
+		ArticleTags: []*TransferArticleTag{{ArticleID: 20, TagID: 11}, {ArticleID: 21, TagID: 10}},
+	}
+	wantConflicts := []*ImportConflict{
+		{Type: "tag", ID: 10, Key: "Go", ExistingID: tag.ID, Fields: []string{"state"}},
+		{Type: "article", ID: 20, Key: "Hello", ExistingID: article.ID, Fields: []string{"content"}},
+	}
+
+	// 试运行报告将要执行的变更, 不修改数据库
+	dryRun, err := svc.Import(&ImportRequest{Format: TRANSFER_FORMAT_JSON, DryRun: true, ModifiedBy: "importer"}, encodeTestTransferData(t, data))
+	if err != nil {
+		t.Fatalf("dry run Import err: %v", err)
+	}
+	if !dryRun.DryRun || !reflect.DeepEqual(dryRun.Conflicts, wantConflicts) {
+		t.Errorf("dry run conflicts = %+v, want %+v", dryRun.Conflicts, wantConflicts)
+	}
+	if got := getTestArticle(t, svc, article.ID); got.Content != article.Content {
+		t.Errorf("article content after dry run = %q, want %q", got.Content, article.Content)
+	}
+	if count, err := svc.dao.CountTag("", model.STATE_OPEN); err != nil || count != 1 {
+		t.Errorf("open tags after dry run = %d, %v, want 1", count, err)
+	}
+	if world, err := svc.dao.GetArticleByTitle("World"); err != nil || (world.Model != nil && world.ID != 0) {
+		t.Errorf("GetArticleByTitle(World) after dry run = %+v, %v, want not found", world.Model, err)
+	}
+	if revisions, err := svc.dao.CountArticleRevision(article.ID); err != nil || revisions != 0 {
+		t.Errorf("revisions after dry run = %d, %v, want 0", revisions, err)
+	}
+
+	// 实际导入的结果与试运行相同
+	report, err := svc.Import(&ImportRequest{Format: TRANSFER_FORMAT_JSON, ModifiedBy: "importer"}, encodeTestTransferData(t, data))
+	if err != nil {
+		t.Fatalf("Import err: %v", err)
+	}
+	dryRun.DryRun = false
+	if !reflect.DeepEqual(report, dryRun) {
+		t.Errorf("Import report = %+v, want the dry run report %+v", report, dryRun)
+	}
+	if report.Tags != (ImportStat{Created: 1, Updated: 1}) || report.Articles != (ImportStat{Created: 1, Updated: 1}) {
+		t.Errorf("Import stats = %+v, %+v, want one created and one updated each", report.Tags, report.Articles)
+	}
+
+	got := getTestArticle(t, svc, article.ID)
+	if got.Content != "# Hello again" || got.Desc != article.Desc {
+		t.Errorf("updated article = %q, %q, want new content and the existing desc", got.Content, got.Desc)
+	}
+	links, err := svc.dao.GetArticleTagListByAID(article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleTagListByAID err: %v", err)
+	}
+	rust, err := svc.dao.GetTagByName("Rust")
+	if err != nil {
+		t.Fatalf("GetTagByName err: %v", err)
+	}
+	if len(links) != 1 || links[0].TagID != rust.ID {
+		t.Errorf("article tags = %+v, want only the imported Rust tag", links)
+	}
+	if tag, err := svc.dao.GetTagByName("Go"); err != nil || tag.State != model.STATE_CLOSE {
+		t.Errorf("tag Go state = %d, %v, want %d", tag.State, err, model.STATE_CLOSE)
+	}
+}
+
+// 引用了不存在的记录或重复的数据在写入前被拒绝
+func TestImportRejectsInvalidData(t *testing.T) {
+	svc := newTestService(t)
+
+	tests := []struct {
+		name string
+		data *TransferData
+	}{
+		{"missing tag", &TransferData{
+			Tags:        []*TransferTag{{ID: 1, Name: "Go", State: model.STATE_OPEN}},
+			Articles:    []*TransferArticle{{ID: 1, Title: "Hello", Content: "# Hello"}},
+			ArticleTags: []*TransferArticleTag{{ArticleID: 1, TagID: 2}},
+		}},
+		{"missing article", &TransferData{
+			Tags:        []*TransferTag{{ID: 1, Name: "Go", State: model.STATE_OPEN}},
+			Articles:    []*TransferArticle{{ID: 1, Title: "Hello", Content: "# Hello"}},
+			ArticleTags: []*TransferArticleTag{{ArticleID: 2, TagID: 1}},
+		}},
+		{"duplicated tag name", &TransferData{
+			Tags: []*TransferTag{{ID: 1, Name: "Go", State: model.STATE_OPEN}, {ID: 2, Name: "Go", State: model.STATE_OPEN}},
+		}},
+		{"invalid article state", &TransferData{
+			Tags:     []*TransferTag{{ID: 1, Name: "Go", State: model.STATE_OPEN}},
+			Articles: []*TransferArticle{{ID: 1, Title: "Hello", Content: "# Hello", State: 9}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Import(&ImportRequest{Format: TRANSFER_FORMAT_JSON, ModifiedBy: "importer"}, encodeTestTransferData(t, tt.data))
+			if !errors.Is(err, ErrInvalidTransferData) {
+				t.Fatalf("Import err = %v, want ErrInvalidTransferData", err)
+			}
+
+			tag, err := svc.dao.GetTagByName("Go")
+			if err != nil {
+				t.Fatalf("GetTagByName err: %v", err)
+			}
+			if tag.Model != nil && tag.ID != 0 {
+				t.Errorf("tag Go created before the data was rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/upload"
+	"errors"
+	"mime/multipart"
+	"os"
+)
+
+type FileInfo struct {
+	Name      string `json:"name"`
+	AccessUrl string `json:"access_url"`
+}
+
+func (svc *Service) UploadFile(fileType upload.FileType, fileHeader *multipart.FileHeader) (*FileInfo, error) {
+	fileName, err := upload.GetFileName(fileHeader.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if !upload.CheckContainExt(fileType, fileName) {
+		return nil, errors.New("file suffix is not supported")
+	}
+	if upload.CheckMaxSize(fileType, fileHeader.Size) {
+		return nil, errors.New("exceeded maximum file limit")
+	}
+
+	uploadSavePath := upload.GetSavePath()
+	if upload.CheckSavePath(uploadSavePath) {
+		if err := upload.CreateSavePath(uploadSavePath, os.ModePerm); err != nil {
+			return nil, errors.New("failed to create save directory")
+		}
+	}
+	if upload.CheckPermission(uploadSavePath) {
+		return nil, errors.New("insufficient file permissions")
+	}
+
+	dst := uploadSavePath + "/" + fileName
+	if err := upload.SaveFile(fileHeader, dst); err != nil {
+		global.Logger.WithTrace(svc.ctx).Errorf("upload.SaveFile err: %v", err)
+		return nil, err
+	}
+
+	accessUrl := upload.GetServerUrl() + "/" + fileName
+	return &FileInfo{Name: fileName, AccessUrl: accessUrl}, nil
+}
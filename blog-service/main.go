@@ -2,20 +2,50 @@ package main
 
 import (
 	"blog-service/global"
+	"blog-service/internal/migration"
 	"blog-service/internal/model"
 	"blog-service/internal/routers"
+	"blog-service/internal/scheduler"
+	"blog-service/internal/service"
+	"blog-service/pkg/cache"
 	"blog-service/pkg/logger"
+	"blog-service/pkg/search"
 	"blog-service/pkg/setting"
+	"blog-service/pkg/tracer"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+var (
+	port    string
+	runMode string
+	config  string
+
+	publishScheduler *scheduler.PublishScheduler
+)
+
 func init() {
-	err := setupSetting()
+	err := setupFlag()
+	if err != nil {
+		log.Fatalf("init.setupFlag err: %v", err)
+	}
+
+	err = setupSetting()
 	if err != nil {
 		log.Fatalf("init.setupSetting err: %v", err)
 	}
@@ -30,6 +60,30 @@ func init() {
 		log.Fatalf("init.setupDBEngine err: %v", err)
 	}
 
+	// 执行数据库迁移时只需要配置、日志和数据库连接
+	if flag.Arg(0) == "migrate" {
+		return
+	}
+
+	err = checkMigrations()
+	if err != nil {
+		log.Fatalf("init.checkMigrations err: %v", err)
+	}
+
+	err = setupCache()
+	if err != nil {
+		log.Fatalf("init.setupCache err: %v", err)
+	}
+
+	err = setupTracer()
+	if err != nil {
+		log.Fatalf("init.setupTracer err: %v", err)
+	}
+
+	err = setupSearchIndexer()
+	if err != nil {
+		log.Fatalf("init.setupSearchIndexer err: %v", err)
+	}
 }
 
 // @title 博客系统
@@ -37,6 +91,13 @@ func init() {
 // @description Go 语言编程之旅：一起用 Go 做项目
 // @termsOfService https://github.com/go-programming-tour-book
 func main() {
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(flag.Args()[1:]); err != nil {
+			log.Fatalf("migrate err: %v", err)
+		}
+		return
+	}
+
 	gin.SetMode(global.ServerSetting.RunMode)
 	router := routers.NewRouter()
 
@@ -50,11 +111,129 @@ func main() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	s.ListenAndServe()
+	go func() {
+		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("s.ListenAndServe err: %v", err)
+		}
+	}()
+	atomic.StoreInt32(&global.ServerReady, 1)
+
+	publishScheduler = scheduler.NewPublishScheduler()
+	publishScheduler.Start()
+
+	// 等待中断信号
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	global.Logger.Infof("received signal %s, shutting down server...", sig)
+
+	setting.RLock()
+	serverSetting := global.ServerSetting
+	setting.RUnlock()
+
+	// 先让/healthz返回未就绪, 等待负载均衡摘除流量后再停止接收请求
+	atomic.StoreInt32(&global.ServerReady, 0)
+	time.Sleep(serverSetting.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverSetting.ShutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		global.Logger.Errorf("server forced to shutdown: %v", err)
+	}
+
+	shutdown()
+	log.Println("server exiting")
+}
+
+// 按顺序释放资源: 定时任务、链路数据、全文检索索引、缓存、数据库连接池, 最后关闭日志
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if publishScheduler != nil {
+		publishScheduler.Stop()
+	}
+
+	if global.TracerProvider != nil {
+		if err := global.TracerProvider.Shutdown(ctx); err != nil {
+			global.Logger.Errorf("TracerProvider.Shutdown err: %v", err)
+		}
+	}
+
+	if global.SearchIndexer != nil {
+		if err := global.SearchIndexer.Close(); err != nil {
+			global.Logger.Errorf("SearchIndexer.Close err: %v", err)
+		}
+	}
+
+	if global.Cache != nil {
+		if err := global.Cache.Close(); err != nil {
+			global.Logger.Errorf("Cache.Close err: %v", err)
+		}
+	}
+
+	if global.DBEngine != nil {
+		if sqlDB, err := global.DBEngine.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				global.Logger.Errorf("DBEngine.Close err: %v", err)
+			}
+		}
+	}
+
+	if err := global.Logger.Close(); err != nil {
+		log.Printf("Logger.Close err: %v", err)
+	}
+}
+
+// 执行数据库迁移: migrate up 执行全部未执行的迁移, migrate down [n] 回滚最近的n个迁移, 默认为1
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down [n]")
+	}
+
+	var (
+		done []*migration.Migration
+		err  error
+	)
+	switch args[0] {
+	case "up":
+		done, err = migration.Up(global.DBEngine)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid migrate down steps: %s", args[1])
+			}
+		}
+		done, err = migration.Down(global.DBEngine, steps)
+	default:
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+
+	for _, m := range done {
+		log.Printf("migrate %s: %d_%s", args[0], m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(done) == 0 {
+		log.Printf("migrate %s: nothing to do", args[0])
+	}
+	return nil
+}
+
+func setupFlag() error {
+	flag.StringVar(&port, "port", "", "启动端口")
+	flag.StringVar(&runMode, "mode", "", "启动模式")
+	flag.StringVar(&config, "config", "configs/", "指定要使用的配置文件路径, 多个路径以逗号分隔")
+	flag.Parse()
+
+	return nil
 }
 
 func setupSetting() error {
-	setting, err := setting.NewSetting()
+	setting, err := setting.NewSetting(strings.Split(config, ",")...)
 	if err != nil {
 		return err
 	}
@@ -74,10 +253,95 @@ func setupSetting() error {
 		return err
 	}
 
+	err = setting.ReadSection("JWT", &global.JWTSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Limiter", &global.LimiterSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Email", &global.EmailSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Tracer", &global.TracerSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Search", &global.SearchSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Scheduler", &global.SchedulerSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Site", &global.SiteSetting)
+	if err != nil {
+		return err
+	}
+
+	err = setting.ReadSection("Cache", &global.CacheSetting)
+	if err != nil {
+		return err
+	}
+
+	applySetting()
+	setting.OnChange(func() {
+		applySetting()
+		reloadSetting()
+	})
+	setting.OnError(func(err error) {
+		if global.Logger == nil {
+			log.Printf("setting.ReloadAllSection err: %v, keep the previous settings", err)
+			return
+		}
+		global.Logger.Errorf("setting.ReloadAllSection err: %v, keep the previous settings", err)
+	})
+
+	return nil
+}
+
+// 换算配置中的时间单位, 并使用命令行参数覆盖配置文件中的值
+func applySetting() {
 	global.ServerSetting.ReadTimeout *= time.Second
 	global.ServerSetting.WriteTimeout *= time.Second
+	global.ServerSetting.ShutdownDelay *= time.Second
+	global.ServerSetting.ShutdownTimeout *= time.Second
+	global.JWTSetting.Expire *= time.Second
+	global.AppSetting.DefaultContextTimeout *= time.Second
+	global.SchedulerSetting.PublishInterval *= time.Second
+	global.CacheSetting.ArticleTTL *= time.Second
+	global.CacheSetting.TagListTTL *= time.Second
+	for i := range global.LimiterSetting.Rules {
+		global.LimiterSetting.Rules[i].FillInterval *= time.Second
+	}
 
-	return nil
+	if port != "" {
+		global.ServerSetting.HttpPort = port
+	}
+	if runMode != "" {
+		global.ServerSetting.RunMode = runMode
+	}
+}
+
+// 配置文件变更后使可以在运行时调整的配置生效, 端口、限流规则等仍需重启
+func reloadSetting() {
+	gin.SetMode(global.ServerSetting.RunMode)
+
+	if global.DBEngine != nil {
+		if sqlDB, err := global.DBEngine.DB(); err == nil {
+			sqlDB.SetMaxIdleConns(global.DatabaseSetting.MaxIdleConns)
+			sqlDB.SetMaxOpenConns(global.DatabaseSetting.MaxOpenConns)
+		}
+	}
 }
 
 func setupLogger() error {
@@ -103,3 +367,74 @@ func setupDBEngine() error {
 	}
 	return nil
 }
+
+func setupCache() error {
+	switch global.CacheSetting.Driver {
+	case "":
+		return nil
+	case "memory":
+		global.Cache = cache.NewMemoryCache(global.CacheSetting.Capacity)
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     global.CacheSetting.RedisAddr,
+			Password: global.CacheSetting.RedisPassword,
+			DB:       global.CacheSetting.RedisDB,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return err
+		}
+		global.Cache = cache.NewRedisCache(client, global.CacheSetting.KeyPrefix)
+	default:
+		return fmt.Errorf("unsupported cache driver: %s", global.CacheSetting.Driver)
+	}
+
+	return nil
+}
+
+func setupTracer() error {
+	tp, err := tracer.NewTracerProvider(
+		global.TracerSetting.ServiceName,
+		global.TracerSetting.Exporter,
+		global.TracerSetting.Endpoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	global.TracerProvider = tp
+	global.Tracer = tp.Tracer(global.TracerSetting.ServiceName)
+	return nil
+}
+
+// 数据库结构落后于代码时拒绝启动, 提示先执行迁移
+func checkMigrations() error {
+	pending, err := migration.Pending(global.DBEngine)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database has %d pending migrations starting at %d_%s, run `go run main.go migrate up` first",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+func setupSearchIndexer() error {
+	indexer, err := search.NewBleveIndexer(global.SearchSetting.IndexPath)
+	if err != nil {
+		return err
+	}
+	global.SearchIndexer = indexer
+
+	// 已有的索引可能错过了停机期间或索引失败的修改, 每次启动都与数据库对账, 只重新索引有变化的文章
+	svc := service.New(context.Background())
+	indexed, removed, err := svc.SyncSearchIndex()
+	if err != nil {
+		return err
+	}
+	global.Logger.Infof("search index synced with %d articles reindexed, %d stale documents removed", indexed, removed)
+
+	return nil
+}
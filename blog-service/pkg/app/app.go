@@ -15,6 +15,10 @@ type Pager struct {
 	Page      int `json:"page"`
 	PageSize  int `json:"page_size"`
 	TotalRows int `json:"total_rows"`
+	// 游标分页时使用, Cursor为解码后的游标, NextCursor为下一页的游标, 没有下一页时为空
+	UseCursor  bool   `json:"-"`
+	Cursor     uint32 `json:"-"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func NewResponse(ctx *gin.Context) *Response {
@@ -32,20 +36,25 @@ func (r *Response) ToResponse(data interface{}) {
 }
 
 func (r *Response) ToResponseList(list interface{}, totalRows int) {
+	r.ToPagerResponseList(list, &Pager{
+		Page:      GetPage(r.Ctx),
+		PageSize:  GetPageSize(r.Ctx),
+		TotalRows: totalRows,
+	})
+}
+
+// 按传入的分页信息返回列表, 游标分页时会带上next_cursor
+func (r *Response) ToPagerResponseList(list interface{}, pager *Pager) {
 	r.Ctx.JSON(http.StatusOK, gin.H{
-		"list": list,
-		"pager": Pager{
-			Page:      GetPage(r.Ctx),
-			PageSize:  GetPageSize(r.Ctx),
-			TotalRows: totalRows,
-		},
+		"list":  list,
+		"pager": pager,
 	})
 }
 
 func (r *Response) ToErrorResponse(err *errcode.Error) {
 	response := gin.H{
 		"code": err.Code(),
-		"msg":  err.Msg(),
+		"msg":  err.MsgIn(r.Ctx.GetString("locale")),
 	}
 
 	details := err.Details()
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"blog-service/pkg/util"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ToConditionalResponse 输出带有ETag和Last-Modified的内容,
+// 客户端缓存的内容未发生变化时只返回304
+func (r *Response) ToConditionalResponse(contentType string, body []byte, lastModified time.Time) {
+	etag := `"` + util.EncodeMD5(string(body)) + `"`
+	header := r.Ctx.Writer.Header()
+	header.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r.Ctx.Request, etag, lastModified) {
+		r.Ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	r.Ctx.Data(http.StatusOK, contentType, body)
+}
+
+// 优先使用If-None-Match, 没有时才比较If-Modified-Since
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == etag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blog-service/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestToConditionalResponse(t *testing.T) {
+	body := []byte("<rss></rss>")
+	etag := `"` + util.EncodeMD5(string(body)) + `"`
+	lastModified := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{"no validators", nil, http.StatusOK},
+		{"etag matches", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"wildcard etag", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"etag differs", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": lastModified.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		// 同时存在时以If-None-Match为准
+		{"etag wins over date", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": lastModified.Format(http.TimeFormat),
+		}, http.StatusOK},
+	}
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/feed/rss", func(c *gin.Context) {
+		NewResponse(c).ToConditionalResponse("application/rss+xml", body, lastModified)
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/feed/rss", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if got := w.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 10:00:00 GMT" {
+				t.Errorf("Last-Modified = %q", got)
+			}
+			wantBody := string(body)
+			if tt.wantStatus == http.StatusNotModified {
+				wantBody = ""
+			}
+			if w.Body.String() != wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), wantBody)
+			}
+		})
+	}
+}
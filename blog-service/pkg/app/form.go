@@ -34,27 +34,40 @@ func (v ValidErrors) Errors() []string {
 }
 
 func BindAndValid(c *gin.Context, v interface{}) (bool, ValidErrors) {
-	var errs ValidErrors
 	fmt.Println("v", v)
 	err := c.ShouldBind(v)
 	fmt.Println("err:", err)
 	if err != nil {
-		v := c.Value("trans")
-		trans, _ := v.(ut.Translator)
-		verrs, ok := err.(val.ValidationErrors)
-		if !ok {
-			return false, errs
-		}
-
-		for key, value := range verrs.Translate(trans) {
-			errs = append(errs, &ValidError{
-				Key:     key,
-				Message: value,
-			})
-		}
-
-		return false, errs
+		return false, translateErrors(c, err)
 	}
 
 	return true, nil
 }
+
+// 只绑定URL中的参数, 用于请求体是上传的数据而不是参数的接口
+func BindQueryAndValid(c *gin.Context, v interface{}) (bool, ValidErrors) {
+	if err := c.ShouldBindQuery(v); err != nil {
+		return false, translateErrors(c, err)
+	}
+
+	return true, nil
+}
+
+func translateErrors(c *gin.Context, err error) ValidErrors {
+	var errs ValidErrors
+	v := c.Value("trans")
+	trans, _ := v.(ut.Translator)
+	verrs, ok := err.(val.ValidationErrors)
+	if !ok {
+		return errs
+	}
+
+	for key, value := range verrs.Translate(trans) {
+		errs = append(errs, &ValidError{
+			Key:     key,
+			Message: value,
+		})
+	}
+
+	return errs
+}
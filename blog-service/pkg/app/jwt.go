@@ -0,0 +1,93 @@
+package app
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/setting"
+	"blog-service/pkg/util"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type Claims struct {
+	AppKey string `json:"app_key"`
+	jwt.StandardClaims
+}
+
+// 获取请求中携带的Token, 优先读取query参数, 其次读取header
+func GetToken(c *gin.Context) string {
+	if s, exist := c.GetQuery("token"); exist {
+		return s
+	}
+
+	return c.GetHeader("token")
+}
+
+func GetJWTSecret() []byte {
+	setting.RLock()
+	defer setting.RUnlock()
+
+	return []byte(global.JWTSetting.Secret)
+}
+
+// 生成JWT Token, AppKey经过MD5编码后写入载荷
+// 载荷只是Base64编码, 不能写入AppSecret或其摘要
+func GenerateToken(appKey string) (string, error) {
+	setting.RLock()
+	jwtSetting := global.JWTSetting
+	setting.RUnlock()
+
+	nowTime := time.Now()
+	expireTime := nowTime.Add(jwtSetting.Expire)
+	claims := Claims{
+		AppKey: util.EncodeMD5(appKey),
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expireTime.Unix(),
+			Issuer:    jwtSetting.Issuer,
+		},
+	}
+
+	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token, err := tokenClaims.SignedString(GetJWTSecret())
+	return token, err
+}
+
+// 解析和校验JWT Token
+func ParseToken(token string) (*Claims, error) {
+	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return GetJWTSecret(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := tokenClaims.Claims.(*Claims)
+	if !ok || !tokenClaims.Valid {
+		return nil, jwt.NewValidationError("token is invalid", jwt.ValidationErrorClaimsInvalid)
+	}
+
+	return claims, nil
+}
+
+// 请求携带的Token是否由JWT.Moderators中的AppKey签发
+func IsModerator(c *gin.Context) bool {
+	claims, err := ParseToken(GetToken(c))
+	if err != nil {
+		return false
+	}
+
+	setting.RLock()
+	moderators := global.JWTSetting.Moderators
+	setting.RUnlock()
+	for _, appKey := range moderators {
+		if util.EncodeMD5(appKey) == claims.AppKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-service/global"
+	"blog-service/pkg/setting"
+	"blog-service/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func setupJWTSetting(t *testing.T, expire time.Duration) {
+	t.Helper()
+
+	jwtSetting := global.JWTSetting
+	global.JWTSetting = &setting.JWTSettingS{Secret: "test-secret", Issuer: "blog-service", Expire: expire}
+	t.Cleanup(func() { global.JWTSetting = jwtSetting })
+}
+
+func TestGenerateToken(t *testing.T) {
+	setupJWTSetting(t, time.Hour)
+
+	token, err := GenerateToken("app-key")
+	if err != nil {
+		t.Fatalf("GenerateToken err: %v", err)
+	}
+
+	// 载荷中只能有app_key和标准字段, 不能出现app_secret
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts, want 3", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode payload err: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		t.Fatalf("unmarshal payload err: %v", err)
+	}
+	for key := range fields {
+		if key != "app_key" && key != "exp" && key != "iss" {
+			t.Errorf("unexpected claim %q in payload %s", key, payload)
+		}
+	}
+
+	claims, err := ParseToken(token)
+	if err != nil {
+		t.Fatalf("ParseToken err: %v", err)
+	}
+	if claims.AppKey != util.EncodeMD5("app-key") {
+		t.Errorf("AppKey = %q, want %q", claims.AppKey, util.EncodeMD5("app-key"))
+	}
+	if claims.Issuer != "blog-service" {
+		t.Errorf("Issuer = %q, want %q", claims.Issuer, "blog-service")
+	}
+}
+
+func TestParseTokenInvalid(t *testing.T) {
+	setupJWTSetting(t, time.Hour)
+
+	valid, err := GenerateToken("app-key")
+	if err != nil {
+		t.Fatalf("GenerateToken err: %v", err)
+	}
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		AppKey:         "app-key",
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Minute).Unix()},
+	}).SignedString(GetJWTSecret())
+	if err != nil {
+		t.Fatalf("sign expired token err: %v", err)
+	}
+
+	otherSecret, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{AppKey: "app-key"}).SignedString([]byte("other-secret"))
+	if err != nil {
+		t.Fatalf("sign token err: %v", err)
+	}
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{AppKey: "app-key"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token err: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"malformed", "not-a-token"},
+		{"tampered", valid[:len(valid)-2] + "xx"},
+		{"expired", expired},
+		{"other secret", otherSecret},
+		{"alg none", none},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseToken(tt.token); err == nil {
+				t.Errorf("ParseToken(%q) err = nil, want error", tt.token)
+			}
+		})
+	}
+}
+
+func TestIsModerator(t *testing.T) {
+	setupJWTSetting(t, time.Hour)
+	global.JWTSetting.Moderators = []string{"moderator"}
+
+	moderator, err := GenerateToken("moderator")
+	if err != nil {
+		t.Fatalf("GenerateToken err: %v", err)
+	}
+	reader, err := GenerateToken("reader")
+	if err != nil {
+		t.Fatalf("GenerateToken err: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"moderator", moderator, true},
+		{"other app key", reader, false},
+		{"no token", "", false},
+		{"invalid token", "not-a-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			c.Request.Header.Set("token", tt.token)
+			if got := IsModerator(c); got != tt.want {
+				t.Errorf("IsModerator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
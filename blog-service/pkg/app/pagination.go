@@ -3,10 +3,16 @@ package app
 import (
 	"blog-service/global"
 	"blog-service/pkg/convert"
+	"blog-service/pkg/setting"
+	"encoding/base64"
+	"errors"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 func GetPage(c *gin.Context) int {
 	page := convert.StrTo(c.Query("page")).MustInt()
 	if page < 0 {
@@ -16,6 +22,9 @@ func GetPage(c *gin.Context) int {
 }
 
 func GetPageSize(c *gin.Context) int {
+	setting.RLock()
+	defer setting.RUnlock()
+
 	pageSize := convert.StrTo(c.Query("page_size")).MustInt()
 
 	if pageSize <= 0 {
@@ -35,3 +44,31 @@ func GetPageOffset(page, pageSize int) int {
 	}
 	return result
 }
+
+// 请求中带有cursor参数时使用游标分页, cursor为空表示从第一页开始
+func GetCursor(c *gin.Context) (cursor uint32, ok bool, err error) {
+	value, ok := c.GetQuery("cursor")
+	if !ok || value == "" {
+		return 0, ok, nil
+	}
+
+	cursor, err = DecodeCursor(value)
+	return cursor, true, err
+}
+
+// 游标对客户端不透明, 内容为最后一条记录的ID
+func EncodeCursor(id uint32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func DecodeCursor(cursor string) (uint32, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(b), 10, 32)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint32(id), nil
+}
@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache 缓存后端, 值为已经序列化的字节
+type Cache interface {
+	// Get 获取缓存的值, 不存在或已过期时返回false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 写入缓存, ttl为0时不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
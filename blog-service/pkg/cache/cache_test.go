@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// 两种缓存后端都需要满足的行为
+func testCache(t *testing.T, c Cache, expire func(d time.Duration)) {
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Get missing = %v, %v, want false, nil", ok, err)
+	}
+
+	if err := c.Set(ctx, "a", []byte("1"), 0); err != nil {
+		t.Fatalf("Set err: %v", err)
+	}
+	if err := c.Set(ctx, "b", []byte("2"), time.Minute); err != nil {
+		t.Fatalf("Set err: %v", err)
+	}
+	if err := c.Set(ctx, "a", []byte("3"), 0); err != nil {
+		t.Fatalf("Set err: %v", err)
+	}
+	if value, ok, err := c.Get(ctx, "a"); !ok || err != nil || string(value) != "3" {
+		t.Errorf("Get a = %q, %v, %v, want 3, true, nil", value, ok, err)
+	}
+
+	// 过期后不再返回
+	expire(time.Minute + time.Second)
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Errorf("Get b after ttl = true, want false")
+	}
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Errorf("Get a without ttl = false after expire, want true")
+	}
+
+	if err := c.Delete(ctx, "a", "missing"); err != nil {
+		t.Fatalf("Delete err: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Errorf("Get a after Delete = true, want false")
+	}
+	if err := c.Delete(ctx); err != nil {
+		t.Errorf("Delete without keys err: %v", err)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache(10)
+	testCache(t, c, func(d time.Duration) {
+		// 内存缓存使用真实时间, 直接修改过期时间模拟时间流逝
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, elem := range c.items {
+			entry := elem.Value.(*memoryEntry)
+			if !entry.expireAt.IsZero() {
+				entry.expireAt = entry.expireAt.Add(-d)
+			}
+		}
+	})
+}
+
+func TestMemoryCacheLRU(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2)
+
+	c.Set(ctx, "a", []byte("1"), 0)
+	c.Set(ctx, "b", []byte("2"), 0)
+	// 访问a后b成为最久未使用的键
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatalf("Get a = false, want true")
+	}
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := c.Get(ctx, key); ok != want {
+			t.Errorf("Get %s = %v, want %v", key, ok, want)
+		}
+	}
+	if c.ll.Len() != 2 || len(c.items) != 2 {
+		t.Errorf("cache holds %d list entries and %d items, want 2", c.ll.Len(), len(c.items))
+	}
+
+	// 更新已有的键不会淘汰其他键
+	c.Set(ctx, "a", []byte("4"), 0)
+	if _, ok, _ := c.Get(ctx, "c"); !ok {
+		t.Errorf("Get c after updating a = false, want true")
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis.Run err: %v", err)
+	}
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	c := NewRedisCache(client, "blog:")
+	defer c.Close()
+
+	testCache(t, c, s.FastForward)
+
+	// 所有键都带有前缀
+	c.Set(context.Background(), "d", []byte("5"), 0)
+	if value, err := s.Get("blog:d"); err != nil || value != "5" {
+		t.Errorf("redis blog:d = %q, %v, want 5, nil", value, err)
+	}
+}
@@ -0,0 +1,38 @@
+package email
+
+import (
+	"crypto/tls"
+
+	"gopkg.in/gomail.v2"
+)
+
+type Email struct {
+	*SMTPInfo
+}
+
+type SMTPInfo struct {
+	Host     string
+	Port     int
+	IsSSL    bool
+	UserName string
+	Password string
+	From     string
+}
+
+func NewEmail(info *SMTPInfo) *Email {
+	return &Email{SMTPInfo: info}
+}
+
+// 发送邮件, body为HTML内容
+func (e *Email) SendMail(to []string, subject, body string) error {
+	m := gomail.NewMessage()
+	m.SetHeader("From", e.From)
+	m.SetHeader("To", to...)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", body)
+
+	dialer := gomail.NewDialer(e.Host, e.Port, e.UserName, e.Password)
+	dialer.SSL = e.IsSSL
+	dialer.TLSConfig = &tls.Config{ServerName: e.Host}
+	return dialer.DialAndSend(m)
+}
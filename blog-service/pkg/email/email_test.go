@@ -0,0 +1,47 @@
+package email
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog-service/pkg/email/emailtest"
+)
+
+func TestSendMail(t *testing.T) {
+	server := emailtest.NewServer(t)
+	mailer := NewEmail(&SMTPInfo{Host: server.Host, Port: server.Port, From: "alert@example.com"})
+
+	to := []string{"a@example.com", "b@example.com"}
+	if err := mailer.SendMail(to, "告警", "<b>panic</b>"); err != nil {
+		t.Fatalf("SendMail err: %v", err)
+	}
+
+	msg := server.Receive(time.Second)
+	if msg == nil {
+		t.Fatal("SMTP server received no message")
+	}
+	if msg.From != "alert@example.com" {
+		t.Errorf("From = %q, want alert@example.com", msg.From)
+	}
+	if strings.Join(msg.To, ",") != strings.Join(to, ",") {
+		t.Errorf("To = %v, want %v", msg.To, to)
+	}
+	if !strings.Contains(msg.Data, "Content-Type: text/html") {
+		t.Errorf("Data = %q, want an HTML message", msg.Data)
+	}
+	body, err := msg.Body()
+	if err != nil {
+		t.Fatalf("msg.Body err: %v", err)
+	}
+	if strings.TrimSpace(body) != "<b>panic</b>" {
+		t.Errorf("body = %q, want <b>panic</b>", body)
+	}
+}
+
+func TestSendMailUnreachable(t *testing.T) {
+	mailer := NewEmail(&SMTPInfo{Host: "127.0.0.1", Port: 1, From: "alert@example.com"})
+	if err := mailer.SendMail([]string{"a@example.com"}, "告警", "body"); err == nil {
+		t.Error("SendMail to a closed port err = nil, want error")
+	}
+}
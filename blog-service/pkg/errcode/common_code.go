@@ -10,4 +10,6 @@ var (
 	UnauthorizedTokenTimeout  = NewError(10000005, "鉴权失败，Token超时")
 	UnauthorizedTokenGenerate = NewError(10000006, "鉴权失败，Token生成失败")
 	TooManyRequests           = NewError(10000007, "请求过多")
+	ServiceUnavailable        = NewError(10000008, "服务不可用")
+	Forbidden                 = NewError(10000009, "没有权限")
 )
@@ -7,11 +7,11 @@ import (
 
 type Error struct {
 	// 错误码
-	code int `json:"code"`
+	code int
 	// 错误消息
-	msg string `json:"msg"`
+	msg string
 	// 详细信息
-	details []string `json:"details"`
+	details []string
 }
 
 var codes = map[int]string{}
@@ -36,6 +36,11 @@ func (e *Error) Msg() string {
 	return e.msg
 }
 
+// 获取指定语言的错误信息
+func (e *Error) MsgIn(locale string) string {
+	return localize(e.code, locale, e.msg)
+}
+
 func (e *Error) Msgf(args []interface{}) string {
 	return fmt.Sprintf(e.msg, args...)
 }
@@ -65,6 +70,9 @@ func (e *Error) StatusCode() int {
 	case InvalidParams.Code():
 		return http.StatusBadRequest
 
+	case NotFound.Code():
+		return http.StatusNotFound
+
 	case UnauthorizedAuthNotExist.Code():
 		fallthrough
 
@@ -80,6 +88,12 @@ func (e *Error) StatusCode() int {
 	case TooManyRequests.Code():
 		return http.StatusTooManyRequests
 
+	case ServiceUnavailable.Code():
+		return http.StatusServiceUnavailable
+
+	case Forbidden.Code():
+		return http.StatusForbidden
+
 	}
 
 	return http.StatusInternalServerError
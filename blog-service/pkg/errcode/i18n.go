@@ -0,0 +1,79 @@
+package errcode
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// 错误信息的多语言目录, 每种语言一个文件, 文件名即语言标识, 如en.yaml、zh_Hant_TW.yaml
+// 新增语言只需要在locales目录下添加对应的文件
+//
+//go:embed locales/*.yaml
+var catalogFS embed.FS
+
+var catalogs = map[string]map[int]string{}
+
+// FallbackLocale 指定语言及其上级语言都没有对应的翻译时使用的语言
+const FallbackLocale = "en"
+
+func init() {
+	files, err := catalogFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, f := range files {
+		data, err := catalogFS.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		messages := map[int]string{}
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("错误信息目录 %s 格式错误: %v", f.Name(), err))
+		}
+		for code := range messages {
+			if _, ok := codes[code]; !ok {
+				panic(fmt.Sprintf("错误信息目录 %s 中的错误码 %d 不存在", f.Name(), code))
+			}
+		}
+		catalogs[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = messages
+	}
+}
+
+// Locales 返回全部支持的语言
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// 按语言获取错误信息, 依次查找该语言及其上级语言(如zh_Hant_TW、zh_Hant、zh)和FallbackLocale,
+// 都没有或未指定语言时返回默认的错误信息
+func localize(code int, locale string, msg string) string {
+	if locale == "" {
+		return msg
+	}
+	for locale != "" {
+		if m, ok := catalogs[locale][code]; ok {
+			return m
+		}
+		i := strings.LastIndex(locale, "_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	if m, ok := catalogs[FallbackLocale][code]; ok {
+		return m
+	}
+	return msg
+}
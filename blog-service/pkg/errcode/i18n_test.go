@@ -0,0 +1,41 @@
+package errcode
+
+import "testing"
+
+func TestMsgIn(t *testing.T) {
+	// 只翻译了部分错误码的语言
+	catalogs["fr"] = map[int]string{InvalidParams.Code(): "Paramètres invalides"}
+	t.Cleanup(func() { delete(catalogs, "fr") })
+
+	tests := []struct {
+		name   string
+		err    *Error
+		locale string
+		want   string
+	}{
+		{"no locale", NotFound, "", "找不到"},
+		{"locale", NotFound, "en", "Not found"},
+		{"region", InvalidParams, "zh_Hant_TW", "入參錯誤"},
+		{"parent", InvalidParams, "fr_CA", "Paramètres invalides"},
+		{"missing code", NotFound, "fr", "Not found"},
+		{"unknown locale", NotFound, "de", "Not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.MsgIn(tt.locale); got != tt.want {
+				t.Errorf("MsgIn(%q) = %q, want %q", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+// 每种语言的目录都应包含全部错误码
+func TestCatalogsComplete(t *testing.T) {
+	for _, locale := range Locales() {
+		for code := range codes {
+			if _, ok := catalogs[locale][code]; !ok {
+				t.Errorf("catalog %s is missing code %d", locale, code)
+			}
+		}
+	}
+}
@@ -2,8 +2,31 @@ package errcode
 
 var (
 	ErrorGetTagListFail = NewError(20010001, "获取标签列表失败")
-	ErrorCreateTagFail = NewError(20010002, "创建标签失败")
-	ErrorUpdateTagFail = NewError(20010003, "更新标签失败")
-	ErrorDeleteTagFail = NewError(20010004, "删除标签失败")
-	ErrorCountTagFail = NewError(20010005, "统计标签失败")
-)
\ No newline at end of file
+	ErrorCreateTagFail  = NewError(20010002, "创建标签失败")
+	ErrorUpdateTagFail  = NewError(20010003, "更新标签失败")
+	ErrorDeleteTagFail  = NewError(20010004, "删除标签失败")
+	ErrorCountTagFail   = NewError(20010005, "统计标签失败")
+
+	ErrorGetArticleFail    = NewError(20020001, "获取单个文章失败")
+	ErrorGetArticlesFail   = NewError(20020002, "获取多个文章失败")
+	ErrorCreateArticleFail = NewError(20020003, "创建文章失败")
+	ErrorUpdateArticleFail = NewError(20020004, "更新文章失败")
+	ErrorDeleteArticleFail = NewError(20020005, "删除文章失败")
+	ErrorSearchArticleFail = NewError(20020006, "搜索文章失败")
+
+	ErrorUploadFileFail = NewError(20030001, "上传文件失败")
+
+	ErrorGetCommentListFail = NewError(20040001, "获取评论列表失败")
+	ErrorCreateCommentFail  = NewError(20040002, "发表评论失败")
+	ErrorUpdateCommentFail  = NewError(20040003, "审核评论失败")
+	ErrorDeleteCommentFail  = NewError(20040004, "删除评论失败")
+
+	ErrorGetArticleRevisionListFail = NewError(20050001, "获取文章历史版本失败")
+	ErrorGetArticleRevisionDiffFail = NewError(20050002, "对比文章历史版本失败")
+	ErrorRestoreArticleRevisionFail = NewError(20050003, "恢复文章历史版本失败")
+
+	ErrorGetFeedFail = NewError(20060001, "获取订阅源失败")
+
+	ErrorExportFail = NewError(20070001, "导出数据失败")
+	ErrorImportFail = NewError(20070002, "导入数据失败")
+)
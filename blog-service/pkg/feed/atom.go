@@ -0,0 +1,71 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"feed"`
+	NS      string       `xml:"xmlns,attr"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Link    atomLink     `xml:"link"`
+	Updated string       `xml:"updated"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published,omitempty"`
+	Updated   string      `xml:"updated"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// ToAtom 输出Atom 1.0格式的订阅源
+func ToAtom(f *Feed) ([]byte, error) {
+	feed := atomFeed{
+		NS:      atomNS,
+		ID:      f.Link,
+		Title:   f.Title,
+		Link:    atomLink{Href: f.Link},
+		Updated: formatAtomTime(f.Updated),
+		Entries: make([]*atomEntry, 0, len(f.Items)),
+	}
+	for _, item := range f.Items {
+		entry := &atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link},
+			Published: formatAtomTime(item.Published),
+			Updated:   formatAtomTime(item.Updated),
+			Summary:   item.Description,
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshal(feed)
+}
+
+func formatAtomTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
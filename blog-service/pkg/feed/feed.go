@@ -0,0 +1,22 @@
+package feed
+
+import "time"
+
+// Feed 与输出格式无关的订阅源, 由RSS和Atom共用
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	Updated     time.Time
+	Items       []*Item
+}
+
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Author      string
+	Published   time.Time
+	Updated     time.Time
+}
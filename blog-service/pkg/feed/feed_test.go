@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+func testFeed() *Feed {
+	published := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	return &Feed{
+		Title:       "博客 & Go",
+		Link:        "https://example.com",
+		Description: "Go 语言编程之旅",
+		Updated:     published.Add(2 * time.Hour),
+		Items: []*Item{
+			{
+				ID:          "https://example.com/articles/2",
+				Title:       "<Generics> in Go",
+				Link:        "https://example.com/articles/2",
+				Description: "类型参数",
+				Author:      "eddycjy",
+				Published:   published.Add(time.Hour),
+				Updated:     published.Add(2 * time.Hour),
+			},
+			{
+				ID:        "urn:article:1",
+				Title:     "Hello",
+				Link:      "https://example.com/articles/1",
+				Published: published,
+				Updated:   published,
+			},
+		},
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("write %s err: %v", path, err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s err: %v", path, err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch, run go test -update to refresh:\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestToRSS(t *testing.T) {
+	got, err := ToRSS(testFeed())
+	if err != nil {
+		t.Fatalf("ToRSS err: %v", err)
+	}
+	assertGolden(t, "rss.xml", got)
+}
+
+func TestToAtom(t *testing.T) {
+	got, err := ToAtom(testFeed())
+	if err != nil {
+		t.Fatalf("ToAtom err: %v", err)
+	}
+	assertGolden(t, "atom.xml", got)
+}
+
+func TestToSitemap(t *testing.T) {
+	got, err := ToSitemap([]*URL{
+		{Loc: "https://example.com/", LastMod: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/articles/2?a=1&b=2", LastMod: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/articles/1"},
+	})
+	if err != nil {
+		t.Fatalf("ToSitemap err: %v", err)
+	}
+	assertGolden(t, "sitemap.xml", got)
+}
@@ -0,0 +1,36 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// 单个sitemap文件最多包含的URL数量
+const MaxSitemapURLs = 50000
+
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name      `xml:"urlset"`
+	NS      string        `xml:"xmlns,attr"`
+	URLs    []*sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// ToSitemap 输出sitemap.xml
+func ToSitemap(urls []*URL) ([]byte, error) {
+	set := urlSet{NS: sitemapNS, URLs: make([]*sitemapURL, 0, len(urls))}
+	for _, u := range urls {
+		set.URLs = append(set.URLs, &sitemapURL{Loc: u.Loc, LastMod: formatAtomTime(u.LastMod)})
+	}
+	return marshal(set)
+}
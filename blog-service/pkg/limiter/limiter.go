@@ -0,0 +1,36 @@
+package limiter
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juju/ratelimit"
+)
+
+// 令牌桶的区分维度
+const (
+	KeyByPath   = ""        // 同一路由共用一个令牌桶
+	KeyByIP     = "ip"      // 同一路由下每个客户端IP各自一个令牌桶
+	KeyByAppKey = "app_key" // 同一路由下每个AppKey各自一个令牌桶
+)
+
+type LimiterIface interface {
+	// 获取对应的限流器的键值对名称
+	Key(c *gin.Context) string
+	// 获取令牌桶
+	GetBucket(key string) (*ratelimit.Bucket, bool)
+	// 新增多个令牌桶
+	AddBuckets(rules ...LimiterBucketRule) LimiterIface
+}
+
+type Limiter struct {
+	limiterBuckets map[string]*ratelimit.Bucket
+}
+
+type LimiterBucketRule struct {
+	Key          string        // 自定义键值对名称
+	FillInterval time.Duration // 间隔多久时间放N个令牌
+	Capacity     int64         // 令牌桶的容量
+	Quantum      int64         // 每次到达间隔时间后所放的具体令牌数量
+	KeyBy        string        // 令牌桶的区分维度, 为空时按路由区分
+}
@@ -0,0 +1,190 @@
+package limiter
+
+import (
+	"blog-service/pkg/app"
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juju/ratelimit"
+)
+
+const keySeparator = "|"
+
+// 按客户端IP或AppKey区分的令牌桶最多保留的数量, 达到上限时只淘汰闲置的令牌桶,
+// 没有闲置的令牌桶时新的客户端共用同一路由的溢出令牌桶
+const DefaultMaxClientBuckets = 10000
+
+// 按路由进行限流, 可按客户端IP或AppKey进一步细分令牌桶
+// 细分的令牌桶在闲置到重新填满后淘汰, 此时淘汰与保留的限流效果相同
+type MethodLimiter struct {
+	*Limiter
+	mu    sync.Mutex
+	rules map[string]LimiterBucketRule
+
+	maxClientBuckets int
+	clientBuckets    map[string]*list.Element
+	lru              *list.List
+	// 客户端令牌桶达到上限后按路由共用的令牌桶, 淘汰仍在使用的令牌桶会让被限流的客户端重新获得令牌
+	overflowBuckets map[string]*ratelimit.Bucket
+	now             func() time.Time
+}
+
+type clientBucket struct {
+	key      string
+	bucket   *ratelimit.Bucket
+	idleTTL  time.Duration
+	lastUsed time.Time
+}
+
+func NewMethodLimiter() LimiterIface {
+	return newMethodLimiter(DefaultMaxClientBuckets, time.Now)
+}
+
+func newMethodLimiter(maxClientBuckets int, now func() time.Time) *MethodLimiter {
+	return &MethodLimiter{
+		Limiter:          &Limiter{limiterBuckets: make(map[string]*ratelimit.Bucket)},
+		rules:            make(map[string]LimiterBucketRule),
+		maxClientBuckets: maxClientBuckets,
+		clientBuckets:    make(map[string]*list.Element),
+		lru:              list.New(),
+		overflowBuckets:  make(map[string]*ratelimit.Bucket),
+		now:              now,
+	}
+}
+
+func (l *MethodLimiter) Key(c *gin.Context) string {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+
+	l.mu.Lock()
+	rule, ok := l.rules[path]
+	l.mu.Unlock()
+	if !ok {
+		return path
+	}
+
+	switch rule.KeyBy {
+	case KeyByIP:
+		return path + keySeparator + c.ClientIP()
+	case KeyByAppKey:
+		if claims, err := app.ParseToken(app.GetToken(c)); err == nil {
+			return path + keySeparator + claims.AppKey
+		}
+		// 未携带有效Token时退化为按客户端IP限流
+		return path + keySeparator + c.ClientIP()
+	}
+
+	return path
+}
+
+func (l *MethodLimiter) GetBucket(key string) (*ratelimit.Bucket, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, ok := l.limiterBuckets[key]; ok {
+		return bucket, true
+	}
+
+	now := l.now()
+	l.evictIdle(now)
+	if elem, ok := l.clientBuckets[key]; ok {
+		entry := elem.Value.(*clientBucket)
+		entry.lastUsed = now
+		l.lru.MoveToFront(elem)
+		return entry.bucket, true
+	}
+
+	rule, ok := l.rules[strings.SplitN(key, keySeparator, 2)[0]]
+	if !ok || rule.KeyBy == KeyByPath {
+		return nil, false
+	}
+
+	if l.maxClientBuckets > 0 && l.lru.Len() >= l.maxClientBuckets && !l.evictAnyIdle(now) {
+		return l.overflowBucket(rule), true
+	}
+
+	bucket := newBucket(rule)
+	l.clientBuckets[key] = l.lru.PushFront(&clientBucket{
+		key:      key,
+		bucket:   bucket,
+		idleTTL:  fillDuration(rule),
+		lastUsed: now,
+	})
+	return bucket, true
+}
+
+func (l *MethodLimiter) AddBuckets(rules ...LimiterBucketRule) LimiterIface {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, rule := range rules {
+		if _, ok := l.rules[rule.Key]; ok {
+			continue
+		}
+
+		l.rules[rule.Key] = rule
+		if rule.KeyBy == KeyByPath {
+			l.limiterBuckets[rule.Key] = newBucket(rule)
+		}
+	}
+
+	return l
+}
+
+// 从最久未使用的一端淘汰闲置超过idleTTL的令牌桶, 调用方需要持有锁
+func (l *MethodLimiter) evictIdle(now time.Time) {
+	for elem := l.lru.Back(); elem != nil; elem = l.lru.Back() {
+		entry := elem.Value.(*clientBucket)
+		if now.Sub(entry.lastUsed) <= entry.idleTTL {
+			return
+		}
+		l.removeClientBucket(elem)
+	}
+}
+
+// 淘汰一个闲置的令牌桶, 没有闲置的令牌桶时返回false, 调用方需要持有锁
+// 不同路由的idleTTL不同, 最久未使用的令牌桶仍在使用时其他令牌桶也可能已经闲置
+func (l *MethodLimiter) evictAnyIdle(now time.Time) bool {
+	for elem := l.lru.Back(); elem != nil; elem = elem.Prev() {
+		entry := elem.Value.(*clientBucket)
+		if now.Sub(entry.lastUsed) > entry.idleTTL {
+			l.removeClientBucket(elem)
+			return true
+		}
+	}
+	return false
+}
+
+// 获取路由的溢出令牌桶, 调用方需要持有锁
+func (l *MethodLimiter) overflowBucket(rule LimiterBucketRule) *ratelimit.Bucket {
+	bucket, ok := l.overflowBuckets[rule.Key]
+	if !ok {
+		bucket = newBucket(rule)
+		l.overflowBuckets[rule.Key] = bucket
+	}
+	return bucket
+}
+
+func (l *MethodLimiter) removeClientBucket(elem *list.Element) {
+	l.lru.Remove(elem)
+	delete(l.clientBuckets, elem.Value.(*clientBucket).key)
+}
+
+func newBucket(rule LimiterBucketRule) *ratelimit.Bucket {
+	return ratelimit.NewBucketWithQuantum(rule.FillInterval, rule.Capacity, rule.Quantum)
+}
+
+// 令牌桶从空到重新填满所需的时间
+func fillDuration(rule LimiterBucketRule) time.Duration {
+	if rule.Quantum <= 0 {
+		return rule.FillInterval
+	}
+
+	intervals := (rule.Capacity + rule.Quantum - 1) / rule.Quantum
+	return time.Duration(intervals) * rule.FillInterval
+}
@@ -0,0 +1,184 @@
+package limiter
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func newTestLimiter(maxClientBuckets int) (*MethodLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(1600000000, 0)}
+	l := newMethodLimiter(maxClientBuckets, clock.Now)
+	l.AddBuckets(
+		LimiterBucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 10, Quantum: 5, KeyBy: KeyByIP},
+		LimiterBucketRule{Key: "/upload/file", FillInterval: time.Second, Capacity: 10, Quantum: 10},
+	)
+	return l, clock
+}
+
+func TestMethodLimiterGetBucket(t *testing.T) {
+	l, _ := newTestLimiter(10)
+
+	if _, ok := l.GetBucket("/unknown"); ok {
+		t.Errorf("GetBucket(/unknown) = true, want false")
+	}
+	if _, ok := l.GetBucket("/upload/file|10.0.0.1"); ok {
+		t.Errorf("GetBucket for a path rule with client key = true, want false")
+	}
+
+	path, ok := l.GetBucket("/upload/file")
+	if !ok {
+		t.Fatalf("GetBucket(/upload/file) = false, want true")
+	}
+	if again, _ := l.GetBucket("/upload/file"); again != path {
+		t.Errorf("path bucket is not shared")
+	}
+
+	a, _ := l.GetBucket("/auth|10.0.0.1")
+	b, _ := l.GetBucket("/auth|10.0.0.2")
+	if a == nil || b == nil || a == b {
+		t.Fatalf("client buckets = %p, %p, want two different buckets", a, b)
+	}
+	if got := a.TakeAvailable(20); got != 10 {
+		t.Errorf("TakeAvailable = %d, want capacity 10", got)
+	}
+	// 耗尽的令牌桶在闲置期内不会被替换为新的令牌桶
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again != a {
+		t.Errorf("client bucket was replaced while in use")
+	}
+}
+
+func TestMethodLimiterEvictIdle(t *testing.T) {
+	l, clock := newTestLimiter(10)
+
+	a, _ := l.GetBucket("/auth|10.0.0.1")
+	path, _ := l.GetBucket("/upload/file")
+
+	// 容量10、每秒放5个令牌, 2秒后重新填满
+	clock.Advance(2 * time.Second)
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again != a {
+		t.Fatalf("client bucket was evicted before it refilled")
+	}
+
+	clock.Advance(2*time.Second + time.Millisecond)
+	l.GetBucket("/auth|10.0.0.2")
+	if _, ok := l.clientBuckets["/auth|10.0.0.1"]; ok {
+		t.Errorf("idle client bucket was not evicted")
+	}
+	if len(l.clientBuckets) != 1 || l.lru.Len() != 1 {
+		t.Errorf("limiter holds %d client buckets, want 1", len(l.clientBuckets))
+	}
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again == a {
+		t.Errorf("GetBucket returned an evicted bucket")
+	}
+
+	// 按路由共用的令牌桶不会被淘汰
+	clock.Advance(time.Hour)
+	if again, _ := l.GetBucket("/upload/file"); again != path {
+		t.Errorf("path bucket was evicted")
+	}
+}
+
+func TestMethodLimiterMaxClientBuckets(t *testing.T) {
+	l, clock := newTestLimiter(2)
+
+	a, _ := l.GetBucket("/auth|10.0.0.1")
+	b, _ := l.GetBucket("/auth|10.0.0.2")
+	if got := a.TakeAvailable(20); got != 10 {
+		t.Fatalf("TakeAvailable = %d, want capacity 10", got)
+	}
+
+	// 达到上限且没有闲置的令牌桶时, 新的客户端共用溢出令牌桶, 已有的令牌桶不会被淘汰
+	c, _ := l.GetBucket("/auth|10.0.0.3")
+	d, _ := l.GetBucket("/auth|10.0.0.4")
+	if c == nil || c != d || c == a || c == b {
+		t.Fatalf("buckets at capacity = %p, %p, want one shared overflow bucket", c, d)
+	}
+	if len(l.clientBuckets) != 2 || l.lru.Len() != 2 {
+		t.Fatalf("limiter holds %d client buckets, want 2", len(l.clientBuckets))
+	}
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again != a {
+		t.Errorf("exhausted bucket was evicted while in use")
+	}
+	if got := a.TakeAvailable(1); got != 0 {
+		t.Errorf("TakeAvailable after GetBucket = %d, want the exhausted bucket", got)
+	}
+
+	// 10.0.0.2闲置后被淘汰, 新的客户端重新获得自己的令牌桶
+	clock.Advance(2 * time.Second)
+	l.GetBucket("/auth|10.0.0.1")
+	clock.Advance(time.Second)
+	e, _ := l.GetBucket("/auth|10.0.0.5")
+	if e == nil || e == c {
+		t.Fatalf("GetBucket after an idle bucket expired returned the overflow bucket")
+	}
+	if _, ok := l.clientBuckets["/auth|10.0.0.2"]; ok {
+		t.Errorf("idle client bucket was not evicted")
+	}
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again != a {
+		t.Errorf("recently used bucket was evicted")
+	}
+}
+
+// 最久未使用的令牌桶仍在使用时, 淘汰其他路由下已经闲置的令牌桶
+func TestMethodLimiterEvictIdleBehindActive(t *testing.T) {
+	l, clock := newTestLimiter(2)
+	l.AddBuckets(LimiterBucketRule{Key: "/login", FillInterval: time.Second, Capacity: 5, Quantum: 5, KeyBy: KeyByIP})
+
+	a, _ := l.GetBucket("/auth|10.0.0.1")
+	l.GetBucket("/login|10.0.0.1")
+
+	// /auth的令牌桶2秒后才闲置, /login的令牌桶1秒后闲置
+	clock.Advance(1500 * time.Millisecond)
+	b, _ := l.GetBucket("/auth|10.0.0.2")
+	if _, ok := l.clientBuckets["/login|10.0.0.1"]; ok {
+		t.Errorf("idle client bucket was not evicted")
+	}
+	if _, ok := l.clientBuckets["/auth|10.0.0.2"]; !ok || b == l.overflowBuckets["/auth"] {
+		t.Errorf("new client got the overflow bucket while an idle bucket could be evicted")
+	}
+	if again, _ := l.GetBucket("/auth|10.0.0.1"); again != a {
+		t.Errorf("active bucket was evicted")
+	}
+}
+
+func TestMethodLimiterKey(t *testing.T) {
+	l := NewMethodLimiter().AddBuckets(
+		LimiterBucketRule{Key: "/auth", FillInterval: time.Second, Capacity: 10, Quantum: 10, KeyBy: KeyByIP},
+		LimiterBucketRule{Key: "/api/v1/tags", FillInterval: time.Second, Capacity: 10, Quantum: 10, KeyBy: KeyByAppKey},
+		LimiterBucketRule{Key: "/upload/file", FillInterval: time.Second, Capacity: 10, Quantum: 10},
+	)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/auth", "/auth|10.0.0.1"},
+		// 未携带有效Token时按客户端IP区分
+		{"/api/v1/tags", "/api/v1/tags|10.0.0.1"},
+		{"/upload/file", "/upload/file"},
+		{"/unknown", "/unknown"},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", tt.path, nil)
+		c.Request.RemoteAddr = "10.0.0.1:1234"
+		if got := l.Key(c); got != tt.want {
+			t.Errorf("Key(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
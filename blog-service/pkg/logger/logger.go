@@ -8,6 +8,8 @@ import (
 	"log"
 	"runtime"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Level int8
@@ -43,6 +45,7 @@ func (l Level) String() string {
 
 type Logger struct {
 	newLogger *log.Logger
+	writer    io.Writer
 	ctx       context.Context
 	level     Level
 	fields    Fields
@@ -51,7 +54,16 @@ type Logger struct {
 
 func NewLogger(w io.Writer, prefix string, flag int) *Logger {
 	l := log.New(w, prefix, flag)
-	return &Logger{newLogger: l}
+	return &Logger{newLogger: l, writer: w}
+}
+
+// 关闭日志的输出目标, 输出目标未实现io.Closer时不做处理
+func (l *Logger) Close() error {
+	if c, ok := l.writer.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
 }
 
 func (l *Logger) clone() *Logger {
@@ -69,8 +81,10 @@ func (l *Logger) WithLevel(lvl Level) *Logger {
 // 设置日志公共字段
 func (l *Logger) WithFields(f Fields) *Logger {
 	ll := l.clone()
-	if ll.fields == nil {
-		ll.fields = make(Fields)
+	// 复制一份公共字段, 避免修改原有Logger的字段
+	ll.fields = make(Fields, len(l.fields)+len(f))
+	for k, v := range l.fields {
+		ll.fields[k] = v
 	}
 	for k, v := range f {
 		ll.fields[k] = v
@@ -85,6 +99,34 @@ func (l *Logger) WithContext(ctx context.Context) *Logger {
 	return ll
 }
 
+// 设置日志上下文并写入链路信息, 优先读取ctx中的span, 其次读取中间件写入的X-Trace-ID和X-Span-ID
+func (l *Logger) WithTrace(ctx context.Context) *Logger {
+	ll := l.WithContext(ctx)
+	if ctx == nil {
+		return ll
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		return ll.WithFields(Fields{
+			"trace_id": spanContext.TraceID().String(),
+			"span_id":  spanContext.SpanID().String(),
+		})
+	}
+
+	fields := Fields{}
+	if traceID, ok := ctx.Value("X-Trace-ID").(string); ok && traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	if spanID, ok := ctx.Value("X-Span-ID").(string); ok && spanID != "" {
+		fields["span_id"] = spanID
+	}
+	if len(fields) == 0 {
+		return ll
+	}
+
+	return ll.WithFields(fields)
+}
+
 // 设置当前某一层调用堆栈信息（程序计数器、文件信息和行号）
 func (l *Logger) WithCaller(skip int) *Logger {
 	ll := l.clone()
@@ -122,7 +164,7 @@ func (l *Logger) JSONFormat(message string) map[string]interface{} {
 	data["time"] = time.Now().Local().UnixNano()
 	data["message"] = message
 	data["caller"] = l.callers
-	if len(l.fields) >0 {
+	if len(l.fields) > 0 {
 		for k, v := range l.fields {
 			if _, ok := data[k]; !ok {
 				data[k] = v
@@ -157,29 +199,43 @@ func (l *Logger) Output(message string) {
 	}
 }
 
-// 日志分级输出
+// 日志分级输出, 需要链路信息时先调用WithTrace
 func (l *Logger) Debug(v ...interface{}) {
 	l.WithLevel(LevelDebug).Output(fmt.Sprint(v...))
 }
+
 func (l *Logger) Debugf(format string, v ...interface{}) {
 	l.WithLevel(LevelDebug).Output(fmt.Sprintf(format, v...))
 }
+
 func (l *Logger) Info(v ...interface{}) {
 	l.WithLevel(LevelInfo).Output(fmt.Sprint(v...))
 }
+
 func (l *Logger) Infof(format string, v ...interface{}) {
 	l.WithLevel(LevelInfo).Output(fmt.Sprintf(format, v...))
 }
+
+func (l *Logger) Warn(v ...interface{}) {
+	l.WithLevel(LevelWarn).Output(fmt.Sprint(v...))
+}
+
+func (l *Logger) Warnf(format string, v ...interface{}) {
+	l.WithLevel(LevelWarn).Output(fmt.Sprintf(format, v...))
+}
+
 func (l *Logger) Error(v ...interface{}) {
 	l.WithLevel(LevelError).Output(fmt.Sprint(v...))
 }
 
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.WithLevel(LevelError).Output( fmt.Sprintf(format, v...))
+	l.WithLevel(LevelError).Output(fmt.Sprintf(format, v...))
 }
+
 func (l *Logger) Fatal(v ...interface{}) {
 	l.WithLevel(LevelFatal).Output(fmt.Sprint(v...))
 }
-func (l *Logger) Fatalf(format string, v ...interface{}){
+
+func (l *Logger) Fatalf(format string, v ...interface{}) {
 	l.WithLevel(LevelFatal).Output(fmt.Sprintf(format, v...))
-}
\ No newline at end of file
+}
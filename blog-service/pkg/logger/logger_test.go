@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &data); err != nil {
+		t.Fatalf("json.Unmarshal(%q) err: %v", buf.String(), err)
+	}
+	buf.Reset()
+	return data
+}
+
+func TestWithTrace(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	headerCtx := context.WithValue(context.WithValue(context.Background(), "X-Trace-ID", "header-trace"), "X-Span-ID", "header-span")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantTrace interface{}
+		wantSpan  interface{}
+	}{
+		{"span", spanCtx, traceID.String(), spanID.String()},
+		{"middleware values", headerCtx, "header-trace", "header-span"},
+		{"no trace", context.Background(), nil, nil},
+		{"nil ctx", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := NewLogger(&buf, "", 0)
+
+			l.WithTrace(tt.ctx).Errorf("failed: %d", 1)
+			data := decodeLine(t, &buf)
+			if data["message"] != "failed: 1" || data["level"] != "error" {
+				t.Errorf("log = %v, want an error line with the message", data)
+			}
+			if data["trace_id"] != tt.wantTrace || data["span_id"] != tt.wantSpan {
+				t.Errorf("trace_id, span_id = %v, %v, want %v, %v", data["trace_id"], data["span_id"], tt.wantTrace, tt.wantSpan)
+			}
+		})
+	}
+}
+
+func TestWithTraceKeepsLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, "", 0).WithFields(Fields{"app": "blog"})
+
+	l.WithTrace(context.WithValue(context.Background(), "X-Trace-ID", "abc")).Info("traced")
+	if data := decodeLine(t, &buf); data["trace_id"] != "abc" || data["app"] != "blog" {
+		t.Errorf("traced log = %v, want trace_id and the common fields", data)
+	}
+
+	l.Info("plain")
+	if data := decodeLine(t, &buf); data["trace_id"] != nil || data["app"] != "blog" {
+		t.Errorf("plain log = %v, want no trace_id from the derived logger", data)
+	}
+}
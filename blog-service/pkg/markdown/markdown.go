@@ -0,0 +1,156 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(
+			extension.Linkify,
+			extension.Strikethrough,
+			extension.TaskList,
+			// 白名单不允许style属性, 表格对齐使用align属性
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	// 在UGC白名单的基础上允许代码高亮使用的language-*类名和任务列表的复选框
+	policy = newPolicy()
+)
+
+type Heading struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// Render 将Markdown渲染为经过白名单过滤的HTML,
+// 文章包含标题时会在开头插入<nav class="toc">目录
+func Render(source string) (string, error) {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(parser.NewContext(parser.WithIDs(newIDs()))))
+
+	var headings []*Heading
+	err := ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		id, _ := heading.AttributeString("id")
+		if id, ok := id.([]byte); ok {
+			headings = append(headings, &Heading{Level: heading.Level, ID: string(id), Text: string(heading.Text(src))})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return "", err
+	}
+
+	// 目录由服务端生成, 不经过白名单过滤
+	return renderTOC(headings) + string(policy.SanitizeBytes(buf.Bytes())), nil
+}
+
+// 按标题层级生成嵌套的目录列表
+func renderTOC(headings []*Heading) string {
+	if len(headings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`<nav class="toc">`)
+	base := headings[0].Level
+	for _, h := range headings {
+		if h.Level < base {
+			base = h.Level
+		}
+	}
+
+	// 子目录嵌套在上一级的<li>中, 跳级的标题补齐空的<li>
+	depth := 0
+	for i, h := range headings {
+		level := h.Level - base + 1
+		switch {
+		case i == 0:
+			b.WriteString("<ul>")
+			depth = 1
+		case level > depth:
+			b.WriteString("<ul>")
+			depth++
+		default:
+			b.WriteString("</li>")
+			for ; depth > level; depth-- {
+				b.WriteString("</ul></li>")
+			}
+		}
+		for ; depth < level; depth++ {
+			b.WriteString("<li><ul>")
+		}
+		fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, html.EscapeString(h.ID), html.EscapeString(h.Text))
+	}
+	b.WriteString("</li>")
+	for ; depth > 1; depth-- {
+		b.WriteString("</ul></li>")
+	}
+	b.WriteString("</ul>")
+	b.WriteString("</nav>")
+
+	return b.String()
+}
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// 标题id只保留ASCII字母和数字, 避免被白名单过滤掉;
+// 无法生成时使用heading-序号, 重复时追加序号
+type ids struct {
+	values map[string]bool
+	count  int
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func newIDs() parser.IDs {
+	return &ids{values: map[string]bool{}}
+}
+
+func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
+	s.count++
+	id := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(string(value)), "-"), "-")
+	if id == "" {
+		id = fmt.Sprintf("heading-%d", s.count)
+	}
+
+	result := id
+	for i := 1; s.values[result]; i++ {
+		result = fmt.Sprintf("%s-%d", id, i)
+	}
+	s.values[result] = true
+	return []byte(result)
+}
+
+func (s *ids) Put(value []byte) {
+	s.values[string(value)] = true
+}
@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		want     []string
+		unwanted []string
+	}{
+		{
+			name:     "script tag",
+			source:   "<script>alert(1)</script>\n\nhi",
+			want:     []string{"<p>hi</p>"},
+			unwanted: []string{"<script", "alert(1)"},
+		},
+		{
+			name:     "javascript link",
+			source:   "[x](javascript:alert(1))",
+			want:     []string{"<p>x</p>"},
+			unwanted: []string{"javascript:", "href"},
+		},
+		{
+			name:     "javascript link with mixed case",
+			source:   "<a href=\"JaVaScRiPt:alert(1)\">x</a>",
+			unwanted: []string{"javascript:", "JaVaScRiPt:", "href"},
+		},
+		{
+			name:     "on* attribute",
+			source:   "<a href=\"https://example.com\" onclick=\"e()\">a</a> <img src=\"a.png\" onerror=\"e()\">",
+			unwanted: []string{"onclick", "onerror", "e()"},
+		},
+		{
+			name:   "safe link",
+			source: "[x](https://example.com)",
+			want:   []string{`<a href="https://example.com" rel="nofollow">x</a>`},
+		},
+		{
+			name:   "language class",
+			source: "```go\nfmt.Println()\n```",
+			want:   []string{`<code class="language-go">`},
+		},
+		{
+			name:     "invalid language class",
+			source:   "```a\"onclick=e()\nx\n```",
+			want:     []string{"<code>x\n</code>"},
+			unwanted: []string{"class=", "onclick"},
+		},
+		{
+			name:   "task list",
+			source: "- [x] done\n- [ ] todo",
+			want: []string{
+				`<input checked="" disabled="" type="checkbox"> done`,
+				`<input disabled="" type="checkbox"> todo`,
+			},
+		},
+		{
+			name:     "other input",
+			source:   "<input type=\"text\" value=\"x\">",
+			unwanted: []string{`type="text"`, `value=`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render err: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, s)
+				}
+			}
+			for _, s := range tt.unwanted {
+				if strings.Contains(got, s) {
+					t.Errorf("Render(%q) = %q, want no %q", tt.source, got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderHeadingIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{"slug", "# Hello, World!", []string{`<h1 id="hello-world">Hello, World!</h1>`}},
+		{"duplicate", "## Intro\n## Intro", []string{`<h2 id="intro">`, `<h2 id="intro-1">`}},
+		{"non ascii", "# Go\n## 中文标题", []string{`<h2 id="heading-2">中文标题</h2>`}},
+		{"toc links", "# A\n## B", []string{`<nav class="toc"><ul><li><a href="#a">A</a><ul><li><a href="#b">B</a>`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.source)
+			if err != nil {
+				t.Fatalf("Render err: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render(%q) = %q, want it to contain %q", tt.source, got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderWithoutHeadings(t *testing.T) {
+	got, err := Render("plain text")
+	if err != nil {
+		t.Fatalf("Render err: %v", err)
+	}
+	if strings.Contains(got, "<nav") {
+		t.Errorf("Render = %q, want no toc without headings", got)
+	}
+}
+
+func TestRenderTOC(t *testing.T) {
+	tests := []struct {
+		name     string
+		headings []*Heading
+		want     string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name:     "flat",
+			headings: []*Heading{{2, "a", "A"}, {2, "b", "B"}},
+			want:     `<nav class="toc"><ul><li><a href="#a">A</a></li><li><a href="#b">B</a></li></ul></nav>`,
+		},
+		{
+			name:     "nested",
+			headings: []*Heading{{1, "a", "A"}, {2, "b", "B"}, {3, "c", "C"}, {1, "d", "D"}},
+			want: `<nav class="toc"><ul><li><a href="#a">A</a><ul><li><a href="#b">B</a><ul><li><a href="#c">C</a></li></ul></li></ul></li>` +
+				`<li><a href="#d">D</a></li></ul></nav>`,
+		},
+		{
+			name:     "skipped level",
+			headings: []*Heading{{2, "a", "A"}, {4, "b", "B"}, {2, "c", "C"}},
+			want:     `<nav class="toc"><ul><li><a href="#a">A</a><ul><li><ul><li><a href="#b">B</a></li></ul></li></ul></li><li><a href="#c">C</a></li></ul></nav>`,
+		},
+		{
+			name:     "starts deeper than the top level",
+			headings: []*Heading{{3, "a", "A"}, {1, "b", "B"}},
+			want:     `<nav class="toc"><ul><li><ul><li><ul><li><a href="#a">A</a></li></ul></li></ul></li><li><a href="#b">B</a></li></ul></nav>`,
+		},
+		{
+			name:     "escaped",
+			headings: []*Heading{{1, `a"b`, "<T>"}},
+			want:     `<nav class="toc"><ul><li><a href="#a&#34;b">&lt;T&gt;</a></li></ul></nav>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderTOC(tt.headings); got != tt.want {
+				t.Errorf("renderTOC() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
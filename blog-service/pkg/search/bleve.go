@@ -0,0 +1,164 @@
+package search
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// 列出文档ID时每批读取的数量
+const idsBatchSize = 1000
+
+// 各字段在相关度计算中的权重
+var fieldBoosts = map[string]float64{
+	"title":   3,
+	"desc":    2,
+	"content": 1,
+}
+
+type BleveIndexer struct {
+	index bleve.Index
+}
+
+// 打开path下的bleve索引, 不存在时新建; path为空时使用内存索引
+func NewBleveIndexer(path string) (*BleveIndexer, error) {
+	var index bleve.Index
+	var err error
+	if path == "" {
+		index, err = bleve.NewMemOnly(newIndexMapping())
+	} else if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+		index, err = bleve.New(path, newIndexMapping())
+	} else {
+		index, err = bleve.Open(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BleveIndexer{index: index}, nil
+}
+
+func newIndexMapping() mapping.IndexMapping {
+	// 使用CJK分词器对中文按二元组切分, 英文按单词切分
+	textField := bleve.NewTextFieldMapping()
+	textField.Analyzer = cjk.AnalyzerName
+	textField.Store = true
+	textField.IncludeTermVectors = true
+
+	stateField := bleve.NewNumericFieldMapping()
+	stateField.Store = false
+
+	// 只用于对账, 不参与检索
+	modifiedOnField := bleve.NewNumericFieldMapping()
+	modifiedOnField.Index = false
+
+	articleMapping := bleve.NewDocumentMapping()
+	articleMapping.AddFieldMappingsAt("title", textField)
+	articleMapping.AddFieldMappingsAt("desc", textField)
+	articleMapping.AddFieldMappingsAt("content", textField)
+	articleMapping.AddFieldMappingsAt("state", stateField)
+	articleMapping.AddFieldMappingsAt("modified_on", modifiedOnField)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = articleMapping
+	indexMapping.DefaultAnalyzer = cjk.AnalyzerName
+	return indexMapping
+}
+
+func (b *BleveIndexer) Index(doc *Document) error {
+	return b.index.Index(strconv.FormatUint(uint64(doc.ID), 10), map[string]interface{}{
+		"title":       doc.Title,
+		"desc":        doc.Desc,
+		"content":     doc.Content,
+		"state":       float64(doc.State),
+		"modified_on": float64(doc.ModifiedOn),
+	})
+}
+
+func (b *BleveIndexer) Delete(id uint32) error {
+	return b.index.Delete(strconv.FormatUint(uint64(id), 10))
+}
+
+func (b *BleveIndexer) Search(req *Request) (*Result, error) {
+	var fieldQueries []query.Query
+	for field, boost := range fieldBoosts {
+		q := bleve.NewMatchQuery(req.Query)
+		q.SetField(field)
+		q.SetBoost(boost)
+		fieldQueries = append(fieldQueries, q)
+	}
+
+	state := float64(req.State)
+	inclusive := true
+	stateQuery := bleve.NewNumericRangeInclusiveQuery(&state, &state, &inclusive, &inclusive)
+	stateQuery.SetField("state")
+
+	searchRequest := bleve.NewSearchRequestOptions(
+		bleve.NewConjunctionQuery(bleve.NewDisjunctionQuery(fieldQueries...), stateQuery),
+		req.Size,
+		req.Offset,
+		false,
+	)
+	searchRequest.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	for field := range fieldBoosts {
+		searchRequest.Highlight.AddField(field)
+	}
+
+	searchResult, err := b.index.Search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Total: int(searchResult.Total)}
+	for _, hit := range searchResult.Hits {
+		id, err := strconv.ParseUint(hit.ID, 10, 32)
+		if err != nil {
+			continue
+		}
+		result.Hits = append(result.Hits, &Hit{
+			ID:         uint32(id),
+			Score:      hit.Score,
+			Highlights: hit.Fragments,
+		})
+	}
+
+	return result, nil
+}
+
+func (b *BleveIndexer) Count() (uint64, error) {
+	return b.index.DocCount()
+}
+
+// 没有记录modified_on的旧文档返回0
+func (b *BleveIndexer) Versions() (map[uint32]uint32, error) {
+	versions := map[uint32]uint32{}
+	for offset := 0; ; offset += idsBatchSize {
+		searchRequest := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), idsBatchSize, offset, false)
+		searchRequest.SortBy([]string{"_id"})
+		searchRequest.Fields = []string{"modified_on"}
+		searchResult, err := b.index.Search(searchRequest)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range searchResult.Hits {
+			id, err := strconv.ParseUint(hit.ID, 10, 32)
+			if err != nil {
+				continue
+			}
+			modifiedOn, _ := hit.Fields["modified_on"].(float64)
+			versions[uint32(id)] = uint32(modifiedOn)
+		}
+		if len(searchResult.Hits) < idsBatchSize {
+			return versions, nil
+		}
+	}
+}
+
+func (b *BleveIndexer) Close() error {
+	return b.index.Close()
+}
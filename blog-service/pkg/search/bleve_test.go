@@ -0,0 +1,132 @@
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestIndexer(t *testing.T) *BleveIndexer {
+	t.Helper()
+
+	indexer, err := NewBleveIndexer("")
+	if err != nil {
+		t.Fatalf("NewBleveIndexer err: %v", err)
+	}
+	t.Cleanup(func() { indexer.Close() })
+	return indexer
+}
+
+func hitIDs(result *Result) []uint32 {
+	ids := make([]uint32, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestBleveIndexerSearch(t *testing.T) {
+	indexer := newTestIndexer(t)
+	docs := []*Document{
+		{ID: 1, Title: "Go语言入门", Desc: "基础语法", Content: "变量和函数", State: 1},
+		{ID: 2, Title: "数据库设计", Desc: "Go语言访问数据库", Content: "索引和事务", State: 1},
+		{ID: 3, Title: "Go语言草稿", Desc: "未发布", Content: "草稿内容", State: 0},
+	}
+	for _, doc := range docs {
+		if err := indexer.Index(doc); err != nil {
+			t.Fatalf("Index(%d) err: %v", doc.ID, err)
+		}
+	}
+
+	// 标题命中的权重高于描述命中, 其他状态的文章不返回
+	result, err := indexer.Search(&Request{Query: "Go语言", State: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("Search err: %v", err)
+	}
+	if got := hitIDs(result); result.Total != 2 || !reflect.DeepEqual(got, []uint32{1, 2}) {
+		t.Errorf("Search = %v (total %d), want [1 2] (total 2)", got, result.Total)
+	}
+	if len(result.Hits[0].Highlights["title"]) == 0 {
+		t.Errorf("Highlights = %v, want title fragments", result.Hits[0].Highlights)
+	}
+
+	result, err = indexer.Search(&Request{Query: "Go语言", State: 0, Size: 10})
+	if err != nil {
+		t.Fatalf("Search err: %v", err)
+	}
+	if got := hitIDs(result); !reflect.DeepEqual(got, []uint32{3}) {
+		t.Errorf("Search state 0 = %v, want [3]", got)
+	}
+
+	// 分页
+	result, err = indexer.Search(&Request{Query: "Go语言", State: 1, Offset: 1, Size: 1})
+	if err != nil {
+		t.Fatalf("Search err: %v", err)
+	}
+	if got := hitIDs(result); result.Total != 2 || !reflect.DeepEqual(got, []uint32{2}) {
+		t.Errorf("Search offset 1 = %v (total %d), want [2] (total 2)", got, result.Total)
+	}
+
+	// 更新后按新内容检索
+	if err := indexer.Index(&Document{ID: 2, Title: "数据库设计", Desc: "关系模型", Content: "索引和事务", State: 1}); err != nil {
+		t.Fatalf("Index err: %v", err)
+	}
+	if err := indexer.Delete(1); err != nil {
+		t.Fatalf("Delete err: %v", err)
+	}
+	result, err = indexer.Search(&Request{Query: "Go语言", State: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("Search err: %v", err)
+	}
+	if result.Total != 0 {
+		t.Errorf("Search after update and delete = %v, want none", hitIDs(result))
+	}
+	if count, err := indexer.Count(); err != nil || count != 2 {
+		t.Errorf("Count = %d, %v, want 2, nil", count, err)
+	}
+}
+
+func TestBleveIndexerVersions(t *testing.T) {
+	indexer := newTestIndexer(t)
+
+	// 超过一批的数量以覆盖分页读取
+	want := map[uint32]uint32{}
+	for id := uint32(1); id <= idsBatchSize+5; id++ {
+		if err := indexer.Index(&Document{ID: id, Title: "title", State: 1, ModifiedOn: 1600000000 + id}); err != nil {
+			t.Fatalf("Index(%d) err: %v", id, err)
+		}
+		want[id] = 1600000000 + id
+	}
+
+	versions, err := indexer.Versions()
+	if err != nil {
+		t.Fatalf("Versions err: %v", err)
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("Versions returned %d documents, want %d", len(versions), len(want))
+	}
+}
+
+func TestNewBleveIndexerReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	indexer, err := NewBleveIndexer(path)
+	if err != nil {
+		t.Fatalf("NewBleveIndexer err: %v", err)
+	}
+	if err := indexer.Index(&Document{ID: 7, Title: "持久化", State: 1}); err != nil {
+		t.Fatalf("Index err: %v", err)
+	}
+	if err := indexer.Close(); err != nil {
+		t.Fatalf("Close err: %v", err)
+	}
+
+	// 已存在的索引直接打开, 保留原有的文档
+	indexer, err = NewBleveIndexer(path)
+	if err != nil {
+		t.Fatalf("NewBleveIndexer reopen err: %v", err)
+	}
+	defer indexer.Close()
+	if versions, err := indexer.Versions(); err != nil || !reflect.DeepEqual(versions, map[uint32]uint32{7: 0}) {
+		t.Errorf("Versions after reopen = %v, %v, want map[7:0], nil", versions, err)
+	}
+}
@@ -0,0 +1,44 @@
+package search
+
+// 文章全文检索的索引, 默认实现为内嵌的bleve索引, 可替换为外部的搜索服务
+type Indexer interface {
+	// 新增或更新文档
+	Index(doc *Document) error
+	// 删除文档
+	Delete(id uint32) error
+	// 按关键字检索, 返回按相关度排序的结果
+	Search(req *Request) (*Result, error)
+	// 索引中的文档数量
+	Count() (uint64, error)
+	// 索引中全部文档的ID及其ModifiedOn, 用于与数据库对账
+	Versions() (map[uint32]uint32, error)
+	Close() error
+}
+
+type Document struct {
+	ID      uint32
+	Title   string
+	Desc    string
+	Content string
+	State   uint8
+	// 文章的修改时间, 与数据库中的不同时说明索引已经过期
+	ModifiedOn uint32
+}
+
+type Request struct {
+	Query  string
+	State  uint8
+	Offset int
+	Size   int
+}
+
+type Result struct {
+	Total int
+	Hits  []*Hit
+}
+
+type Hit struct {
+	ID         uint32
+	Score      float64
+	Highlights map[string][]string // 以字段名为键的高亮片段
+}
@@ -5,18 +5,27 @@ import (
 )
 
 type ServerSettingS struct {
-	RunMode      string
-	HttpPort     string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
+	RunMode         string
+	HttpPort        string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	ShutdownDelay   time.Duration
+	ShutdownTimeout time.Duration
 }
 
 type AppSettingS struct {
-	DefaultPageSize int
-	MaxPageSize     int
-	LogSavePath     string
-	LogFileName     string
-	LogFileExt      string
+	DefaultPageSize       int
+	MaxPageSize           int
+	LogSavePath           string
+	LogFileName           string
+	LogFileExt            string
+	UploadSavePath        string
+	UploadServerUrl       string
+	UploadImageMaxSize    int
+	UploadImageAllowExts  []string
+	UploadDocMaxSize      int
+	UploadDocAllowExts    []string
+	DefaultContextTimeout time.Duration
 }
 
 type DatabaseSettingS struct {
@@ -25,17 +34,87 @@ type DatabaseSettingS struct {
 	Password     string
 	Host         string
 	DBName       string
-	TablePrefix  string
 	Charset      string
 	ParseTime    bool
 	MaxIdleConns int
 	MaxOpenConns int
 }
 
+type EmailSettingS struct {
+	Host     string
+	Port     int
+	UserName string
+	Password string
+	IsSSL    bool
+	From     string
+	To       []string
+}
+
+type TracerSettingS struct {
+	ServiceName string
+	Exporter    string
+	Endpoint    string
+}
+
+type SearchSettingS struct {
+	IndexPath string
+}
+
+type SiteSettingS struct {
+	BaseUrl     string
+	Title       string
+	Description string
+	FeedSize    int
+}
+
+type CacheSettingS struct {
+	Driver        string
+	Capacity      int
+	KeyPrefix     string
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	ArticleTTL    time.Duration
+	TagListTTL    time.Duration
+}
+
+type SchedulerSettingS struct {
+	PublishInterval  time.Duration
+	PublishBatchSize int
+}
+
+type JWTSettingS struct {
+	Secret string
+	Issuer string
+	Expire time.Duration
+	// 可以审核评论的AppKey, 只有这些AppKey签发的Token能查看和审核未通过的评论
+	Moderators []string
+}
+
+type LimiterSettingS struct {
+	Rules []LimiterRuleS
+}
+
+type LimiterRuleS struct {
+	Key          string
+	FillInterval time.Duration
+	Capacity     int64
+	Quantum      int64
+	KeyBy        string
+}
+
+// 读取配置段到v中, v需要为指针, 配置变更时会重新读取
 func (s *Setting) ReadSection(k string, v interface{}) error {
+	mu.Lock()
+	defer mu.Unlock()
+
 	err := s.vp.UnmarshalKey(k, v)
 	if err != nil {
 		return err
 	}
+
+	if _, ok := s.sections[k]; !ok {
+		s.sections[k] = v
+	}
 	return nil
 }
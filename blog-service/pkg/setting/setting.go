@@ -1,20 +1,118 @@
 package setting
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// 配置热更新时持有写锁, 读取global中的配置时需要持有读锁
+var mu sync.RWMutex
 
 type Setting struct {
-	vp *viper.Viper
+	vp        *viper.Viper
+	sections  map[string]interface{}
+	callbacks []func()
+	onErrors  []func(error)
 }
 
-func NewSetting() (*Setting, error) {
+func NewSetting(configs ...string) (*Setting, error) {
 	vp := viper.New()
 	vp.SetConfigName("config")
-	vp.AddConfigPath("configs/")
+	for _, config := range configs {
+		if config != "" {
+			vp.AddConfigPath(config)
+		}
+	}
 	vp.SetConfigType("yaml")
 	err := vp.ReadInConfig()
 
 	if err != nil {
 		return nil, err
 	}
-	return &Setting{vp}, nil
+
+	s := &Setting{vp: vp, sections: make(map[string]interface{})}
+	s.WatchSettingChange()
+	return s, nil
+}
+
+func RLock() {
+	mu.RLock()
+}
+
+func RUnlock() {
+	mu.RUnlock()
+}
+
+// 注册配置变更后的回调函数, 回调在写锁内执行, 不能再调用RLock
+func (s *Setting) OnChange(fn func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	s.callbacks = append(s.callbacks, fn)
+}
+
+// 注册配置热更新失败时的回调函数, 失败时保留原有的配置
+// 没有注册回调时使用标准库log输出错误
+func (s *Setting) OnError(fn func(error)) {
+	mu.Lock()
+	defer mu.Unlock()
+	s.onErrors = append(s.onErrors, fn)
+}
+
+// 监听配置文件的变更, 变更后重新读取所有已读取过的配置段
+func (s *Setting) WatchSettingChange() {
+	s.vp.OnConfigChange(func(in fsnotify.Event) {
+		if err := s.ReloadAllSection(); err != nil {
+			s.reloadFailed(fmt.Errorf("reload %s: %w", in.Name, err))
+		}
+	})
+	s.vp.WatchConfig()
+}
+
+func (s *Setting) reloadFailed(err error) {
+	mu.RLock()
+	onErrors := s.onErrors
+	mu.RUnlock()
+
+	if len(onErrors) == 0 {
+		log.Printf("setting.ReloadAllSection err: %v", err)
+		return
+	}
+	for _, fn := range onErrors {
+		fn(err)
+	}
+}
+
+// 重新读取配置文件和所有配置段, 全部解析成功后才替换原有的配置
+func (s *Setting) ReloadAllSection() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// 配置文件格式错误时viper会保留原有的配置, 这里重新读取一次以便返回错误
+	if err := s.vp.ReadInConfig(); err != nil {
+		return err
+	}
+
+	values := make(map[string]reflect.Value, len(s.sections))
+	for k, v := range s.sections {
+		target := reflect.ValueOf(v).Elem()
+		fresh := reflect.New(target.Type())
+		if err := s.vp.UnmarshalKey(k, fresh.Interface()); err != nil {
+			return err
+		}
+		values[k] = fresh.Elem()
+	}
+
+	for k, v := range s.sections {
+		reflect.ValueOf(v).Elem().Set(values[k])
+	}
+	for _, fn := range s.callbacks {
+		fn()
+	}
+
+	return nil
 }
@@ -0,0 +1,111 @@
+package setting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const validConfig = `Server:
+  RunMode: debug
+  HttpPort: 8000
+  ReadTimeout: 60
+`
+
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile err: %v", err)
+	}
+}
+
+// 不监听配置文件, 由测试直接调用ReloadAllSection
+func newTestSetting(t *testing.T) (*Setting, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeConfig(t, dir, validConfig)
+	vp := viper.New()
+	vp.SetConfigFile(filepath.Join(dir, "config.yaml"))
+	if err := vp.ReadInConfig(); err != nil {
+		t.Fatalf("ReadInConfig err: %v", err)
+	}
+	return &Setting{vp: vp, sections: make(map[string]interface{})}, dir
+}
+
+func TestReloadAllSection(t *testing.T) {
+	s, dir := newTestSetting(t)
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err != nil {
+		t.Fatalf("ReadSection err: %v", err)
+	}
+	changed := 0
+	s.OnChange(func() { changed++ })
+
+	tests := []struct {
+		name        string
+		content     string
+		wantErr     bool
+		wantPort    string
+		wantChanged int
+	}{
+		{"invalid yaml", "Server:\n  HttpPort: [8001\n", true, "8000", 0},
+		{"invalid value", "Server:\n  HttpPort: 8001\n  ReadTimeout: abc\n", true, "8000", 0},
+		{"valid", "Server:\n  HttpPort: 8002\n  ReadTimeout: 30\n", false, "8002", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, dir, tt.content)
+			err := s.ReloadAllSection()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReloadAllSection err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			RLock()
+			port := server.HttpPort
+			RUnlock()
+			if port != tt.wantPort {
+				t.Errorf("HttpPort = %q, want %q", port, tt.wantPort)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("OnChange called %d times, want %d", changed, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestWatchSettingChangeError(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, validConfig)
+	s, err := NewSetting(dir)
+	if err != nil {
+		t.Fatalf("NewSetting err: %v", err)
+	}
+	var server *ServerSettingS
+	if err := s.ReadSection("Server", &server); err != nil {
+		t.Fatalf("ReadSection err: %v", err)
+	}
+	errs := make(chan error, 10)
+	s.OnError(func(err error) { errs <- err })
+
+	writeConfig(t, dir, "Server:\n  HttpPort: [8001\n")
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("OnError called with nil error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError not called after writing an invalid config")
+	}
+
+	RLock()
+	port := server.HttpPort
+	RUnlock()
+	if port != "8000" {
+		t.Errorf("HttpPort = %q after a failed reload, want the previous 8000", port)
+	}
+}
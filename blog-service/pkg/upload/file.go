@@ -0,0 +1,141 @@
+package upload
+
+import (
+	"blog-service/global"
+	"blog-service/pkg/setting"
+	"blog-service/pkg/util"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"strings"
+)
+
+type FileType int
+
+const (
+	TypeImage FileType = iota + 1
+	TypeDoc
+)
+
+// 上传请求中文件以外的表单字段和multipart分隔符预留的大小
+const multipartOverhead = 1 << 20
+
+// 对原始文件名进行MD5编码, 避免直接暴露原始文件名
+// 再加上随机后缀, 避免同名文件互相覆盖
+func GetFileName(name string) (string, error) {
+	ext := GetFileExt(name)
+	fileName := strings.TrimSuffix(name, ext)
+	fileName = util.EncodeMD5(fileName)
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+
+	return fileName + "_" + hex.EncodeToString(suffix) + ext, nil
+}
+
+func GetFileExt(name string) string {
+	return path.Ext(name)
+}
+
+func GetSavePath() string {
+	return appSetting().UploadSavePath
+}
+
+func GetServerUrl() string {
+	return appSetting().UploadServerUrl
+}
+
+// 检查保存目录是否存在
+func CheckSavePath(dst string) bool {
+	_, err := os.Stat(dst)
+	return os.IsNotExist(err)
+}
+
+// 检查文件后缀是否在允许的范围内
+func CheckContainExt(t FileType, name string) bool {
+	ext := strings.ToUpper(GetFileExt(name))
+	for _, allowExt := range allowExts(t) {
+		if strings.ToUpper(allowExt) == ext {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 检查文件大小是否超出限制, 配置中的单位为MB
+func CheckMaxSize(t FileType, size int64) bool {
+	return size > int64(maxSize(t))*1024*1024
+}
+
+// 上传请求体的大小上限, 解析表单前还不知道文件类型, 按各类型中最大的限制计算
+func MaxRequestSize() int64 {
+	max := maxSize(TypeImage)
+	if docMax := maxSize(TypeDoc); docMax > max {
+		max = docMax
+	}
+	return int64(max)*1024*1024 + multipartOverhead
+}
+
+// 检查文件权限是否足够
+func CheckPermission(dst string) bool {
+	_, err := os.Stat(dst)
+	return os.IsPermission(err)
+}
+
+func CreateSavePath(dst string, perm os.FileMode) error {
+	return os.MkdirAll(dst, perm)
+}
+
+func SaveFile(file *multipart.FileHeader, dst string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// 文件已存在时返回错误, 不覆盖已上传的文件
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+func allowExts(t FileType) []string {
+	switch t {
+	case TypeImage:
+		return appSetting().UploadImageAllowExts
+	case TypeDoc:
+		return appSetting().UploadDocAllowExts
+	}
+
+	return nil
+}
+
+func maxSize(t FileType) int {
+	switch t {
+	case TypeImage:
+		return appSetting().UploadImageMaxSize
+	case TypeDoc:
+		return appSetting().UploadDocMaxSize
+	}
+
+	return 0
+}
+
+// 配置热更新时会整体替换global.AppSetting, 持有读锁获取当前的配置
+func appSetting() *setting.AppSettingS {
+	setting.RLock()
+	defer setting.RUnlock()
+
+	return global.AppSetting
+}
@@ -0,0 +1,106 @@
+package upload
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"blog-service/global"
+	"blog-service/pkg/setting"
+	"blog-service/pkg/util"
+)
+
+func TestGetFileName(t *testing.T) {
+	first, err := GetFileName("avatar.png")
+	if err != nil {
+		t.Fatalf("GetFileName err: %v", err)
+	}
+	second, err := GetFileName("avatar.png")
+	if err != nil {
+		t.Fatalf("GetFileName err: %v", err)
+	}
+
+	if first == second {
+		t.Errorf("GetFileName returned %q twice for the same name", first)
+	}
+	for _, name := range []string{first, second} {
+		if !strings.HasPrefix(name, util.EncodeMD5("avatar")+"_") {
+			t.Errorf("GetFileName = %q, want prefix MD5 of the original name", name)
+		}
+		if GetFileExt(name) != ".png" {
+			t.Errorf("GetFileExt(%q) = %q, want .png", name, GetFileExt(name))
+		}
+		if strings.Contains(name, "avatar") {
+			t.Errorf("GetFileName = %q exposes the original name", name)
+		}
+	}
+}
+
+func newFileHeader(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatalf("CreateFormFile err: %v", err)
+	}
+	part.Write(content)
+	w.Close()
+
+	req := httptest.NewRequest("POST", "/upload/file", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		t.Fatalf("ParseMultipartForm err: %v", err)
+	}
+	return req.MultipartForm.File["file"][0]
+}
+
+func TestSaveFileDoesNotOverwrite(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "avatar.png")
+
+	if err := SaveFile(newFileHeader(t, "avatar.png", []byte("first")), dst); err != nil {
+		t.Fatalf("SaveFile err: %v", err)
+	}
+	if err := SaveFile(newFileHeader(t, "avatar.png", []byte("second")), dst); err == nil {
+		t.Fatalf("SaveFile to an existing file err = nil, want error")
+	}
+
+	content, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile err: %v", err)
+	}
+	if string(content) != "first" {
+		t.Errorf("file content = %q, want %q", content, "first")
+	}
+}
+
+func TestCheckMaxSize(t *testing.T) {
+	as := global.AppSetting
+	global.AppSetting = &setting.AppSettingS{UploadImageMaxSize: 1, UploadDocMaxSize: 2}
+	t.Cleanup(func() { global.AppSetting = as })
+
+	tests := []struct {
+		fileType FileType
+		size     int64
+		want     bool
+	}{
+		{TypeImage, 1 << 20, false},
+		{TypeImage, 1<<20 + 1, true},
+		{TypeDoc, 2 << 20, false},
+		{TypeDoc, 2<<20 + 1, true},
+	}
+	for _, tt := range tests {
+		if got := CheckMaxSize(tt.fileType, tt.size); got != tt.want {
+			t.Errorf("CheckMaxSize(%d, %d) = %v, want %v", tt.fileType, tt.size, got, tt.want)
+		}
+	}
+
+	if got, want := MaxRequestSize(), int64(2<<20+multipartOverhead); got != want {
+		t.Errorf("MaxRequestSize = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+package util
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// 对字符串进行MD5编码
+func EncodeMD5(value string) string {
+	m := md5.New()
+	m.Write([]byte(value))
+
+	return hex.EncodeToString(m.Sum(nil))
+}
@@ -0,0 +1,13 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// 对字符串进行SHA-256编码
+func EncodeSHA256(value string) string {
+	m := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(m[:])
+}
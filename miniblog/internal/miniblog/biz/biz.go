@@ -4,6 +4,7 @@ import (
 	"github.com/Forest-211/miniblog/internal/miniblog/biz/post"
 	"github.com/Forest-211/miniblog/internal/miniblog/biz/user"
 	"github.com/Forest-211/miniblog/internal/miniblog/store"
+	"github.com/Forest-211/miniblog/pkg/denylist"
 )
 
 // IBiz 定义了 Biz 层需要实现的方法.
@@ -18,19 +19,20 @@ var _ IBiz = (*biz)(nil)
 // biz 是 IBiz 的一个具体实现.
 type biz struct {
 	ds store.IStore
+	dl denylist.Denylist
 }
 
 // 确保 biz 实现了 IBiz 接口.
 var _ IBiz = (*biz)(nil)
 
 // NewBiz 创建一个 IBiz 类型的实例.
-func NewBiz(ds store.IStore) *biz {
-	return &biz{ds: ds}
+func NewBiz(ds store.IStore, dl denylist.Denylist) *biz {
+	return &biz{ds: ds, dl: dl}
 }
 
 // Users 返回一个实现了 UserBiz 接口的实例.
 func (b *biz) Users() user.UserBiz {
-	return user.New(b.ds)
+	return user.New(b.ds, b.dl)
 }
 
 func (b *biz) Posts() post.PostBiz {
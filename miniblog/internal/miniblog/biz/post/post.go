@@ -2,26 +2,37 @@ package post
 
 import (
 	"context"
-	"fmt"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/copier"
+	"gorm.io/gorm"
 
 	"github.com/Forest-211/miniblog/internal/miniblog/store"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
 	"github.com/Forest-211/miniblog/internal/pkg/model"
 	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
-	"github.com/google/uuid"
-	"github.com/jinzhu/copier"
 )
 
+// PostBiz 定义了 post 模块在 biz 层所实现的方法.
+// 所有方法都只操作 known.XUsernameKey 对应用户自己的博客.
 type PostBiz interface {
-	Create(ctx context.Context, r *v1.CreatePostRequest) error
-	Get(ctx context.Context, r *v1.PostByIDRequest) (*model.PostM, error)
-	List(ctx context.Context, r *v1.ListPostRequest) ([]*model.PostM, error)
+	Create(ctx context.Context, r *v1.CreatePostRequest) (*v1.CreatePostResponse, error)
+	Update(ctx context.Context, postID string, r *v1.UpdatePostRequest) error
+	Delete(ctx context.Context, postID string) error
+	DeleteCollection(ctx context.Context, postIDs []string) error
+	Get(ctx context.Context, postID string) (*v1.GetPostResponse, error)
+	List(ctx context.Context, r *v1.ListPostRequest) (*v1.ListPostResponse, error)
 }
 
+// PostBiz 接口的实现.
 type postBiz struct {
 	ds store.IStore
 }
 
+// 确保 postBiz 实现了 PostBiz 接口.
 var _ PostBiz = (*postBiz)(nil)
 
 // New 创建一个实现了 PostBiz 接口的实例.
@@ -29,30 +40,96 @@ func New(ds store.IStore) PostBiz {
 	return &postBiz{ds: ds}
 }
 
-func (p *postBiz) Create(ctx context.Context, r *v1.CreatePostRequest) error {
+// username 从上下文中取出请求的所有者.
+func username(ctx context.Context) string {
+	name, _ := ctx.Value(known.XUsernameKey).(string)
+
+	return name
+}
+
+// Create 是 PostBiz 接口中 `Create` 方法的实现.
+func (b *postBiz) Create(ctx context.Context, r *v1.CreatePostRequest) (*v1.CreatePostResponse, error) {
 	var postM model.PostM
 	_ = copier.Copy(&postM, r)
 	postM.PostID = uuid.New().String()
-	postM.Username = ctx.Value(known.XUsernameKey).(string)
-	fmt.Println("postM: ", postM)
-	if err := p.ds.Posts().Create(ctx, &postM); err != nil {
+	postM.Username = username(ctx)
+
+	if err := b.ds.Posts().Create(ctx, &postM); err != nil {
+		return nil, err
+	}
+
+	return &v1.CreatePostResponse{PostID: postM.PostID}, nil
+}
+
+// Update 是 PostBiz 接口中 `Update` 方法的实现.
+func (b *postBiz) Update(ctx context.Context, postID string, r *v1.UpdatePostRequest) error {
+	postM, err := b.ds.Posts().Get(ctx, username(ctx), postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+
 		return err
 	}
-	return nil
+
+	if r.Title != nil {
+		postM.Title = *r.Title
+	}
+
+	if r.Content != nil {
+		postM.Content = *r.Content
+	}
+
+	return b.ds.Posts().Update(ctx, postM)
+}
+
+// Delete 是 PostBiz 接口中 `Delete` 方法的实现.
+func (b *postBiz) Delete(ctx context.Context, postID string) error {
+	return b.ds.Posts().Delete(ctx, username(ctx), []string{postID})
+}
+
+// DeleteCollection 是 PostBiz 接口中 `DeleteCollection` 方法的实现.
+func (b *postBiz) DeleteCollection(ctx context.Context, postIDs []string) error {
+	return b.ds.Posts().Delete(ctx, username(ctx), postIDs)
 }
 
-func (p *postBiz) Get(ctx context.Context, r *v1.PostByIDRequest) (*model.PostM, error) {
-	post, err := p.ds.Posts().Get(ctx, r.ID)
+// Get 是 PostBiz 接口中 `Get` 方法的实现.
+func (b *postBiz) Get(ctx context.Context, postID string) (*v1.GetPostResponse, error) {
+	post, err := b.ds.Posts().Get(ctx, username(ctx), postID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+
 		return nil, err
 	}
-	return post, nil
+
+	var resp v1.GetPostResponse
+	_ = copier.Copy(&resp, post)
+
+	resp.CreatedAt = post.CreatedAt.Format("2006-01-02 15:04:05")
+	resp.UpdatedAt = post.UpdatedAt.Format("2006-01-02 15:04:05")
+
+	return &resp, nil
 }
 
-func (p *postBiz) List(ctx context.Context, r *v1.ListPostRequest) ([]*model.PostM, error) {
-	posts, err := p.ds.Posts().List(ctx, r.Username)
+// List 是 PostBiz 接口中 `List` 方法的实现.
+func (b *postBiz) List(ctx context.Context, r *v1.ListPostRequest) (*v1.ListPostResponse, error) {
+	count, list, err := b.ds.Posts().List(ctx, username(ctx), r.Offset, r.Limit)
 	if err != nil {
+		log.C(ctx).Errorw("Failed to list posts from storage", "err", err)
 		return nil, err
 	}
-	return posts, nil
+
+	posts := make([]*v1.PostInfo, 0, len(list))
+	for _, item := range list {
+		var post v1.PostInfo
+		_ = copier.Copy(&post, item)
+
+		post.CreatedAt = item.CreatedAt.Format("2006-01-02 15:04:05")
+		post.UpdatedAt = item.UpdatedAt.Format("2006-01-02 15:04:05")
+		posts = append(posts, &post)
+	}
+
+	return &v1.ListPostResponse{TotalCount: count, Posts: posts}, nil
 }
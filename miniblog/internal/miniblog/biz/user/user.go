@@ -4,15 +4,20 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/copier"
 	"gorm.io/gorm"
 
 	"github.com/Forest-211/miniblog/internal/miniblog/store"
 	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
 	"github.com/Forest-211/miniblog/internal/pkg/model"
 	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
 	"github.com/Forest-211/miniblog/pkg/auth"
+	"github.com/Forest-211/miniblog/pkg/denylist"
 	"github.com/Forest-211/miniblog/pkg/token"
 )
 
@@ -22,23 +27,34 @@ type UserBiz interface {
 	Get(ctx context.Context, username string) (*v1.GetUserResponse, error)
 	Login(ctx context.Context, r *v1.LoginRequest) (*v1.LoginResponse, error)
 	ChangePassword(ctx context.Context, username string, r *v1.ChangePasswordRequest) error
+	Update(ctx context.Context, username string, r *v1.UpdateUserRequest) error
+	Delete(ctx context.Context, username string) error
+	List(ctx context.Context, r *v1.ListUserRequest) (*v1.ListUserResponse, error)
+	RefreshToken(ctx context.Context, r *v1.RefreshTokenRequest) (*v1.RefreshTokenResponse, error)
+	Logout(ctx context.Context, claims *token.Claims, r *v1.LogoutRequest) error
 }
 
 // UserBiz 接口的实现.
 type userBiz struct {
 	ds store.IStore
+	dl denylist.Denylist
 }
 
 // 确保 userBiz 实现了 UserBiz 接口.
 var _ UserBiz = (*userBiz)(nil)
 
 // New 创建一个实现了 UserBiz 接口的实例.
-func New(ds store.IStore) *userBiz {
-	return &userBiz{ds: ds}
+func New(ds store.IStore, dl denylist.Denylist) *userBiz {
+	return &userBiz{ds: ds, dl: dl}
 }
 
 // Create 是 UserBiz 接口中 `Create` 方法的实现.
 func (b *userBiz) Create(ctx context.Context, r *v1.CreateUserRequest) error {
+	// 管理员账户只能通过配置创建，否则第一个注册 root 的人会成为管理员
+	if strings.EqualFold(r.Username, known.AdminUsername) {
+		return errno.ErrUsernameReserved
+	}
+
 	var userM model.UserM
 	_ = copier.Copy(&userM, r)
 
@@ -64,15 +80,100 @@ func (b *userBiz) Get(ctx context.Context, username string) (*v1.GetUserResponse
 		return nil, err
 	}
 
+	counts, err := b.ds.Posts().CountByUsernames(ctx, []string{username})
+	if err != nil {
+		return nil, err
+	}
+
 	var resp v1.GetUserResponse
 	_ = copier.Copy(&resp, user)
 
+	resp.PostCount = counts[username]
+
 	resp.CreatedAt = user.CreatedAt.Format("2006-01-02 15:04:05")
 	resp.UpdatedAt = user.UpdatedAt.Format("2006-01-02 15:04:05")
 
 	return &resp, nil
 }
 
+// Update 是 UserBiz 接口中 `Update` 方法的实现.
+func (b *userBiz) Update(ctx context.Context, username string, r *v1.UpdateUserRequest) error {
+	userM, err := b.ds.Users().Get(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+
+		return err
+	}
+
+	if r.Nickname != nil {
+		userM.Nickname = *r.Nickname
+	}
+
+	if r.Email != nil {
+		userM.Email = *r.Email
+	}
+
+	if r.Phone != nil {
+		userM.Phone = *r.Phone
+	}
+
+	return b.ds.Users().Update(ctx, userM)
+}
+
+// Delete 是 UserBiz 接口中 `Delete` 方法的实现，用户的博客会在同一个事务中一起删除.
+func (b *userBiz) Delete(ctx context.Context, username string) error {
+	// 先吊销用户已签发的令牌，删除失败时最多需要用户重新登录，
+	// 而先删除再吊销失败会留下仍然可用的令牌
+	if err := b.dl.RevokeBefore(ctx, username, time.Now(), token.MaxLifetime()); err != nil {
+		return err
+	}
+
+	return b.ds.TX(ctx, func(ds store.IStore) error {
+		if err := ds.Posts().DeleteByUsername(ctx, username); err != nil {
+			return err
+		}
+
+		return ds.Users().Delete(ctx, username)
+	})
+}
+
+// List 是 UserBiz 接口中 `List` 方法的实现.
+func (b *userBiz) List(ctx context.Context, r *v1.ListUserRequest) (*v1.ListUserResponse, error) {
+	count, list, err := b.ds.Users().List(ctx, r.Offset, r.Limit)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to list users from storage", "err", err)
+		return nil, err
+	}
+
+	usernames := make([]string, 0, len(list))
+	for _, item := range list {
+		usernames = append(usernames, item.Username)
+	}
+
+	counts, err := b.ds.Posts().CountByUsernames(ctx, usernames)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to count posts of users", "err", err)
+		return nil, err
+	}
+
+	users := make([]*v1.UserInfo, 0, len(list))
+	for _, item := range list {
+		users = append(users, &v1.UserInfo{
+			Username:  item.Username,
+			Nickname:  item.Nickname,
+			Email:     item.Email,
+			Phone:     item.Phone,
+			PostCount: counts[item.Username],
+			CreatedAt: item.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt: item.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &v1.ListUserResponse{TotalCount: count, Users: users}, nil
+}
+
 // ChangePassword 是 UserBiz 接口中 `ChangePassword` 方法的实现.
 func (b *userBiz) ChangePassword(ctx context.Context, username string, r *v1.ChangePasswordRequest) error {
 	userM, err := b.ds.Users().Get(ctx, username)
@@ -89,7 +190,8 @@ func (b *userBiz) ChangePassword(ctx context.Context, username string, r *v1.Cha
 		return err
 	}
 
-	return nil
+	// 修改密码后，之前签发的所有令牌都不能再使用
+	return b.dl.RevokeBefore(ctx, username, time.Now(), token.MaxLifetime())
 }
 
 // Login 是 UserBiz 接口中 `Login` 方法的实现.
@@ -106,10 +208,76 @@ func (b *userBiz) Login(ctx context.Context, r *v1.LoginRequest) (*v1.LoginRespo
 	}
 
 	// 如果匹配成功，说明登录成功，签发 token 并返回
-	t, err := token.Sign(r.Username)
+	pair, err := token.Sign(r.Username)
+	if err != nil {
+		return nil, errno.ErrSignToken
+	}
+
+	return loginResponse(pair), nil
+}
+
+// RefreshToken 是 UserBiz 接口中 `RefreshToken` 方法的实现.
+// 刷新令牌只能使用一次，换取新的令牌对后旧的刷新令牌会被吊销.
+func (b *userBiz) RefreshToken(ctx context.Context, r *v1.RefreshTokenRequest) (*v1.RefreshTokenResponse, error) {
+	claims, err := token.ParseType(r.RefreshToken, token.TypeRefresh)
+	if err != nil {
+		return nil, errno.ErrTokenInvalid
+	}
+
+	revoked, err := denylist.Revoked(ctx, b.dl, claims.ID, claims.Identity, claims.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errno.ErrTokenRevoked
+	}
+
+	// 用户被删除后不能再刷新令牌
+	if _, err := b.ds.Users().Get(ctx, claims.Identity); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errno.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	// 原子地吊销旧的刷新令牌，同一个刷新令牌的并发请求只有一个能换取新的令牌
+	claimed, err := b.dl.Claim(ctx, claims.ID, claims.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errno.ErrTokenRevoked
+	}
+
+	pair, err := token.Sign(claims.Identity)
 	if err != nil {
 		return nil, errno.ErrSignToken
 	}
 
-	return &v1.LoginResponse{Token: t}, nil
+	return (*v1.RefreshTokenResponse)(loginResponse(pair)), nil
+}
+
+// Logout 是 UserBiz 接口中 `Logout` 方法的实现，claims 为本次请求所用的访问令牌.
+func (b *userBiz) Logout(ctx context.Context, claims *token.Claims, r *v1.LogoutRequest) error {
+	if r.RefreshToken != "" {
+		refresh, err := token.ParseType(r.RefreshToken, token.TypeRefresh)
+		if err != nil || refresh.Identity != claims.Identity {
+			return errno.ErrTokenInvalid
+		}
+
+		if err := b.dl.Add(ctx, refresh.ID, refresh.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	return b.dl.Add(ctx, claims.ID, claims.ExpiresAt)
+}
+
+func loginResponse(pair *token.Pair) *v1.LoginResponse {
+	return &v1.LoginResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt.Format("2006-01-02 15:04:05"),
+	}
 }
@@ -0,0 +1,203 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/store"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/model"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/denylist"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+var (
+	storeOnce sync.Once
+	testStore store.IStore
+)
+
+// newTestBiz 返回使用 SQLite 内存数据库和内存黑名单的 userBiz.
+// store.NewStore 只会初始化一次，因此同一个包中的测试共用一个数据库，各个测试需要使用不同的用户名.
+func newTestBiz(t *testing.T) *userBiz {
+	t.Helper()
+
+	storeOnce.Do(func() {
+		token.Init("Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", known.XUsernameKey, time.Hour, 24*time.Hour)
+
+		db, err := gorm.Open(sqlite.Open("file:biz_user?mode=memory&cache=shared"), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		if err != nil {
+			t.Fatalf("gorm.Open() error = %v", err)
+		}
+		if err := db.AutoMigrate(&model.UserM{}, &model.PostM{}); err != nil {
+			t.Fatalf("AutoMigrate() error = %v", err)
+		}
+		testStore = store.NewStore(db)
+	})
+
+	return New(testStore, denylist.NewMemory())
+}
+
+func createTestUser(t *testing.T, b *userBiz, username string) *v1.LoginResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	err := b.Create(ctx, &v1.CreateUserRequest{
+		Username: username,
+		Password: "miniblog1234",
+		Nickname: username,
+		Email:    username + "@example.com",
+		Phone:    "18110000000",
+	})
+	if err != nil {
+		t.Fatalf("Create(%q) error = %v", username, err)
+	}
+
+	resp, err := b.Login(ctx, &v1.LoginRequest{Username: username, Password: "miniblog1234"})
+	if err != nil {
+		t.Fatalf("Login(%q) error = %v", username, err)
+	}
+
+	return resp
+}
+
+func TestCreateReservedUsername(t *testing.T) {
+	b := newTestBiz(t)
+
+	for _, username := range []string{"root", "Root", "ROOT"} {
+		err := b.Create(context.Background(), &v1.CreateUserRequest{
+			Username: username,
+			Password: "miniblog1234",
+			Nickname: "admin",
+			Email:    "admin@example.com",
+			Phone:    "18110000000",
+		})
+		if err != errno.ErrUsernameReserved {
+			t.Errorf("Create(%q) error = %v, want %v", username, err, errno.ErrUsernameReserved)
+		}
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	b := newTestBiz(t)
+	ctx := context.Background()
+	login := createTestUser(t, b, "refresh")
+
+	resp, err := b.RefreshToken(ctx, &v1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if _, err := token.ParseType(resp.Token, token.TypeAccess); err != nil {
+		t.Errorf("new access token is invalid: %v", err)
+	}
+
+	// 刷新令牌只能使用一次
+	if _, err := b.RefreshToken(ctx, &v1.RefreshTokenRequest{RefreshToken: login.RefreshToken}); err != errno.ErrTokenRevoked {
+		t.Errorf("RefreshToken() with a used token error = %v, want %v", err, errno.ErrTokenRevoked)
+	}
+	if _, err := b.RefreshToken(ctx, &v1.RefreshTokenRequest{RefreshToken: resp.Token}); err != errno.ErrTokenInvalid {
+		t.Errorf("RefreshToken() with an access token error = %v, want %v", err, errno.ErrTokenInvalid)
+	}
+}
+
+func TestRefreshTokenConcurrent(t *testing.T) {
+	b := newTestBiz(t)
+	login := createTestUser(t, b, "concurrent")
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		refreshed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := b.RefreshToken(context.Background(), &v1.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+			if err == nil {
+				mu.Lock()
+				refreshed++
+				mu.Unlock()
+			} else if err != errno.ErrTokenRevoked {
+				t.Errorf("RefreshToken() error = %v, want nil or %v", err, errno.ErrTokenRevoked)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshed != 1 {
+		t.Errorf("%d concurrent RefreshToken() calls succeeded, want 1", refreshed)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	b := newTestBiz(t)
+	ctx := context.Background()
+	login := createTestUser(t, b, "logout")
+
+	claims, err := token.ParseType(login.Token, token.TypeAccess)
+	if err != nil {
+		t.Fatalf("ParseType() error = %v", err)
+	}
+	if err := b.Logout(ctx, claims, &v1.LogoutRequest{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if revoked, _ := denylist.Revoked(ctx, b.dl, claims.ID, claims.Identity, claims.IssuedAt); !revoked {
+		t.Errorf("access token is not revoked after logout")
+	}
+	if _, err := b.RefreshToken(ctx, &v1.RefreshTokenRequest{RefreshToken: login.RefreshToken}); err != errno.ErrTokenRevoked {
+		t.Errorf("RefreshToken() after logout error = %v, want %v", err, errno.ErrTokenRevoked)
+	}
+}
+
+func TestRevokeOnPasswordChangeAndDelete(t *testing.T) {
+	tests := []struct {
+		username string
+		action   func(b *userBiz, username string) error
+	}{
+		{"changepwd", func(b *userBiz, username string) error {
+			return b.ChangePassword(context.Background(), username, &v1.ChangePasswordRequest{
+				OldPassword: "miniblog1234",
+				NewPassword: "miniblog5678",
+			})
+		}},
+		{"deleted", func(b *userBiz, username string) error {
+			return b.Delete(context.Background(), username)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			b := newTestBiz(t)
+			ctx := context.Background()
+			login := createTestUser(t, b, tt.username)
+
+			// 令牌的签发时间精确到毫秒，等待到下一毫秒再吊销
+			time.Sleep(2 * time.Millisecond)
+			if err := tt.action(b, tt.username); err != nil {
+				t.Fatalf("action error = %v", err)
+			}
+
+			for _, s := range []string{login.Token, login.RefreshToken} {
+				claims, err := token.Parse(s, "Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5")
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				revoked, err := denylist.Revoked(ctx, b.dl, claims.ID, claims.Identity, claims.IssuedAt)
+				if err != nil || !revoked {
+					t.Errorf("%s token revoked = %v, %v, want true", claims.Type, revoked, err)
+				}
+			}
+		})
+	}
+}
@@ -1,15 +1,16 @@
 package post
 
 import (
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+
 	"github.com/Forest-211/miniblog/internal/pkg/core"
 	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
 	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
-	"github.com/asaskevich/govalidator"
-	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
+// Create 创建一篇博客.
 func (ctrl *PostController) Create(c *gin.Context) {
 	// 写入日志
 	log.C(c).Infow("Create post function called")
@@ -31,14 +32,11 @@ func (ctrl *PostController) Create(c *gin.Context) {
 	}
 
 	// 逻辑处理
-	if err := ctrl.b.Posts().Create(c, &r); err != nil {
+	resp, err := ctrl.b.Posts().Create(c, &r)
+	if err != nil {
 		core.WriteResponse(c, err, nil)
 		return
 	}
-	core.WriteResponse(c, nil, map[string]any{
-		"title":   r.Title,
-		"content": r.Content,
-		"author":  "Forest",
-		"id":      uuid.New().String(),
-	})
+
+	core.WriteResponse(c, nil, resp)
 }
@@ -1,15 +1,20 @@
 package post
 
 import (
+	"github.com/gin-gonic/gin"
+
 	"github.com/Forest-211/miniblog/internal/pkg/core"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
-	"github.com/gin-gonic/gin"
 )
 
+// Delete 删除当前用户的指定博客.
 func (ctrl *PostController) Delete(c *gin.Context) {
-	log.C(c).Infow("Create post function called")
-	core.WriteResponse(c, nil, map[string]any{
-		"title":   "delete test",
-		"content": "Let's build a blog.",
-	})
+	log.C(c).Infow("Delete post function called")
+
+	if err := ctrl.b.Posts().Delete(c, c.Param("postID")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
 }
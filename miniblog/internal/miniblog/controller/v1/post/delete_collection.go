@@ -0,0 +1,21 @@
+package post
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+)
+
+// DeleteCollection 批量删除当前用户的博客，博客 ID 通过多个 `postID` 查询参数传入.
+func (ctrl *PostController) DeleteCollection(c *gin.Context) {
+	log.C(c).Infow("Batch delete post function called")
+
+	postIDs := c.QueryArray("postID")
+	if err := ctrl.b.Posts().DeleteCollection(c, postIDs); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
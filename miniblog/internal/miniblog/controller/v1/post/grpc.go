@@ -0,0 +1,116 @@
+package post
+
+import (
+	"context"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/jinzhu/copier"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	pb "github.com/Forest-211/miniblog/pkg/proto/miniblog/v1"
+)
+
+// PostServer 实现了 gRPC 的 PostService，与 PostController 共用 biz 层.
+type PostServer struct {
+	pb.UnimplementedPostServiceServer
+
+	b biz.IBiz
+}
+
+// 确保 PostServer 实现了 pb.PostServiceServer 接口.
+var _ pb.PostServiceServer = (*PostServer)(nil)
+
+// NewServer 创建一个 PostServer.
+func NewServer(b biz.IBiz) *PostServer {
+	return &PostServer{b: b}
+}
+
+// CreatePost 创建一篇博客，作用与 PostController.Create 相同.
+func (s *PostServer) CreatePost(ctx context.Context, req *pb.CreatePostRequest) (*pb.CreatePostResponse, error) {
+	log.C(ctx).Infow("Create post function called")
+
+	r := v1.CreatePostRequest{Title: req.Title, Content: req.Content}
+	if _, err := govalidator.ValidateStruct(&r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	resp, err := s.b.Posts().Create(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreatePostResponse{PostId: resp.PostID}, nil
+}
+
+// GetPost 获取当前用户的指定博客，作用与 PostController.Get 相同.
+func (s *PostServer) GetPost(ctx context.Context, req *pb.GetPostRequest) (*pb.PostInfo, error) {
+	log.C(ctx).Infow("Get post function called")
+
+	post, err := s.b.Posts().Get(ctx, req.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	return postInfo((*v1.PostInfo)(post)), nil
+}
+
+// UpdatePost 更新当前用户的指定博客，作用与 PostController.Update 相同.
+func (s *PostServer) UpdatePost(ctx context.Context, req *pb.UpdatePostRequest) (*pb.UpdatePostResponse, error) {
+	log.C(ctx).Infow("Update post function called")
+
+	r := v1.UpdatePostRequest{Title: req.Title, Content: req.Content}
+	if err := validateUpdate(&r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	if err := s.b.Posts().Update(ctx, req.PostId, &r); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdatePostResponse{}, nil
+}
+
+// DeletePost 删除当前用户的一篇或多篇博客，作用与 PostController.DeleteCollection 相同.
+func (s *PostServer) DeletePost(ctx context.Context, req *pb.DeletePostRequest) (*pb.DeletePostResponse, error) {
+	log.C(ctx).Infow("Delete post function called")
+
+	if err := s.b.Posts().DeleteCollection(ctx, req.PostIds); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeletePostResponse{}, nil
+}
+
+// ListPost 分页返回当前用户的博客列表，作用与 PostController.List 相同.
+func (s *PostServer) ListPost(ctx context.Context, req *pb.ListPostRequest) (*pb.ListPostResponse, error) {
+	log.C(ctx).Infow("List post function called")
+
+	r := v1.ListPostRequest{Offset: int(req.Offset), Limit: int(req.Limit)}
+	if _, err := govalidator.ValidateStruct(&r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	resp, err := s.b.Posts().List(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*pb.PostInfo, 0, len(resp.Posts))
+	for _, item := range resp.Posts {
+		posts = append(posts, postInfo(item))
+	}
+
+	return &pb.ListPostResponse{TotalCount: resp.TotalCount, Posts: posts}, nil
+}
+
+func postInfo(post *v1.PostInfo) *pb.PostInfo {
+	var info pb.PostInfo
+	_ = copier.Copy(&info, post)
+	// 字段名的大小写不同，copier 无法自动复制
+	info.PostId = post.PostID
+
+	return &info
+}
@@ -1,14 +1,16 @@
 package post
 
 import (
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+
 	"github.com/Forest-211/miniblog/internal/pkg/core"
 	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
 	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
-	"github.com/asaskevich/govalidator"
-	"github.com/gin-gonic/gin"
 )
 
+// List 分页返回当前用户的博客列表.
 func (ctrl *PostController) List(c *gin.Context) {
 	// 写入日志
 	log.C(c).Infow("List post function called")
@@ -16,25 +18,24 @@ func (ctrl *PostController) List(c *gin.Context) {
 	// 获取参数
 	var r v1.ListPostRequest
 
-	// 接收 HTTP 请求中的 JSON 数据、并进行参数解析
-	if err := c.ShouldBindJSON(&r); err != nil {
-		log.C(c).Errorw("ShouldBindJSON error", "err", err)
+	// 解析 URL 中的查询参数
+	if err := c.ShouldBindQuery(&r); err != nil {
+		log.C(c).Errorw("ShouldBindQuery error", "err", err)
 		core.WriteResponse(c, errno.ErrBind, nil)
 		return
 	}
 
-	// 参数校验
 	if _, err := govalidator.ValidateStruct(&r); err != nil {
 		core.WriteResponse(c, errno.ErrInvalidParameter.SetMessage(err.Error()), nil)
 		return
 	}
 
 	// 逻辑处理
-	posts, err := ctrl.b.Posts().List(c, &r)
+	resp, err := ctrl.b.Posts().List(c, &r)
 	if err != nil {
 		core.WriteResponse(c, err, nil)
 		return
 	}
 
-	core.WriteResponse(c, nil, posts)
+	core.WriteResponse(c, nil, resp)
 }
@@ -1,15 +1,34 @@
 package post
 
 import (
+	"github.com/gin-gonic/gin"
+
 	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
-	"github.com/gin-gonic/gin"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
 )
 
+// Update 更新当前用户的指定博客.
 func (ctrl *PostController) Update(c *gin.Context) {
 	log.C(c).Infow("Update post function called")
-	core.WriteResponse(c, nil, map[string]any{
-		"title":   "update test",
-		"content": "Let's build a blog.",
-	})
+
+	var r v1.UpdatePostRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		log.C(c).Errorw("ShouldBindJSON error", "err", err)
+		core.WriteResponse(c, errno.ErrBind, nil)
+		return
+	}
+
+	if err := validateUpdate(&r); err != nil {
+		core.WriteResponse(c, errno.ErrInvalidParameter.SetMessage(err.Error()), nil)
+		return
+	}
+
+	if err := ctrl.b.Posts().Update(c, c.Param("postID"), &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
 }
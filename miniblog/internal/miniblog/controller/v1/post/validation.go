@@ -0,0 +1,23 @@
+package post
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+)
+
+// validateUpdate 校验 UpdatePostRequest 中传入的字段.
+// govalidator 不会校验指针字段上的 valid 标签，因此需要显式校验解引用后的值.
+func validateUpdate(r *v1.UpdatePostRequest) error {
+	if r.Title != nil && !govalidator.StringLength(*r.Title, "1", "255") {
+		return fmt.Errorf("title: %q does not validate as stringlength(1|255)", *r.Title)
+	}
+
+	if r.Content != nil && !govalidator.StringLength(*r.Content, "1", "4294967295") {
+		return fmt.Errorf("content: %q does not validate as stringlength(1|4294967295)", *r.Content)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package post
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asaskevich/govalidator"
+
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		r       v1.UpdatePostRequest
+		wantErr bool
+	}{
+		{"nothing", v1.UpdatePostRequest{}, false},
+		{"title", v1.UpdatePostRequest{Title: str("miniblog")}, false},
+		{"content", v1.UpdatePostRequest{Content: str("hello")}, false},
+		{"empty title", v1.UpdatePostRequest{Title: str("")}, true},
+		{"long title", v1.UpdatePostRequest{Title: str(strings.Repeat("a", 256))}, true},
+		{"empty content", v1.UpdatePostRequest{Title: str("miniblog"), Content: str("")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateUpdate(&tt.r); (err != nil) != tt.wantErr {
+				t.Errorf("validateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateListPostRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       v1.ListPostRequest
+		wantErr bool
+	}{
+		{"default", v1.ListPostRequest{}, false},
+		{"max limit", v1.ListPostRequest{Offset: 10, Limit: 100}, false},
+		{"negative offset", v1.ListPostRequest{Offset: -1}, true},
+		{"negative limit", v1.ListPostRequest{Limit: -1}, true},
+		{"limit too large", v1.ListPostRequest{Limit: 101}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := govalidator.ValidateStruct(&tt.r); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStruct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
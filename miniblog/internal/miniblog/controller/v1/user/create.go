@@ -1,8 +1,6 @@
 package user
 
 import (
-	"fmt"
-
 	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
 
@@ -12,8 +10,6 @@ import (
 	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
 )
 
-const defaultMethods = "(GET)|(POST)|(PUT)|(DELETE)"
-
 func (ctrl *UserController) Create(c *gin.Context) {
 	// 写入日志
 	log.C(c).Infow("Create user function called")
@@ -35,13 +31,7 @@ func (ctrl *UserController) Create(c *gin.Context) {
 	}
 
 	// 逻辑处理
-	if err := ctrl.b.Users().Create(c, &r); err != nil {
-		core.WriteResponse(c, err, nil)
-		return
-	}
-
-	if _, err := ctrl.a.AddNamedPolicy("p", r.Username, "/v1/users/"+r.Username, defaultMethods); err != nil {
-		fmt.Println("error: ", err)
+	if err := createUser(c, ctrl.b, ctrl.a, &r); err != nil {
 		core.WriteResponse(c, err, nil)
 		return
 	}
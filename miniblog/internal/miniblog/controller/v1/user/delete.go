@@ -4,10 +4,25 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
 )
 
-func (*UserController) Delete(c *gin.Context) {
-	core.WriteResponse(c, nil, map[string]any{
-		"username": "test delete user",
-	})
+func (ctrl *UserController) Delete(c *gin.Context) {
+	log.C(c).Infow("Delete user function called")
+
+	username := c.Param("name")
+	if err := ctrl.b.Users().Delete(c, username); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	if err := removeUserPolicy(ctrl.a, username); err != nil {
+		log.C(c).Errorw("Failed to remove user policy", "username", username, "err", err)
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
 }
@@ -0,0 +1,209 @@
+package user
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/jinzhu/copier"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/interceptor"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/auth"
+	pb "github.com/Forest-211/miniblog/pkg/proto/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+// UserServer 实现了 gRPC 的 UserService，与 UserController 共用 biz 层和授权规则.
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+
+	a *auth.Authz
+	b biz.IBiz
+}
+
+// 确保 UserServer 实现了 pb.UserServiceServer 接口.
+var _ pb.UserServiceServer = (*UserServer)(nil)
+
+// NewServer 创建一个 UserServer.
+func NewServer(b biz.IBiz, a *auth.Authz) *UserServer {
+	return &UserServer{a: a, b: b}
+}
+
+// PublicMethods 返回不需要认证的 gRPC 方法，与 HTTP 中不经过 Authn 中间件的接口对应.
+func PublicMethods() []string {
+	return []string{
+		pb.UserService_CreateUser_FullMethodName,
+		pb.UserService_Login_FullMethodName,
+		pb.UserService_RefreshToken_FullMethodName,
+		pb.UserService_ChangePassword_FullMethodName,
+	}
+}
+
+// Resources 返回需要授权的 gRPC 方法，授权时使用对应 HTTP 接口的路径和方法.
+func Resources() map[string]interceptor.ResourceFunc {
+	return map[string]interceptor.ResourceFunc{
+		pb.UserService_GetUser_FullMethodName: func(req interface{}) (string, string) {
+			return userResource(req.(*pb.GetUserRequest).Username), http.MethodGet
+		},
+		pb.UserService_UpdateUser_FullMethodName: func(req interface{}) (string, string) {
+			return userResource(req.(*pb.UpdateUserRequest).Username), http.MethodPut
+		},
+		pb.UserService_DeleteUser_FullMethodName: func(req interface{}) (string, string) {
+			return userResource(req.(*pb.DeleteUserRequest).Username), http.MethodDelete
+		},
+		pb.UserService_ListUser_FullMethodName: func(req interface{}) (string, string) {
+			return "/v1/users", http.MethodGet
+		},
+	}
+}
+
+// CreateUser 创建用户，作用与 UserController.Create 相同.
+func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	log.C(ctx).Infow("CreateUser function called")
+
+	var r v1.CreateUserRequest
+	_ = copier.Copy(&r, req)
+
+	if _, err := govalidator.ValidateStruct(&r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	if err := createUser(ctx, s.b, s.a, &r); err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateUserResponse{}, nil
+}
+
+// Login 登录 miniblog 并返回令牌，作用与 UserController.Login 相同.
+func (s *UserServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	log.C(ctx).Infow("Login function called")
+
+	resp, err := s.b.Users().Login(ctx, &v1.LoginRequest{Username: req.Username, Password: req.Password})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{Token: resp.Token, RefreshToken: resp.RefreshToken, ExpiresAt: resp.ExpiresAt}, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌，作用与 UserController.RefreshToken 相同.
+func (s *UserServer) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.LoginResponse, error) {
+	log.C(ctx).Infow("Refresh token function called")
+
+	r := v1.RefreshTokenRequest{RefreshToken: req.RefreshToken}
+	if _, err := govalidator.ValidateStruct(r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	resp, err := s.b.Users().RefreshToken(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{Token: resp.Token, RefreshToken: resp.RefreshToken, ExpiresAt: resp.ExpiresAt}, nil
+}
+
+// Logout 吊销本次请求所用的访问令牌，作用与 UserController.Logout 相同.
+func (s *UserServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	log.C(ctx).Infow("Logout function called")
+
+	claims, _ := ctx.Value(known.XTokenClaimsKey).(*token.Claims)
+	if err := s.b.Users().Logout(ctx, claims, &v1.LogoutRequest{RefreshToken: req.RefreshToken}); err != nil {
+		return nil, err
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+// ChangePassword 修改用户密码，作用与 UserController.ChangePassword 相同.
+func (s *UserServer) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	log.C(ctx).Infow("Change password function called")
+
+	r := v1.ChangePasswordRequest{OldPassword: req.OldPassword, NewPassword: req.NewPassword}
+	if _, err := govalidator.ValidateStruct(r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	if err := s.b.Users().ChangePassword(ctx, req.Username, &r); err != nil {
+		return nil, err
+	}
+
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+// GetUser 获取用户详情，作用与 UserController.Detail 相同.
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.UserInfo, error) {
+	log.C(ctx).Infow("Get user function called")
+
+	user, err := s.b.Users().Get(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pb.UserInfo
+	_ = copier.Copy(&resp, user)
+
+	return &resp, nil
+}
+
+// UpdateUser 更新用户信息，作用与 UserController.Update 相同.
+func (s *UserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	log.C(ctx).Infow("Update user function called")
+
+	r := v1.UpdateUserRequest{Nickname: req.Nickname, Email: req.Email, Phone: req.Phone}
+	if err := validateUpdate(&r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	if err := s.b.Users().Update(ctx, req.Username, &r); err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateUserResponse{}, nil
+}
+
+// DeleteUser 删除用户及其博客，作用与 UserController.Delete 相同.
+func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	log.C(ctx).Infow("Delete user function called")
+
+	if err := s.b.Users().Delete(ctx, req.Username); err != nil {
+		return nil, err
+	}
+
+	if err := removeUserPolicy(s.a, req.Username); err != nil {
+		log.C(ctx).Errorw("Failed to remove user policy", "username", req.Username, "err", err)
+		return nil, err
+	}
+
+	return &pb.DeleteUserResponse{}, nil
+}
+
+// ListUser 分页返回用户列表，作用与 UserController.List 相同.
+func (s *UserServer) ListUser(ctx context.Context, req *pb.ListUserRequest) (*pb.ListUserResponse, error) {
+	log.C(ctx).Infow("List user function called")
+
+	r := v1.ListUserRequest{Offset: int(req.Offset), Limit: int(req.Limit)}
+	if _, err := govalidator.ValidateStruct(r); err != nil {
+		return nil, errno.ErrInvalidParameter.SetMessage(err.Error())
+	}
+
+	resp, err := s.b.Users().List(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*pb.UserInfo, 0, len(resp.Users))
+	for _, item := range resp.Users {
+		var user pb.UserInfo
+		_ = copier.Copy(&user, item)
+		users = append(users, &user)
+	}
+
+	return &pb.ListUserResponse{TotalCount: resp.TotalCount, Users: users}, nil
+}
@@ -1,15 +1,37 @@
 package user
 
 import (
+	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
 
 	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
 )
 
-func (*UserController) List(c *gin.Context) {
+func (ctrl *UserController) List(c *gin.Context) {
 	log.C(c).Infow("List user function called")
-	core.WriteResponse(c, nil, map[string]interface{}{
-		"username": "test list user",
-	})
+
+	var r v1.ListUserRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errno.ErrBind, nil)
+
+		return
+	}
+
+	if _, err := govalidator.ValidateStruct(r); err != nil {
+		core.WriteResponse(c, errno.ErrInvalidParameter.SetMessage(err.Error()), nil)
+
+		return
+	}
+
+	resp, err := ctrl.b.Users().List(c, &r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, resp)
 }
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+// 退出登录，吊销本次请求所用的访问令牌，请求体中带有刷新令牌时一并吊销.
+func (ctrl *UserController) Logout(c *gin.Context) {
+	log.C(c).Infow("Logout function called")
+
+	// 请求体是可选的
+	var r v1.LogoutRequest
+	if err := c.ShouldBindJSON(&r); err != nil && !errors.Is(err, io.EOF) {
+		core.WriteResponse(c, errno.ErrBind, nil)
+
+		return
+	}
+
+	claims, _ := c.MustGet(known.XTokenClaimsKey).(*token.Claims)
+	if err := ctrl.b.Users().Logout(c, claims, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
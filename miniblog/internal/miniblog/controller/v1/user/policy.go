@@ -0,0 +1,60 @@
+package user
+
+import (
+	"context"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/auth"
+)
+
+const defaultMethods = "(GET)|(POST)|(PUT)|(DELETE)"
+
+// AddAdminPolicy 授予管理员管理所有用户的权限，包括获取用户列表.
+func AddAdminPolicy(a *auth.Authz) error {
+	_, err := a.AddNamedPolicy("p", known.AdminUsername, "/v1/users*", defaultMethods)
+
+	return err
+}
+
+// createUser 先授予用户权限再创建用户，创建失败时撤销本次添加的权限，
+// 避免用户创建成功但没有权限管理自己的资源.
+func createUser(ctx context.Context, b biz.IBiz, a *auth.Authz, r *v1.CreateUserRequest) error {
+	added, err := addUserPolicy(a, r.Username)
+	if err != nil {
+		log.C(ctx).Errorw("Failed to add user policy", "username", r.Username, "err", err)
+
+		return err
+	}
+
+	if err := b.Users().Create(ctx, r); err != nil {
+		// 权限已经存在时属于同名的已有用户，不能撤销
+		if added {
+			if err := removeUserPolicy(a, r.Username); err != nil {
+				log.C(ctx).Errorw("Failed to remove user policy", "username", r.Username, "err", err)
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// addUserPolicy 授予用户管理自己的 `/v1/users/{name}` 资源的权限，权限已经存在时返回 false.
+func addUserPolicy(a *auth.Authz, username string) (bool, error) {
+	return a.AddNamedPolicy("p", username, userResource(username), defaultMethods)
+}
+
+// removeUserPolicy 移除 addUserPolicy 添加的权限.
+func removeUserPolicy(a *auth.Authz, username string) error {
+	_, err := a.RemoveFilteredNamedPolicy("p", 0, username, userResource(username))
+
+	return err
+}
+
+func userResource(username string) string {
+	return "/v1/users/" + username
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	userbiz "github.com/Forest-211/miniblog/internal/miniblog/biz/user"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+	"github.com/Forest-211/miniblog/pkg/auth"
+)
+
+// fakeBiz 只实现了 Users().Create，用于模拟创建用户成功或失败.
+type fakeBiz struct {
+	biz.IBiz
+	users *fakeUserBiz
+}
+
+func (b *fakeBiz) Users() userbiz.UserBiz {
+	return b.users
+}
+
+type fakeUserBiz struct {
+	userbiz.UserBiz
+	err error
+	// 调用 Create 时用户是否已经有权限
+	authorized bool
+	a          *auth.Authz
+}
+
+func (b *fakeUserBiz) Create(ctx context.Context, r *v1.CreateUserRequest) error {
+	b.authorized, _ = b.a.Authorize(r.Username, userResource(r.Username), "GET")
+
+	return b.err
+}
+
+func newTestAuthz(t *testing.T) *auth.Authz {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	a, err := auth.NewAuthz(db)
+	if err != nil {
+		t.Fatalf("NewAuthz() error = %v", err)
+	}
+	t.Cleanup(a.StopAutoLoadPolicy)
+
+	return a
+}
+
+func TestCreateUser(t *testing.T) {
+	errCreate := errors.New("create failed")
+
+	tests := []struct {
+		name      string
+		existing  bool
+		createErr error
+		wantAllow bool
+	}{
+		{"created", false, nil, true},
+		{"create failed", false, errCreate, false},
+		// 同名用户已存在时保留其原有的权限
+		{"existing user", true, errCreate, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAuthz(t)
+			if tt.existing {
+				if _, err := addUserPolicy(a, "colin"); err != nil {
+					t.Fatalf("addUserPolicy() error = %v", err)
+				}
+			}
+
+			users := &fakeUserBiz{err: tt.createErr, a: a}
+			err := createUser(context.Background(), &fakeBiz{users: users}, a, &v1.CreateUserRequest{Username: "colin"})
+			if !errors.Is(err, tt.createErr) {
+				t.Fatalf("createUser() error = %v, want %v", err, tt.createErr)
+			}
+			if !users.authorized {
+				t.Errorf("policy was not added before the user was created")
+			}
+			if allowed, _ := a.Authorize("colin", "/v1/users/colin", "PUT"); allowed != tt.wantAllow {
+				t.Errorf("Authorize() after createUser = %v, want %v", allowed, tt.wantAllow)
+			}
+		})
+	}
+}
@@ -4,10 +4,32 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
 )
 
-func (*UserController) Update(c *gin.Context) {
-	core.WriteResponse(c, nil, map[string]any{
-		"username": "test update user",
-	})
+func (ctrl *UserController) Update(c *gin.Context) {
+	log.C(c).Infow("Update user function called")
+
+	var r v1.UpdateUserRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errno.ErrBind, nil)
+
+		return
+	}
+
+	if err := validateUpdate(&r); err != nil {
+		core.WriteResponse(c, errno.ErrInvalidParameter.SetMessage(err.Error()), nil)
+
+		return
+	}
+
+	if err := ctrl.b.Users().Update(c, c.Param("name"), &r); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
 }
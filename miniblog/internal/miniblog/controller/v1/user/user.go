@@ -2,7 +2,6 @@ package user
 
 import (
 	"github.com/Forest-211/miniblog/internal/miniblog/biz"
-	"github.com/Forest-211/miniblog/internal/miniblog/store"
 	"github.com/Forest-211/miniblog/pkg/auth"
 )
 
@@ -11,6 +10,6 @@ type UserController struct {
 	b biz.IBiz
 }
 
-func New(ds store.IStore, a *auth.Authz) *UserController {
-	return &UserController{a: a, b: biz.NewBiz(ds)}
+func New(b biz.IBiz, a *auth.Authz) *UserController {
+	return &UserController{a: a, b: b}
 }
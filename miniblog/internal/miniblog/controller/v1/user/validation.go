@@ -0,0 +1,27 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+)
+
+// validateUpdate 校验 UpdateUserRequest 中传入的字段.
+// govalidator 不会校验指针字段上的 valid 标签，因此需要显式校验解引用后的值.
+func validateUpdate(r *v1.UpdateUserRequest) error {
+	if r.Nickname != nil && !govalidator.StringLength(*r.Nickname, "1", "255") {
+		return fmt.Errorf("nickname: %q does not validate as stringlength(1|255)", *r.Nickname)
+	}
+
+	if r.Email != nil && !govalidator.IsEmail(*r.Email) {
+		return fmt.Errorf("email: %q does not validate as email", *r.Email)
+	}
+
+	if r.Phone != nil && !govalidator.StringLength(*r.Phone, "11", "11") {
+		return fmt.Errorf("phone: %q does not validate as stringlength(11|11)", *r.Phone)
+	}
+
+	return nil
+}
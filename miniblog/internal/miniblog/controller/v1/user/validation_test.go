@@ -0,0 +1,55 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/asaskevich/govalidator"
+
+	v1 "github.com/Forest-211/miniblog/pkg/api/miniblog/v1"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		r       v1.UpdateUserRequest
+		wantErr bool
+	}{
+		{"nothing", v1.UpdateUserRequest{}, false},
+		{"all fields", v1.UpdateUserRequest{Nickname: str("colin"), Email: str("colin@example.com"), Phone: str("18110000000")}, false},
+		{"empty nickname", v1.UpdateUserRequest{Nickname: str("")}, true},
+		{"bad email", v1.UpdateUserRequest{Email: str("not-an-email")}, true},
+		{"empty email", v1.UpdateUserRequest{Email: str("")}, true},
+		{"empty phone", v1.UpdateUserRequest{Phone: str("")}, true},
+		{"short phone", v1.UpdateUserRequest{Phone: str("1811000")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateUpdate(&tt.r); (err != nil) != tt.wantErr {
+				t.Errorf("validateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateListUserRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       v1.ListUserRequest
+		wantErr bool
+	}{
+		{"default", v1.ListUserRequest{}, false},
+		{"max limit", v1.ListUserRequest{Offset: 10, Limit: 100}, false},
+		{"negative offset", v1.ListUserRequest{Offset: -1}, true},
+		{"negative limit", v1.ListUserRequest{Limit: -1}, true},
+		{"limit too large", v1.ListUserRequest{Limit: 101}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := govalidator.ValidateStruct(tt.r); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStruct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package miniblog
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	"github.com/Forest-211/miniblog/internal/miniblog/controller/v1/post"
+	"github.com/Forest-211/miniblog/internal/miniblog/controller/v1/user"
+	"github.com/Forest-211/miniblog/internal/pkg/interceptor"
+	"github.com/Forest-211/miniblog/pkg/auth"
+	"github.com/Forest-211/miniblog/pkg/denylist"
+	pb "github.com/Forest-211/miniblog/pkg/proto/miniblog/v1"
+)
+
+// newGRPCServer 创建 gRPC 服务器并注册 miniblog.v1 中的服务，拦截器依次完成与 HTTP 中间件相同的工作.
+func newGRPCServer(b biz.IBiz, authz *auth.Authz, dl denylist.Denylist) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		interceptor.Recovery(),
+		interceptor.RequestID(),
+		interceptor.Status(),
+		interceptor.Authn(dl, user.PublicMethods()...),
+		interceptor.Authz(authz, user.Resources()),
+	))
+
+	pb.RegisterUserServiceServer(srv, user.NewServer(b, authz))
+	pb.RegisterPostServiceServer(srv, post.NewServer(b))
+
+	return srv
+}
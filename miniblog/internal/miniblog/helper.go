@@ -1,12 +1,20 @@
 package miniblog
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/Forest-211/miniblog/internal/miniblog/store"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
+	"github.com/Forest-211/miniblog/internal/pkg/model"
+	"github.com/Forest-211/miniblog/pkg/denylist"
 	"github.com/Forest-211/miniblog/pkg/repository/mysql"
+	"github.com/Forest-211/miniblog/pkg/repository/redis"
 	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 const (
@@ -71,6 +79,10 @@ func logOptions() *log.Options {
 		Level:             viper.GetString("log.level"),
 		Format:            viper.GetString("log.format"),
 		OutputPaths:       viper.GetStringSlice("log.output-paths"),
+		MaxSize:           viper.GetInt("log.max-size"),
+		MaxBackups:        viper.GetInt("log.max-backups"),
+		MaxAge:            viper.GetInt("log.max-age"),
+		Compress:          viper.GetBool("log.compress"),
 	}
 }
 
@@ -96,3 +108,58 @@ func initStore() error {
 
 	return nil
 }
+
+// initDenylist 根据 token.denylist 配置创建吊销令牌的黑名单，可选值为 memory 和 redis.
+func initDenylist() (denylist.Denylist, error) {
+	switch kind := viper.GetString("token.denylist"); kind {
+	case "", "memory":
+		return denylist.NewMemory(), nil
+	case "redis":
+		client, err := redis.NewRedis(&redis.RedisOptions{
+			Addr:     viper.GetString("redis.addr"),
+			Password: viper.GetString("redis.password"),
+			Database: viper.GetInt("redis.database"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return denylist.NewRedis(client, "miniblog:denylist:"), nil
+	default:
+		return nil, fmt.Errorf("unsupported token denylist %q", kind)
+	}
+}
+
+// initAdmin 在管理员账户不存在时，使用 admin 配置创建管理员账户.
+// admin.password 为空时不创建，此时没有用户可以管理其他用户.
+func initAdmin(ds store.IStore) error {
+	password := viper.GetString("admin.password")
+	if password == "" {
+		log.Warnw("admin.password is empty, administrator account is not provisioned")
+		return nil
+	}
+
+	ctx := context.Background()
+	_, err := ds.Users().Get(ctx, known.AdminUsername)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	admin := &model.UserM{
+		Username: known.AdminUsername,
+		Password: password,
+		Nickname: known.AdminUsername,
+		Email:    viper.GetString("admin.email"),
+		Phone:    viper.GetString("admin.phone"),
+	}
+	if err := ds.Users().Create(ctx, admin); err != nil {
+		return err
+	}
+
+	log.Infow("Administrator account created", "username", known.AdminUsername)
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,10 +14,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
 
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
+	"github.com/Forest-211/miniblog/internal/miniblog/controller/v1/user"
+	"github.com/Forest-211/miniblog/internal/miniblog/store"
 	"github.com/Forest-211/miniblog/internal/pkg/known"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
 	"github.com/Forest-211/miniblog/internal/pkg/middleware"
+	"github.com/Forest-211/miniblog/pkg/auth"
 	"github.com/Forest-211/miniblog/pkg/token"
 	"github.com/Forest-211/miniblog/pkg/version/verflag"
 )
@@ -84,8 +90,32 @@ func run() error {
 		return err
 	}
 
-	// 设置 token 包的签发密钥，用于 token 包 token 的签发和解析
-	token.Init(viper.GetString("jwt-secret"), known.XUsernameKey)
+	// 创建管理员账户
+	if err := initAdmin(store.S); err != nil {
+		return err
+	}
+
+	// 设置 token 包的签发密钥和有效期，用于 token 包 token 的签发和解析
+	token.Init(viper.GetString("jwt-secret"), known.XUsernameKey,
+		viper.GetDuration("token.access-expire"), viper.GetDuration("token.refresh-expire"))
+
+	// 初始化吊销令牌的黑名单
+	dl, err := initDenylist()
+	if err != nil {
+		return err
+	}
+
+	// 初始化授权器，HTTP 和 gRPC 服务共用同一个授权器和 biz 层
+	authz, err := auth.NewAuthz(store.S.DB())
+	if err != nil {
+		return err
+	}
+
+	if err := user.AddAdminPolicy(authz); err != nil {
+		return err
+	}
+
+	b := biz.NewBiz(store.S, dl)
 
 	// 设置 Gin 模式
 	gin.SetMode(viper.GetString("runmode"))
@@ -98,7 +128,7 @@ func run() error {
 
 	g.Use(mws...)
 
-	if err := installRouters(g); err != nil {
+	if err := installRouters(g, b, authz, dl); err != nil {
 		return err
 	}
 
@@ -115,6 +145,21 @@ func run() error {
 		}
 	}()
 
+	// 创建并运行 gRPC 服务器
+	lis, err := net.Listen("tcp", viper.GetString("grpc-addr"))
+	if err != nil {
+		return err
+	}
+
+	grpcsrv := newGRPCServer(b, authz, dl)
+	log.Infow("Start to listening the incoming requests on grpc address", "addr", viper.GetString("grpc-addr"))
+	go func() {
+		// GracefulStop 之后 Serve 返回 nil
+		if err := grpcsrv.Serve(lis); err != nil {
+			log.Fatalw(err.Error())
+		}
+	}()
+
 	// 等待中断信号优雅地关闭服务器（10 秒超时)。
 	quit := make(chan os.Signal, 1)
 	// kill 默认会发送 syscall.SIGTERM 信号
@@ -128,13 +173,37 @@ func run() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// 10 秒内优雅关闭服务（将未处理完的请求处理完再关闭服务），超过 10 秒就超时退出
+	if err := shutdown(ctx, httpsrv, grpcsrv); err != nil {
+		return err
+	}
+
+	log.Infow("Server exiting")
+
+	return nil
+}
+
+// shutdown 同时优雅关闭 HTTP 和 gRPC 服务器，ctx 结束时还没有关闭的服务器会被强制关闭.
+func shutdown(ctx context.Context, httpsrv *http.Server, grpcsrv *grpc.Server) error {
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcsrv.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	// 在 ctx 结束前优雅关闭服务（将未处理完的请求处理完再关闭服务），超时后强制关闭
 	if err := httpsrv.Shutdown(ctx); err != nil {
 		log.Errorw("Insecure Server forced to shutdown", "err", err)
+		grpcsrv.Stop()
 		return err
 	}
 
-	log.Infow("Server exiting")
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		log.Errorw("gRPC Server forced to shutdown", "err", ctx.Err())
+		grpcsrv.Stop()
+		return ctx.Err()
+	}
 
 	return nil
 }
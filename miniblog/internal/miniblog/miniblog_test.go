@@ -0,0 +1,192 @@
+package miniblog
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testServers struct {
+	httpsrv    *http.Server
+	grpcsrv    *grpc.Server
+	httpAddr   string
+	grpcAddr   string
+	httpServed chan error
+	grpcServed chan error
+}
+
+// startServers 在随机端口上启动 HTTP 和 gRPC 服务器，gRPC 服务器注册了健康检查服务.
+func startServers(t *testing.T, handler http.Handler) *testServers {
+	t.Helper()
+
+	httpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen err: %v", err)
+	}
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen err: %v", err)
+	}
+
+	s := &testServers{
+		httpsrv:    &http.Server{Handler: handler},
+		grpcsrv:    grpc.NewServer(),
+		httpAddr:   httpLis.Addr().String(),
+		grpcAddr:   grpcLis.Addr().String(),
+		httpServed: make(chan error, 1),
+		grpcServed: make(chan error, 1),
+	}
+	healthpb.RegisterHealthServer(s.grpcsrv, health.NewServer())
+	t.Cleanup(func() {
+		s.httpsrv.Close()
+		s.grpcsrv.Stop()
+	})
+
+	go func() { s.httpServed <- s.httpsrv.Serve(httpLis) }()
+	go func() { s.grpcServed <- s.grpcsrv.Serve(grpcLis) }()
+	return s
+}
+
+func (s *testServers) waitServed(t *testing.T) {
+	t.Helper()
+
+	select {
+	case err := <-s.httpServed:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("HTTP Serve err = %v, want %v", err, http.ErrServerClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("HTTP server is still serving after shutdown")
+	}
+	select {
+	case err := <-s.grpcServed:
+		// 关闭发生在 Serve 开始之前时返回 ErrServerStopped
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Errorf("gRPC Serve err = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("gRPC server is still serving after shutdown")
+	}
+}
+
+// blockingHandler 在 release 关闭前阻塞请求，started 在请求开始处理时关闭.
+func blockingHandler(started, release chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestShutdownIdle(t *testing.T) {
+	s := startServers(t, http.NotFoundHandler())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx, s.httpsrv, s.grpcsrv); err != nil {
+		t.Fatalf("shutdown() err = %v, want nil", err)
+	}
+	s.waitServed(t)
+}
+
+func TestShutdownWaitsForInflightRequest(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	s := startServers(t, blockingHandler(started, release))
+
+	respErr := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + s.httpAddr)
+		if err == nil {
+			resp.Body.Close()
+		}
+		respErr <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- shutdown(ctx, s.httpsrv, s.grpcsrv) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown() returned %v before the in-flight request finished", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-respErr; err != nil {
+		t.Errorf("in-flight request err = %v, want it to complete", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("shutdown() err = %v, want nil", err)
+	}
+	s.waitServed(t)
+}
+
+func TestShutdownForcesHTTP(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	s := startServers(t, blockingHandler(started, release))
+
+	go func() {
+		if resp, err := http.Get("http://" + s.httpAddr); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := shutdown(ctx, s.httpsrv, s.grpcsrv); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("shutdown() err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// HTTP 服务器超时后 gRPC 服务器也要被关闭
+	select {
+	case err := <-s.grpcServed:
+		// 关闭发生在 Serve 开始之前时返回 ErrServerStopped
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			t.Errorf("gRPC Serve err = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("gRPC server is still serving after shutdown")
+	}
+}
+
+func TestShutdownForcesGRPC(t *testing.T) {
+	s := startServers(t, http.NotFoundHandler())
+
+	conn, err := grpc.Dial(s.grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.Dial err: %v", err)
+	}
+	defer conn.Close()
+
+	// Watch 是一直打开的流，GracefulStop 会等待它结束
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Watch err: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("stream.Recv err: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := shutdown(ctx, s.httpsrv, s.grpcsrv); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("shutdown() err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("stream.Recv err = nil after forced shutdown, want error")
+	}
+	s.waitServed(t)
+}
@@ -3,17 +3,18 @@ package miniblog
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/Forest-211/miniblog/internal/miniblog/biz"
 	"github.com/Forest-211/miniblog/internal/miniblog/controller/v1/post"
 	"github.com/Forest-211/miniblog/internal/miniblog/controller/v1/user"
-	"github.com/Forest-211/miniblog/internal/miniblog/store"
 	"github.com/Forest-211/miniblog/internal/pkg/core"
 	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/log"
 	mw "github.com/Forest-211/miniblog/internal/pkg/middleware"
 	"github.com/Forest-211/miniblog/pkg/auth"
+	"github.com/Forest-211/miniblog/pkg/denylist"
 )
 
-func installRouters(g *gin.Engine) error {
+func installRouters(g *gin.Engine, b biz.IBiz, authz *auth.Authz, dl denylist.Denylist) error {
 	// register 404 handler
 	g.NoRoute(func(c *gin.Context) {
 		log.C(c).Infow("404 handler called", "method", c.Request.Method, "path", c.Request.URL.Path)
@@ -26,26 +27,26 @@ func installRouters(g *gin.Engine) error {
 		core.WriteResponse(c, nil, map[string]string{"status": "ok"})
 	})
 
-	authz, err := auth.NewAuthz(store.S.DB())
-	if err != nil {
-		return err
-	}
-
-	uc := user.New(store.S, authz)
-	pc := post.New(store.S, authz)
+	uc := user.New(b, authz)
+	pc := post.New(b, authz)
 
 	// login
 	g.POST("/login", uc.Login)
+	// logout
+	g.POST("/logout", mw.Authn(dl), uc.Logout)
 
 	// 创建 v1 路由组
 	v1 := g.Group("/v1")
 	{
+		// 使用刷新令牌换取新的令牌
+		v1.POST("/refresh-token", uc.RefreshToken)
+
 		// 创建 users 路由组
 		users := v1.Group("/users")
 		{
 			users.POST("/", uc.Create)                            // 创建用户
 			users.PUT(":name/change-password", uc.ChangePassword) // 修改密码
-			users.Use(mw.Authn(), mw.Authz(authz))                // 认证中间件
+			users.Use(mw.Authn(dl), mw.Authz(authz))              // 认证中间件
 			users.GET(":name", uc.Detail)                         // 获取用户
 			users.PUT(":name", uc.Update)                         // 更新用户
 			users.GET("", uc.List)                                // 获取用户
@@ -55,12 +56,13 @@ func installRouters(g *gin.Engine) error {
 		// 创建 posts 路由组
 		posts := v1.Group("/posts")
 		{
-			posts.Use(mw.Authn())
-			posts.POST("/", pc.Create)     // 创建文章
-			posts.PUT(":id", pc.Update)    // 更新文章
-			posts.GET(":id", pc.Get)       // 获取文章
-			posts.GET("", pc.List)         // 获取文章
-			posts.DELETE(":id", pc.Delete) // 删除文章
+			posts.Use(mw.Authn(dl))
+			posts.POST("/", pc.Create)            // 创建文章
+			posts.PUT(":postID", pc.Update)       // 更新文章
+			posts.GET(":postID", pc.Get)          // 获取文章详情
+			posts.GET("", pc.List)                // 获取文章列表
+			posts.DELETE(":postID", pc.Delete)    // 删除文章
+			posts.DELETE("", pc.DeleteCollection) // 批量删除文章
 		}
 	}
 
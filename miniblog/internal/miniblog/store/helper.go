@@ -0,0 +1,23 @@
+package store
+
+const (
+	// defaultLimitValue 是分页查询时未指定 limit 使用的默认值.
+	defaultLimitValue = 20
+
+	// maxLimitValue 是分页查询时一次最多返回的记录数.
+	maxLimitValue = 100
+)
+
+// defaultLimit 设置默认查询记录数，并限制一次查询的最大记录数.
+// limit 为负数时 GORM 会去掉 LIMIT 子句，因此同样使用默认值.
+func defaultLimit(limit int) int {
+	if limit <= 0 {
+		return defaultLimitValue
+	}
+
+	if limit > maxLimitValue {
+		return maxLimitValue
+	}
+
+	return limit
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 
 	"gorm.io/gorm"
 
@@ -9,51 +10,107 @@ import (
 	"github.com/Forest-211/miniblog/internal/pkg/model"
 )
 
+// PostStore 定义了 post 模块在 store 层所实现的方法.
 type PostStore interface {
-	Create(ctx context.Context, user *model.PostM) error
-	Get(ctx context.Context, username string) (*model.PostM, error)
-	Update(ctx context.Context, user *model.PostM) error
-	List(ctx context.Context, username string) ([]*model.PostM, error)
+	Create(ctx context.Context, post *model.PostM) error
+	Get(ctx context.Context, username, postID string) (*model.PostM, error)
+	Update(ctx context.Context, post *model.PostM) error
+	List(ctx context.Context, username string, offset, limit int) (int64, []*model.PostM, error)
+	Delete(ctx context.Context, username string, postIDs []string) error
+	DeleteByUsername(ctx context.Context, username string) error
+	CountByUsernames(ctx context.Context, usernames []string) (map[string]int64, error)
 }
 
-// UserStore 接口的实现.
+// PostStore 接口的实现.
 type posts struct {
 	db *gorm.DB
 }
 
-// 确保 users 实现了 UserStore 接口.
-var _ UserStore = (*users)(nil)
+// 确保 posts 实现了 PostStore 接口.
+var _ PostStore = (*posts)(nil)
 
 func newPosts(db *gorm.DB) *posts {
 	return &posts{db}
 }
 
+// Create 插入一条 post 记录.
 func (p *posts) Create(ctx context.Context, post *model.PostM) error {
 	return p.db.Create(&post).Error
 }
 
-func (p *posts) Get(ctx context.Context, id string) (*model.PostM, error) {
+// Get 根据 postID 查询指定用户的 post 记录.
+func (p *posts) Get(ctx context.Context, username, postID string) (*model.PostM, error) {
 	var post model.PostM
-	if err := p.db.Where("id = ?", id).First(&post).Error; err != nil {
-		log.C(ctx).Errorw("get post <"+id+"> error", "error", err)
+	if err := p.db.Where("username = ? and postID = ?", username, postID).First(&post).Error; err != nil {
+		log.C(ctx).Errorw("get post <"+postID+"> error", "error", err)
 		return nil, err
 	}
+
 	return &post, nil
 }
 
+// Update 更新一条 post 记录.
 func (p *posts) Update(ctx context.Context, post *model.PostM) error {
 	return p.db.Save(post).Error
 }
 
-func (p *posts) Delete(ctx context.Context, username string) error {
+// List 根据 offset 和 limit 返回指定用户的 post 列表及总数.
+func (p *posts) List(ctx context.Context, username string, offset, limit int) (count int64, ret []*model.PostM, err error) {
+	err = p.db.Model(&model.PostM{}).
+		Where("username = ?", username).
+		Count(&count).
+		Offset(offset).
+		Limit(defaultLimit(limit)).
+		Order("id desc").
+		Find(&ret).
+		Error
+
+	return
+}
+
+// Delete 根据 username 和 postID 删除 post 记录，postIDs 为空时不删除任何记录.
+func (p *posts) Delete(ctx context.Context, username string, postIDs []string) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	err := p.db.Where("username = ? and postID in (?)", username, postIDs).Delete(&model.PostM{}).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUsername 删除指定用户的所有 post 记录.
+func (p *posts) DeleteByUsername(ctx context.Context, username string) error {
 	return p.db.Where("username = ?", username).Delete(&model.PostM{}).Error
 }
 
-func (p *posts) List(ctx context.Context, username string) ([]*model.PostM, error) {
-	var posts []*model.PostM
-	if err := p.db.Where("username = ?", username).Find(&posts).Error; err != nil {
-		log.C(ctx).Errorw("list post <"+username+"> error", "error", err)
+// CountByUsernames 统计每个用户的 post 数量，没有 post 的用户不在返回结果中.
+func (p *posts) CountByUsernames(ctx context.Context, usernames []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(usernames))
+	if len(usernames) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Username string
+		Count    int64
+	}
+	err := p.db.Model(&model.PostM{}).
+		Select("username, count(*) as count").
+		Where("username in (?)", usernames).
+		Group("username").
+		Scan(&rows).
+		Error
+	if err != nil {
 		return nil, err
 	}
-	return posts, nil
+
+	for _, row := range rows {
+		counts[row.Username] = row.Count
+	}
+
+	return counts, nil
 }
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"context"
 	"sync"
 
 	"gorm.io/gorm"
@@ -17,6 +18,7 @@ type IStore interface {
 	DB() *gorm.DB
 	Users() UserStore
 	Posts() PostStore
+	TX(ctx context.Context, fn func(ds IStore) error) error
 }
 
 // datastore 是 IStore 的一个具体实现.
@@ -47,6 +49,15 @@ func (ds *datastore) Users() UserStore {
 	return newUsers(ds.db)
 }
 
+// Posts 返回一个实现了 PostStore 接口的实例.
 func (ds *datastore) Posts() PostStore {
 	return newPosts(ds.db)
 }
+
+// TX 在一个数据库事务中执行 fn，fn 返回错误时回滚事务.
+// fn 中只能使用传入的 ds 访问数据库，否则不在事务中.
+func (ds *datastore) TX(ctx context.Context, fn func(ds IStore) error) error {
+	return ds.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&datastore{tx})
+	})
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Forest-211/miniblog/internal/pkg/log"
 	"github.com/Forest-211/miniblog/internal/pkg/model"
@@ -13,6 +14,8 @@ type UserStore interface {
 	Create(ctx context.Context, user *model.UserM) error
 	Get(ctx context.Context, username string) (*model.UserM, error)
 	Update(ctx context.Context, user *model.UserM) error
+	List(ctx context.Context, offset, limit int) (int64, []*model.UserM, error)
+	Delete(ctx context.Context, username string) error
 }
 
 // UserStore 接口的实现.
@@ -45,3 +48,26 @@ func (u *users) Get(ctx context.Context, username string) (*model.UserM, error)
 func (u *users) Update(ctx context.Context, user *model.UserM) error {
 	return u.db.Save(user).Error
 }
+
+// List 根据 offset 和 limit 返回 user 列表及总数.
+func (u *users) List(ctx context.Context, offset, limit int) (count int64, ret []*model.UserM, err error) {
+	err = u.db.Model(&model.UserM{}).
+		Count(&count).
+		Offset(offset).
+		Limit(defaultLimit(limit)).
+		Order("id desc").
+		Find(&ret).
+		Error
+
+	return
+}
+
+// Delete 根据 username 删除 user 记录.
+func (u *users) Delete(ctx context.Context, username string) error {
+	err := u.db.Where("username = ?", username).Delete(&model.UserM{}).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return nil
+}
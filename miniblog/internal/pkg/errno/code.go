@@ -22,6 +22,9 @@ var (
 	// ErrTokenInvalid 表示 JWT Token 格式错误.
 	ErrTokenInvalid = &Errno{HTTP: 401, Code: "AuthFailure.TokenInvalid", Message: "Token was invalid."}
 
+	// ErrTokenRevoked 表示 JWT Token 已被吊销，例如已经退出登录或修改了密码.
+	ErrTokenRevoked = &Errno{HTTP: 401, Code: "AuthFailure.TokenRevoked", Message: "Token was revoked."}
+
 	// ErrUnauthorized 表示请求没有被授权.
 	ErrUnauthorized = &Errno{HTTP: 401, Code: "AuthFailure.Unauthorized", Message: "Unauthorized."}
 )
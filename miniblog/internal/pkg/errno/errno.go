@@ -14,10 +14,12 @@ func (err *Errno) Error() string {
 	return err.Message
 }
 
-// SetMessage 设置 Errno 类型错误中的 Message 字段.
+// SetMessage 返回设置了 Message 字段的 Errno 副本.
+// 预定义的 Errno 是全局共享的，不能直接修改，否则并发请求之间会互相覆盖错误信息.
 func (err *Errno) SetMessage(format string, args ...interface{}) *Errno {
-	err.Message = fmt.Sprintf(format, args...)
-	return err
+	e := *err
+	e.Message = fmt.Sprintf(format, args...)
+	return &e
 }
 
 // Decode 尝试从 err 中解析出业务错误码和错误信息.
@@ -0,0 +1,61 @@
+package errno
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSetMessage(t *testing.T) {
+	message := ErrInvalidParameter.Message
+
+	err := ErrInvalidParameter.SetMessage("username: %s", "too short")
+	if err.Message != "username: too short" {
+		t.Errorf("SetMessage().Message = %q, want %q", err.Message, "username: too short")
+	}
+	if err.HTTP != ErrInvalidParameter.HTTP || err.Code != ErrInvalidParameter.Code {
+		t.Errorf("SetMessage() = %+v, want the HTTP status and code of %+v", err, ErrInvalidParameter)
+	}
+	if ErrInvalidParameter.Message != message {
+		t.Errorf("ErrInvalidParameter.Message = %q after SetMessage, want %q", ErrInvalidParameter.Message, message)
+	}
+}
+
+func TestSetMessageConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			want := fmt.Sprintf("request %d", i)
+			if got := ErrInvalidParameter.SetMessage(want).Message; got != want {
+				t.Errorf("SetMessage(%q).Message = %q", want, got)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHTTP int
+		wantCode string
+		wantMsg  string
+	}{
+		{"nil", nil, OK.HTTP, OK.Code, OK.Message},
+		{"errno", ErrInvalidParameter.SetMessage("bad"), ErrInvalidParameter.HTTP, ErrInvalidParameter.Code, "bad"},
+		{"other error", errors.New("boom"), InternalServerError.HTTP, InternalServerError.Code, "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpStatus, code, msg := Decode(tt.err)
+			if httpStatus != tt.wantHTTP || code != tt.wantCode || msg != tt.wantMsg {
+				t.Errorf("Decode() = %d, %q, %q, want %d, %q, %q", httpStatus, code, msg, tt.wantHTTP, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
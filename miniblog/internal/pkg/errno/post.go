@@ -0,0 +1,6 @@
+package errno
+
+var (
+	// ErrPostNotFound 表示未找到指定的博客.
+	ErrPostNotFound = &Errno{HTTP: 404, Code: "ResourceNotFound.PostNotFound", Message: "Post was not found."}
+)
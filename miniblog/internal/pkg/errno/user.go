@@ -4,6 +4,9 @@ var (
 	// ErrUserAlreadyExist 代表用户已经存在.
 	ErrUserAlreadyExist = &Errno{HTTP: 400, Code: "FailedOperation.UserAlreadyExist", Message: "User already exist."}
 
+	// ErrUsernameReserved 表示用户名被系统保留，不能注册.
+	ErrUsernameReserved = &Errno{HTTP: 400, Code: "InvalidParameter.UsernameReserved", Message: "Username is reserved."}
+
 	// ErrUserNotFound 表示未找到用户.
 	ErrUserNotFound = &Errno{HTTP: 404, Code: "ResourceNotFound.UserNotFound", Message: "User was not found."}
 
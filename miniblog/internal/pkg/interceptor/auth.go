@@ -0,0 +1,75 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	"github.com/Forest-211/miniblog/internal/pkg/middleware"
+	"github.com/Forest-211/miniblog/pkg/denylist"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+// Authn 是 gRPC 认证拦截器，作用与 middleware.Authn 相同，从 `authorization` 元数据中提取访问令牌.
+// publicMethods 中的方法不需要认证，方法名为 gRPC 的完整方法名.
+func Authn(dl denylist.Denylist, publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := make(map[string]bool, len(publicMethods))
+	for _, method := range publicMethods {
+		public[method] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		var t string
+		fmt.Sscanf(metadataValue(ctx, "Authorization"), "Bearer %s", &t)
+
+		claims, err := token.ParseType(t, token.TypeAccess)
+		if err != nil {
+			return nil, errno.ErrTokenInvalid
+		}
+
+		revoked, err := denylist.Revoked(ctx, dl, claims.ID, claims.Identity, claims.IssuedAt)
+		if err != nil {
+			log.C(ctx).Errorw("Failed to check token denylist", "err", err)
+			return nil, errno.InternalServerError
+		}
+		if revoked {
+			return nil, errno.ErrTokenRevoked
+		}
+
+		ctx = context.WithValue(ctx, known.XUsernameKey, claims.Identity)
+		ctx = context.WithValue(ctx, known.XTokenClaimsKey, claims)
+
+		return handler(ctx, req)
+	}
+}
+
+// ResourceFunc 将 gRPC 请求映射为 casbin 授权使用的资源和操作，与对应 HTTP 接口的路径和方法相同.
+type ResourceFunc func(req interface{}) (obj, act string)
+
+// Authz 是 gRPC 授权拦截器，作用与 middleware.Authz 相同，只对 resources 中的方法进行授权.
+func Authz(a middleware.Auther, resources map[string]ResourceFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resource, ok := resources[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		sub, _ := ctx.Value(known.XUsernameKey).(string)
+		obj, act := resource(req)
+
+		log.C(ctx).Debugw("Build authorize context", "sub", sub, "obj", obj, "act", act)
+		if allowed, _ := a.Authorize(sub, obj, act); !allowed {
+			return nil, errno.ErrUnauthorized
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,257 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/pkg/denylist"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+func init() {
+	token.Init("Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", known.XUsernameKey, time.Hour, 24*time.Hour)
+}
+
+// fakeStream 记录拦截器通过 grpc.SetHeader 设置的元数据.
+type fakeStream struct {
+	header metadata.MD
+}
+
+func (s *fakeStream) Method() string { return "/test/Method" }
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *fakeStream) SetTrailer(md metadata.MD) error { return nil }
+
+// fakeAuther 只允许 sub 访问 obj.
+type fakeAuther struct {
+	sub, obj, act string
+}
+
+func (a *fakeAuther) Authorize(sub, obj, act string) (bool, error) {
+	return sub == a.sub && obj == a.obj && act == a.act, nil
+}
+
+func incoming(kv ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"reuse", incoming("x-request-id", "req-1"), "req-1"},
+		{"generate", context.Background(), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &fakeStream{}
+			ctx := grpc.NewContextWithServerTransportStream(tt.ctx, stream)
+
+			var got string
+			_, err := RequestID()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				got, _ = ctx.Value(known.XRequestIDKey).(string)
+				return nil, nil
+			})
+			if err != nil {
+				t.Fatalf("RequestID() err = %v", err)
+			}
+			if got == "" || (tt.want != "" && got != tt.want) {
+				t.Errorf("request id in context = %q, want %q", got, tt.want)
+			}
+			if header := stream.header.Get(known.XRequestIDKey); len(header) != 1 || header[0] != got {
+				t.Errorf("response header = %v, want [%s]", header, got)
+			}
+		})
+	}
+}
+
+func TestAuthn(t *testing.T) {
+	ctx := context.Background()
+	dl := denylist.NewMemory()
+
+	valid, err := token.Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	loggedOut, err := token.Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	claims, _ := token.ParseType(loggedOut.AccessToken, token.TypeAccess)
+	if err := dl.Add(ctx, claims.ID, claims.ExpiresAt); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		ctx     context.Context
+		wantErr error
+		wantSub string
+	}{
+		{"public method", "/public", context.Background(), nil, ""},
+		{"valid", "/private", incoming("authorization", "Bearer "+valid.AccessToken), nil, "colin"},
+		{"missing metadata", "/private", context.Background(), errno.ErrTokenInvalid, ""},
+		{"malformed", "/private", incoming("authorization", "Bearer not-a-token"), errno.ErrTokenInvalid, ""},
+		{"refresh token", "/private", incoming("authorization", "Bearer "+valid.RefreshToken), errno.ErrTokenInvalid, ""},
+		{"logged out", "/private", incoming("authorization", "Bearer "+loggedOut.AccessToken), errno.ErrTokenRevoked, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			var sub string
+			_, err := Authn(dl, "/public")(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				sub, _ = ctx.Value(known.XUsernameKey).(string)
+				return nil, nil
+			})
+			if err != tt.wantErr {
+				t.Fatalf("Authn() err = %v, want %v", err, tt.wantErr)
+			}
+			if called != (tt.wantErr == nil) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantErr == nil)
+			}
+			if sub != tt.wantSub {
+				t.Errorf("username in context = %q, want %q", sub, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestAuthz(t *testing.T) {
+	a := &fakeAuther{sub: "colin", obj: "/v1/users/colin", act: "GET"}
+	resources := map[string]ResourceFunc{
+		"/getuser": func(req interface{}) (string, string) {
+			return "/v1/users/" + req.(string), "GET"
+		},
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		sub     string
+		req     string
+		wantErr error
+	}{
+		{"allowed", "/getuser", "colin", "colin", nil},
+		{"other user", "/getuser", "colin", "tony", errno.ErrUnauthorized},
+		{"anonymous", "/getuser", "", "colin", errno.ErrUnauthorized},
+		{"not a resource", "/other", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), known.XUsernameKey, tt.sub)
+			called := false
+			_, err := Authz(a, resources)(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+			if err != tt.wantErr {
+				t.Fatalf("Authz() err = %v, want %v", err, tt.wantErr)
+			}
+			if called != (tt.wantErr == nil) {
+				t.Errorf("handler called = %v, want %v", called, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	original := status.Error(codes.Unavailable, "unavailable")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+		wantMsg    string
+	}{
+		{"ok", nil, codes.OK, "", ""},
+		{"invalid parameter", errno.ErrInvalidParameter.SetMessage("bad name"), codes.InvalidArgument, errno.ErrInvalidParameter.Code, "bad name"},
+		{"unauthenticated", errno.ErrTokenInvalid, codes.Unauthenticated, errno.ErrTokenInvalid.Code, errno.ErrTokenInvalid.Message},
+		{"unauthorized", errno.ErrUnauthorized, codes.PermissionDenied, errno.ErrUnauthorized.Code, errno.ErrUnauthorized.Message},
+		{"not found", errno.ErrUserNotFound, codes.NotFound, errno.ErrUserNotFound.Code, errno.ErrUserNotFound.Message},
+		{"unknown error", errors.New("boom"), codes.Internal, errno.InternalServerError.Code, "boom"},
+		{"grpc status", original, codes.Unavailable, "", "unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Status()(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return "resp", nil
+			})
+			if tt.err == nil {
+				if err != nil || resp != "resp" {
+					t.Fatalf("Status() = %v, %v, want resp, nil", resp, err)
+				}
+				return
+			}
+
+			st, ok := status.FromError(err)
+			if !ok {
+				t.Fatalf("Status() err = %v, want a gRPC status", err)
+			}
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMsg {
+				t.Errorf("status = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMsg)
+			}
+
+			var reason string
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("ErrorInfo.Reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+
+	resp, err := Recovery()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "resp", nil
+	})
+	if err != nil || resp != "resp" {
+		t.Fatalf("Recovery() = %v, %v, want resp, nil", resp, err)
+	}
+
+	resp, err = Recovery()(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	st, ok := status.FromError(err)
+	if resp != nil || !ok || st.Code() != codes.Internal {
+		t.Fatalf("Recovery() after panic = %v, %v, want nil and codes.Internal", resp, err)
+	}
+	var reason string
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			reason = info.Reason
+		}
+	}
+	if reason != errno.InternalServerError.Code {
+		t.Errorf("ErrorInfo.Reason = %q, want %q", reason, errno.InternalServerError.Code)
+	}
+}
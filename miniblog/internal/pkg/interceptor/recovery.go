@@ -0,0 +1,26 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+)
+
+// Recovery 是 gRPC 拦截器，作用与 gin.Recovery() 相同，捕获 panic 并返回 codes.Internal，避免一个请求导致整个进程退出.
+// 需要作为第一个拦截器，才能同时捕获其它拦截器中的 panic.
+func Recovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.C(ctx).Errorw("Recovered from panic", "method", info.FullMethod, "panic", r, "stack", string(debug.Stack()))
+				err = toStatus(errno.InternalServerError)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,43 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+)
+
+// RequestID 是 gRPC 拦截器，作用与 middleware.RequestID 相同，在请求的 context 和返回的元数据中注入 `X-Request-ID`.
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// 检查请求元数据中是否有 `X-Request-ID`，如果有则复用，没有则新建
+		requestID := metadataValue(ctx, known.XRequestIDKey)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		// 使用与 gin.Context 相同的键，log.C 和 biz 层可以同时处理 HTTP 和 gRPC 请求
+		ctx = context.WithValue(ctx, known.XRequestIDKey, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(known.XRequestIDKey, requestID))
+
+		return handler(ctx, req)
+	}
+}
+
+// metadataValue 返回请求元数据中 key 对应的第一个值，gRPC 元数据的键都是小写的.
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package interceptor
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+)
+
+// Status 是 gRPC 拦截器，使用 errno.Decode 将返回的错误转换为 gRPC status，作用与 core.WriteResponse 相同.
+// 业务错误码保存在 status 的 ErrorInfo.Reason 中.
+func Status() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		return nil, toStatus(err)
+	}
+}
+
+// toStatus 将错误转换为 gRPC status，已经是 gRPC status 的错误原样返回.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	hcode, code, message := errno.Decode(err)
+	st := status.New(grpcCode(hcode, code), message)
+	if detailed, derr := st.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: "miniblog"}); derr == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// grpcCode 将 HTTP 状态码转换为对应的 gRPC 状态码.
+// errno.ErrUnauthorized 在 HTTP 中沿用 401，但表示的是授权失败，对应 codes.PermissionDenied.
+func grpcCode(hcode int, code string) codes.Code {
+	if code == errno.ErrUnauthorized.Code {
+		return codes.PermissionDenied
+	}
+
+	switch hcode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
@@ -6,4 +6,10 @@ const (
 
 	// XUsernameKey 用来定义 Gin 上下文的键，代表请求的所有者.
 	XUsernameKey = "X-Username"
+
+	// XTokenClaimsKey 用来定义 Gin 上下文的键，代表请求所用访问令牌的 *token.Claims.
+	XTokenClaimsKey = "X-Token-Claims"
 )
+
+// AdminUsername 是管理员用户名，管理员可以管理所有用户.
+const AdminUsername = "root"
@@ -0,0 +1,266 @@
+package log
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+)
+
+// Logger 定义了 miniblog 项目的日志接口. 该接口只包含了支持的日志记录方法.
+type Logger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+	Panicw(msg string, keysAndValues ...interface{})
+	Fatalw(msg string, keysAndValues ...interface{})
+	Sync()
+}
+
+// zapLogger 是 Logger 接口的具体实现. 它底层封装了 zap.Logger.
+type zapLogger struct {
+	z *zap.Logger
+	// closers 是日志文件的 writer，替换全局 Logger 时需要关闭
+	closers []io.Closer
+}
+
+// 确保 zapLogger 实现了 Logger 接口. 以下变量赋值，可以使错误在编译期被发现.
+var _ Logger = &zapLogger{}
+
+var (
+	mu sync.Mutex
+
+	// std 定义了默认的全局 Logger.
+	std = NewLogger(NewOptions())
+
+	// restoreStdLog 撤销标准库 log.Logger 到 std 的重定向.
+	restoreStdLog = zap.RedirectStdLog(std.z)
+)
+
+// Init 使用指定的选项初始化 Logger，并关闭被替换的 Logger 打开的日志文件.
+func Init(opts *Options) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	restoreStdLog()
+	std.Sync()
+	std.close()
+
+	std = NewLogger(opts)
+	// 把标准库的 log.Logger 的 info 级别的输出重定向到 zap.Logger
+	restoreStdLog = zap.RedirectStdLog(std.z)
+}
+
+// NewLogger 根据传入的 opts 创建 Logger.
+func NewLogger(opts *Options) *zapLogger {
+	if opts == nil {
+		opts = NewOptions()
+	}
+
+	// 将文本格式的日志级别，例如 info 转换为 zapcore.Level 类型以供后面使用
+	var zapLevel zapcore.Level
+	if err := zapLevel.UnmarshalText([]byte(opts.Level)); err != nil {
+		// 如果指定了非法的日志级别，则默认使用 info 级别
+		zapLevel = zapcore.InfoLevel
+	}
+
+	// 创建一个默认的 encoder 配置
+	encoderConfig := zap.NewProductionEncoderConfig()
+	// 自定义 MessageKey 为 message，message 语义更明确
+	encoderConfig.MessageKey = "message"
+	// 自定义 TimeKey 为 timestamp，timestamp 语义更明确
+	encoderConfig.TimeKey = "timestamp"
+	// 指定时间序列化函数，将时间序列化为 `2006-01-02 15:04:05.000` 格式，更易读
+	encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
+	}
+	// 指定 time.Duration 序列化函数，将 time.Duration 序列化为经过的毫秒数的浮点数
+	// 毫秒数比默认的秒数更精确
+	encoderConfig.EncodeDuration = func(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendFloat64(float64(d) / float64(time.Millisecond))
+	}
+
+	cores, closers := newCores(opts, encoderConfig, zapLevel)
+	core := zapcore.NewTee(cores...)
+
+	zapOpts := []zap.Option{
+		// 错误日志输出到标准错误
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		// 跳过 log 包本身的调用栈，使 caller 指向调用日志方法的位置
+		zap.AddCallerSkip(1),
+	}
+	if !opts.DisableCaller {
+		zapOpts = append(zapOpts, zap.AddCaller())
+	}
+	if !opts.DisableStacktrace {
+		zapOpts = append(zapOpts, zap.AddStacktrace(zapcore.PanicLevel))
+	}
+
+	z := zap.New(core, zapOpts...)
+
+	return &zapLogger{z: z, closers: closers}
+}
+
+// newCores 为 OutputPaths 中的每个输出创建一个 zapcore.Core，stdout 和 stderr 之外的路径按文件大小轮转.
+// console 格式下只有 stdout 和 stderr 使用彩色的日志级别，避免 ANSI 转义码写入日志文件.
+func newCores(opts *Options, encoderConfig zapcore.EncoderConfig, level zapcore.Level) ([]zapcore.Core, []io.Closer) {
+	paths := opts.OutputPaths
+	if len(paths) == 0 {
+		paths = []string{"stdout"}
+	}
+
+	cores := make([]zapcore.Core, 0, len(paths))
+	var closers []io.Closer
+	for _, path := range paths {
+		var ws zapcore.WriteSyncer
+		terminal := true
+		switch path {
+		case "stdout":
+			ws = zapcore.Lock(os.Stdout)
+		case "stderr":
+			ws = zapcore.Lock(os.Stderr)
+		default:
+			w := &lumberjack.Logger{
+				Filename:   path,
+				MaxSize:    opts.MaxSize,
+				MaxBackups: opts.MaxBackups,
+				MaxAge:     opts.MaxAge,
+				Compress:   opts.Compress,
+				LocalTime:  true,
+			}
+			ws = zapcore.AddSync(w)
+			closers = append(closers, w)
+			terminal = false
+		}
+
+		cores = append(cores, zapcore.NewCore(newEncoder(opts.Format, encoderConfig, terminal), ws, level))
+	}
+
+	return cores, closers
+}
+
+// newEncoder 根据日志格式创建 encoder，terminal 表示输出到终端.
+func newEncoder(format string, encoderConfig zapcore.EncoderConfig, terminal bool) zapcore.Encoder {
+	if format == "json" {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	// console 格式下使用大写的级别，输出到终端时使用彩色，便于查看
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	if terminal {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// Sync 调用底层 zap.Logger 的 Sync 方法，将缓存中的日志刷新到磁盘文件中. 主程序需要在退出前调用 Sync.
+func Sync() { stdLogger().Sync() }
+
+func (l *zapLogger) Sync() {
+	_ = l.z.Sync()
+}
+
+// close 关闭 Logger 打开的日志文件.
+func (l *zapLogger) close() {
+	for _, c := range l.closers {
+		_ = c.Close()
+	}
+}
+
+// Debugw 输出 debug 级别的日志.
+func Debugw(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Debugw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Debugw(msg, keysAndValues...)
+}
+
+// Infow 输出 info 级别的日志.
+func Infow(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Infow(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Infow(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Infow(msg, keysAndValues...)
+}
+
+// Warnw 输出 warning 级别的日志.
+func Warnw(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Warnw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Warnw(msg, keysAndValues...)
+}
+
+// Errorw 输出 error 级别的日志.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Errorw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Errorw(msg, keysAndValues...)
+}
+
+// Panicw 输出 panic 级别的日志.
+func Panicw(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Panicw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Panicw(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Panicw(msg, keysAndValues...)
+}
+
+// Fatalw 输出 fatal 级别的日志.
+func Fatalw(msg string, keysAndValues ...interface{}) {
+	stdLogger().z.Sugar().Fatalw(msg, keysAndValues...)
+}
+
+func (l *zapLogger) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.z.Sugar().Fatalw(msg, keysAndValues...)
+}
+
+// C 解析传入的 context，尝试提取关注的键值，并添加到 zap.Logger 结构化日志中.
+// 同时支持 *gin.Context 和通过 context.WithValue 传入键值的标准 context.
+func C(ctx context.Context) *zapLogger {
+	return stdLogger().C(ctx)
+}
+
+func (l *zapLogger) C(ctx context.Context) *zapLogger {
+	lc := l.clone()
+	if ctx == nil {
+		return lc
+	}
+
+	if requestID := ctx.Value(known.XRequestIDKey); requestID != nil {
+		lc.z = lc.z.With(zap.Any(known.XRequestIDKey, requestID))
+	}
+
+	if username := ctx.Value(known.XUsernameKey); username != nil {
+		lc.z = lc.z.With(zap.Any(known.XUsernameKey, username))
+	}
+
+	return lc
+}
+
+// clone 深度拷贝 zapLogger.
+func (l *zapLogger) clone() *zapLogger {
+	lc := *l
+	return &lc
+}
+
+func stdLogger() *zapLogger {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return std
+}
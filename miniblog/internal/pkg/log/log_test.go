@@ -0,0 +1,57 @@
+package log
+
+import (
+	stdlog "log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(%s) err: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestNewLoggerFileWithoutColor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "miniblog.log")
+	l := NewLogger(&Options{Level: "info", Format: "console", OutputPaths: []string{"stderr", path}})
+	defer l.close()
+
+	l.Infow("hello", "key", "value")
+	l.Sync()
+
+	got := readLog(t, path)
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("log file contains ANSI escape codes: %q", got)
+	}
+	if !strings.Contains(got, "INFO") || !strings.Contains(got, "hello") {
+		t.Errorf("log file = %q, want an INFO line with the message", got)
+	}
+}
+
+func TestInitRedirectsStdLog(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+
+	Init(&Options{Level: "info", Format: "json", OutputPaths: []string{first}})
+	Init(&Options{Level: "info", Format: "json", OutputPaths: []string{second}})
+	t.Cleanup(func() { Init(NewOptions()) })
+
+	stdlog.Print("from stdlib")
+	Sync()
+
+	if got := readLog(t, second); !strings.Contains(got, "from stdlib") {
+		t.Errorf("second log = %q, want the stdlib log line", got)
+	}
+	if _, err := os.Stat(first); err == nil {
+		if got := readLog(t, first); strings.Contains(got, "from stdlib") {
+			t.Errorf("first log = %q, want no output after it was replaced", got)
+		}
+	}
+}
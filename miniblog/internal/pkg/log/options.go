@@ -0,0 +1,39 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Options 包含与日志相关的配置项.
+type Options struct {
+	// 是否开启 caller，如果开启会在日志中显示调用日志所在的文件和行号
+	DisableCaller bool
+	// 是否禁止在 panic 及以上级别打印堆栈信息
+	DisableStacktrace bool
+	// 指定日志级别，可选值：debug, info, warn, error, dpanic, panic, fatal
+	Level string
+	// 指定日志显示格式，可选值：console, json
+	Format string
+	// 指定日志输出位置，stdout 和 stderr 之外的路径都视为文件
+	OutputPaths []string
+	// 单个日志文件的最大大小，单位 MB，超过后进行轮转
+	MaxSize int
+	// 保留的旧日志文件的最大个数，0 表示全部保留
+	MaxBackups int
+	// 保留旧日志文件的最大天数，0 表示不按时间清理
+	MaxAge int
+	// 是否使用 gzip 压缩轮转后的旧日志文件
+	Compress bool
+}
+
+// NewOptions 创建一个带有默认参数的 Options 对象.
+func NewOptions() *Options {
+	return &Options{
+		DisableCaller:     false,
+		DisableStacktrace: false,
+		Level:             zapcore.InfoLevel.String(),
+		Format:            "console",
+		OutputPaths:       []string{"stdout"},
+		MaxSize:           100,
+	}
+}
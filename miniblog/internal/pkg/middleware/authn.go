@@ -1,28 +1,44 @@
 package middleware
 
 import (
-	"fmt"
-
 	"github.com/Forest-211/miniblog/internal/pkg/core"
 	"github.com/Forest-211/miniblog/internal/pkg/errno"
 	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/internal/pkg/log"
+	"github.com/Forest-211/miniblog/pkg/denylist"
 	"github.com/Forest-211/miniblog/pkg/token"
 	"github.com/gin-gonic/gin"
 )
 
-func Authn() gin.HandlerFunc {
+// Authn 是认证中间件，用来从 gin.Context 中提取访问令牌并验证令牌是否合法、是否已被吊销.
+func Authn(dl denylist.Denylist) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 解析 JWT Token
-		username, err := token.ParseRequest(c)
+		claims, err := token.ParseRequest(c)
 		if err != nil {
-			fmt.Println("error: ", err)
 			core.WriteResponse(c, errno.ErrTokenInvalid, nil)
 			c.Abort()
 
 			return
 		}
 
-		c.Set(known.XUsernameKey, username)
+		revoked, err := denylist.Revoked(c, dl, claims.ID, claims.Identity, claims.IssuedAt)
+		if err != nil {
+			log.C(c).Errorw("Failed to check token denylist", "err", err)
+			core.WriteResponse(c, errno.InternalServerError, nil)
+			c.Abort()
+
+			return
+		}
+		if revoked {
+			core.WriteResponse(c, errno.ErrTokenRevoked, nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Set(known.XUsernameKey, claims.Identity)
+		c.Set(known.XTokenClaimsKey, claims)
 		c.Next()
 	}
 }
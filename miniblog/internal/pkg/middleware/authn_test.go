@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Forest-211/miniblog/internal/pkg/core"
+	"github.com/Forest-211/miniblog/internal/pkg/errno"
+	"github.com/Forest-211/miniblog/internal/pkg/known"
+	"github.com/Forest-211/miniblog/pkg/denylist"
+	"github.com/Forest-211/miniblog/pkg/token"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	token.Init("Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", known.XUsernameKey, time.Hour, 24*time.Hour)
+}
+
+func TestAuthn(t *testing.T) {
+	ctx := context.Background()
+	dl := denylist.NewMemory()
+
+	sign := func() *token.Pair {
+		pair, err := token.Sign("colin")
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return pair
+	}
+	valid := sign()
+
+	loggedOut := sign()
+	claims, _ := token.ParseType(loggedOut.AccessToken, token.TypeAccess)
+	if err := dl.Add(ctx, claims.ID, claims.ExpiresAt); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// 修改密码前签发的令牌
+	tony, err := token.Sign("tony")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if err := dl.RevokeBefore(ctx, "tony", time.Now(), token.MaxLifetime()); err != nil {
+		t.Fatalf("RevokeBefore() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		wantCode string
+	}{
+		{"valid", "Bearer " + valid.AccessToken, ""},
+		{"missing header", "", errno.ErrTokenInvalid.Code},
+		{"malformed", "Bearer not-a-token", errno.ErrTokenInvalid.Code},
+		{"refresh token", "Bearer " + valid.RefreshToken, errno.ErrTokenInvalid.Code},
+		{"logged out", "Bearer " + loggedOut.AccessToken, errno.ErrTokenRevoked.Code},
+		{"password changed", "Bearer " + tony.AccessToken, errno.ErrTokenRevoked.Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var username string
+			r := gin.New()
+			r.GET("/", Authn(dl), func(c *gin.Context) {
+				username = c.GetString(known.XUsernameKey)
+				if _, ok := c.Value(known.XTokenClaimsKey).(*token.Claims); !ok {
+					t.Errorf("token claims are not set in context")
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if tt.wantCode == "" {
+				if w.Code != http.StatusOK || username != "colin" {
+					t.Errorf("status = %d, username = %q, want 200 and colin", w.Code, username)
+				}
+				return
+			}
+
+			var resp core.ErrResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Unmarshal() error = %v, body = %s", err, w.Body.String())
+			}
+			if w.Code != http.StatusUnauthorized || resp.Code != tt.wantCode {
+				t.Errorf("status = %d, code = %q, want 401 and %q", w.Code, resp.Code, tt.wantCode)
+			}
+			if username != "" {
+				t.Errorf("handler was called with username %q", username)
+			}
+		})
+	}
+}
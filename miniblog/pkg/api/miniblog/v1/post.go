@@ -1,14 +1,45 @@
 package v1
 
+// CreatePostRequest 指定了 `POST /v1/posts` 接口的请求参数.
 type CreatePostRequest struct {
 	Title   string `json:"title" valid:"required,stringlength(1|255)"`
 	Content string `json:"content" valid:"required,stringlength(1|4294967295)"`
 }
 
+// CreatePostResponse 指定了 `POST /v1/posts` 接口的返回参数.
+type CreatePostResponse struct {
+	PostID string `json:"postID"`
+}
+
+// GetPostResponse 指定了 `GET /v1/posts/{postID}` 接口的返回参数.
+type GetPostResponse PostInfo
+
+// UpdatePostRequest 指定了 `PUT /v1/posts/{postID}` 接口的请求参数.
+// 为 nil 的字段不更新，传入的字段需要满足和 CreatePostRequest 相同的长度限制.
+type UpdatePostRequest struct {
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+}
+
+// ListPostRequest 指定了 `GET /v1/posts` 接口的请求参数.
 type ListPostRequest struct {
-	Username string `json:"username" valid:"alphanum,required,stringlength(1|255)"`
+	Offset int `form:"offset" valid:"range(0|2147483647)"`
+	// Limit 为 0 时返回默认数量的记录，最大为 100.
+	Limit int `form:"limit" valid:"range(0|100)"`
+}
+
+// ListPostResponse 指定了 `GET /v1/posts` 接口的返回参数.
+type ListPostResponse struct {
+	TotalCount int64       `json:"totalCount"`
+	Posts      []*PostInfo `json:"posts"`
 }
 
-type PostByIDRequest struct {
-	ID string `json:"id" valid:"required"`
+// PostInfo 指定了博客的详细信息.
+type PostInfo struct {
+	Username  string `json:"username,omitempty"`
+	PostID    string `json:"postID,omitempty"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
 }
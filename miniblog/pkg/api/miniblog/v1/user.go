@@ -17,7 +17,28 @@ type LoginRequest struct {
 
 // LoginResponse 指定了 `POST /login` 接口的返回参数.
 type LoginResponse struct {
+	// 访问令牌.
 	Token string `json:"token"`
+
+	// 刷新令牌，用于在访问令牌过期后换取新的令牌.
+	RefreshToken string `json:"refreshToken"`
+
+	// 访问令牌的过期时间.
+	ExpiresAt string `json:"expiresAt"`
+}
+
+// RefreshTokenRequest 指定了 `POST /v1/refresh-token` 接口的请求参数.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" valid:"required"`
+}
+
+// RefreshTokenResponse 指定了 `POST /v1/refresh-token` 接口的返回参数.
+type RefreshTokenResponse LoginResponse
+
+// LogoutRequest 指定了 `POST /logout` 接口的请求参数.
+type LogoutRequest struct {
+	// 刷新令牌，不为空时会和访问令牌一起吊销.
+	RefreshToken string `json:"refreshToken"`
 }
 
 // ChangePasswordRequest 指定了 `POST /v1/users/{name}/change-password` 接口的请求参数.
@@ -32,6 +53,27 @@ type ChangePasswordRequest struct {
 // GetUserResponse 指定了 `GET /v1/users/{name}` 接口的返回参数.
 type GetUserResponse UserInfo
 
+// UpdateUserRequest 指定了 `PUT /v1/users/{name}` 接口的请求参数.
+// 为 nil 的字段不更新，传入的字段需要满足和 CreateUserRequest 相同的限制.
+type UpdateUserRequest struct {
+	Nickname *string `json:"nickname"`
+	Email    *string `json:"email"`
+	Phone    *string `json:"phone"`
+}
+
+// ListUserRequest 指定了 `GET /v1/users` 接口的请求参数.
+type ListUserRequest struct {
+	Offset int `form:"offset" valid:"range(0|2147483647)"`
+	// Limit 为 0 时返回默认数量的记录，最大为 100.
+	Limit int `form:"limit" valid:"range(0|100)"`
+}
+
+// ListUserResponse 指定了 `GET /v1/users` 接口的返回参数.
+type ListUserResponse struct {
+	TotalCount int64       `json:"totalCount"`
+	Users      []*UserInfo `json:"users"`
+}
+
 // UserInfo 指定了用户的详细信息.
 type UserInfo struct {
 	Username  string `json:"username"`
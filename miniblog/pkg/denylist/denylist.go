@@ -0,0 +1,38 @@
+// Package denylist 保存被吊销的令牌，令牌在过期前都不能再使用.
+package denylist
+
+import (
+	"context"
+	"time"
+)
+
+// Denylist 定义了令牌黑名单需要实现的方法.
+type Denylist interface {
+	// Add 吊销唯一标识为 jti 的令牌，记录保存到令牌过期时间 expiresAt 为止.
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+	// Claim 原子地吊销唯一标识为 jti 的令牌，令牌此前已被吊销或已过期时返回 false.
+	// 用于保证刷新令牌只能被使用一次，并发请求中只有一个会返回 true.
+	Claim(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	// Contains 返回唯一标识为 jti 的令牌是否已被吊销.
+	Contains(ctx context.Context, jti string) (bool, error)
+	// RevokeBefore 吊销 identity 在 t 之前签发的所有令牌，记录保存 ttl 时间，ttl 应不小于令牌的最长有效期.
+	RevokeBefore(ctx context.Context, identity string, t time.Time, ttl time.Duration) error
+	// RevokedBefore 返回 identity 在该时间之前签发的令牌都已被吊销，没有记录时返回零值.
+	RevokedBefore(ctx context.Context, identity string) (time.Time, error)
+}
+
+// Revoked 返回令牌是否已被吊销，令牌被单独吊销或者签发时间早于 identity 的吊销时间时都视为已吊销.
+func Revoked(ctx context.Context, d Denylist, jti, identity string, issuedAt time.Time) (bool, error) {
+	revoked, err := d.Contains(ctx, jti)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	before, err := d.RevokedBefore(ctx, identity)
+	if err != nil {
+		return false, err
+	}
+
+	// 令牌的签发时间精确到毫秒
+	return issuedAt.Before(before.Truncate(time.Millisecond)), nil
+}
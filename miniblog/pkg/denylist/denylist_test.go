@@ -0,0 +1,190 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// implementations 返回需要测试的 Denylist 实现，redis 实现使用 miniredis.
+func implementations(t *testing.T) map[string]func(t *testing.T) Denylist {
+	return map[string]func(t *testing.T) Denylist{
+		"memory": func(t *testing.T) Denylist {
+			return NewMemory()
+		},
+		"redis": func(t *testing.T) Denylist {
+			s := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+			t.Cleanup(func() { client.Close() })
+
+			return NewRedis(client, "miniblog:denylist:")
+		},
+	}
+}
+
+func TestAddContains(t *testing.T) {
+	ctx := context.Background()
+	for name, newDenylist := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			d := newDenylist(t)
+
+			if err := d.Add(ctx, "jti-1", time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			// 已经过期的令牌不需要记录
+			if err := d.Add(ctx, "jti-expired", time.Now().Add(-time.Second)); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			for jti, want := range map[string]bool{"jti-1": true, "jti-2": false, "jti-expired": false} {
+				got, err := d.Contains(ctx, jti)
+				if err != nil {
+					t.Fatalf("Contains(%q) error = %v", jti, err)
+				}
+				if got != want {
+					t.Errorf("Contains(%q) = %v, want %v", jti, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	for name, newDenylist := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			d := newDenylist(t)
+			expiresAt := time.Now().Add(time.Hour)
+
+			claimed, err := d.Claim(ctx, "jti-1", expiresAt)
+			if err != nil || !claimed {
+				t.Fatalf("first Claim() = %v, %v, want true, nil", claimed, err)
+			}
+			claimed, err = d.Claim(ctx, "jti-1", expiresAt)
+			if err != nil || claimed {
+				t.Fatalf("second Claim() = %v, %v, want false, nil", claimed, err)
+			}
+			if revoked, _ := d.Contains(ctx, "jti-1"); !revoked {
+				t.Errorf("Contains() = false after Claim, want true")
+			}
+
+			// 已通过 Add 吊销的令牌不能再被 Claim
+			if err := d.Add(ctx, "jti-2", expiresAt); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if claimed, _ := d.Claim(ctx, "jti-2", expiresAt); claimed {
+				t.Errorf("Claim() = true for a revoked token, want false")
+			}
+
+			if claimed, _ := d.Claim(ctx, "jti-expired", time.Now().Add(-time.Second)); claimed {
+				t.Errorf("Claim() = true for an expired token, want false")
+			}
+		})
+	}
+}
+
+func TestClaimConcurrent(t *testing.T) {
+	ctx := context.Background()
+	for name, newDenylist := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			d := newDenylist(t)
+			expiresAt := time.Now().Add(time.Hour)
+
+			var (
+				wg      sync.WaitGroup
+				mu      sync.Mutex
+				claimed int
+			)
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					ok, err := d.Claim(ctx, "jti-1", expiresAt)
+					if err != nil {
+						t.Errorf("Claim() error = %v", err)
+						return
+					}
+					if ok {
+						mu.Lock()
+						claimed++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			if claimed != 1 {
+				t.Errorf("%d concurrent Claim() calls succeeded, want 1", claimed)
+			}
+		})
+	}
+}
+
+func TestRevoked(t *testing.T) {
+	ctx := context.Background()
+	for name, newDenylist := range implementations(t) {
+		t.Run(name, func(t *testing.T) {
+			d := newDenylist(t)
+
+			// 令牌的签发时间精确到毫秒，吊销时间之后同一毫秒内签发的令牌仍然有效
+			revokedAt := time.Now()
+			issuedBefore := revokedAt.Add(-time.Second).Truncate(time.Millisecond)
+			issuedAfter := revokedAt.Truncate(time.Millisecond)
+
+			if err := d.RevokeBefore(ctx, "colin", revokedAt, time.Hour); err != nil {
+				t.Fatalf("RevokeBefore() error = %v", err)
+			}
+			if err := d.Add(ctx, "jti-revoked", time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			tests := []struct {
+				name     string
+				jti      string
+				identity string
+				issuedAt time.Time
+				want     bool
+			}{
+				{"issued before revocation", "jti-1", "colin", issuedBefore, true},
+				{"issued after revocation", "jti-2", "colin", issuedAfter, false},
+				{"other user", "jti-3", "tony", issuedBefore, false},
+				{"revoked token", "jti-revoked", "tony", issuedAfter, true},
+			}
+			for _, tt := range tests {
+				got, err := Revoked(ctx, d, tt.jti, tt.identity, tt.issuedAt)
+				if err != nil {
+					t.Fatalf("%s: Revoked() error = %v", tt.name, err)
+				}
+				if got != tt.want {
+					t.Errorf("%s: Revoked() = %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeBeforeExpires(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	d := NewRedis(client, "miniblog:denylist:")
+
+	ctx := context.Background()
+	if err := d.RevokeBefore(ctx, "colin", time.Now(), time.Hour); err != nil {
+		t.Fatalf("RevokeBefore() error = %v", err)
+	}
+	s.FastForward(time.Hour + time.Second)
+
+	before, err := d.RevokedBefore(ctx, "colin")
+	if err != nil {
+		t.Fatalf("RevokedBefore() error = %v", err)
+	}
+	if !before.IsZero() {
+		t.Errorf("RevokedBefore() = %v after ttl, want zero", before)
+	}
+}
@@ -0,0 +1,121 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 是清理过期记录的最小间隔.
+const sweepInterval = time.Minute
+
+type userRevocation struct {
+	before    time.Time
+	expiresAt time.Time
+}
+
+// memory 是保存在进程内存中的 Denylist 实现，只适用于单实例部署.
+type memory struct {
+	mu        sync.Mutex
+	tokens    map[string]time.Time
+	users     map[string]userRevocation
+	lastSweep time.Time
+}
+
+// 确保 memory 实现了 Denylist 接口.
+var _ Denylist = (*memory)(nil)
+
+// NewMemory 创建一个保存在内存中的 Denylist.
+func NewMemory() Denylist {
+	return &memory{
+		tokens:    make(map[string]time.Time),
+		users:     make(map[string]userRevocation),
+		lastSweep: time.Now(),
+	}
+}
+
+// Add 是 Denylist 接口中 `Add` 方法的实现.
+func (m *memory) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	m.tokens[jti] = expiresAt
+
+	return nil
+}
+
+// Claim 是 Denylist 接口中 `Claim` 方法的实现，检查和写入在同一个锁内完成.
+func (m *memory) Claim(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if !now.Before(expiresAt) {
+		return false, nil
+	}
+
+	if revokedUntil, ok := m.tokens[jti]; ok && now.Before(revokedUntil) {
+		return false, nil
+	}
+
+	m.sweep()
+	m.tokens[jti] = expiresAt
+
+	return true, nil
+}
+
+// Contains 是 Denylist 接口中 `Contains` 方法的实现.
+func (m *memory) Contains(ctx context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.tokens[jti]
+
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// RevokeBefore 是 Denylist 接口中 `RevokeBefore` 方法的实现.
+func (m *memory) RevokeBefore(ctx context.Context, identity string, t time.Time, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	m.users[identity] = userRevocation{before: t, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+// RevokedBefore 是 Denylist 接口中 `RevokedBefore` 方法的实现.
+func (m *memory) RevokedBefore(ctx context.Context, identity string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.users[identity]
+	if !ok || time.Now().After(r.expiresAt) {
+		return time.Time{}, nil
+	}
+
+	return r.before, nil
+}
+
+// sweep 删除已经过期的记录，调用方需要持有锁.
+func (m *memory) sweep() {
+	now := time.Now()
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for jti, expiresAt := range m.tokens {
+		if now.After(expiresAt) {
+			delete(m.tokens, jti)
+		}
+	}
+
+	for identity, r := range m.users {
+		if now.After(r.expiresAt) {
+			delete(m.users, identity)
+		}
+	}
+}
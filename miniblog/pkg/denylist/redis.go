@@ -0,0 +1,86 @@
+package denylist
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisDenylist 是保存在 Redis 中的 Denylist 实现，多个实例可以共享同一个黑名单.
+type redisDenylist struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// 确保 redisDenylist 实现了 Denylist 接口.
+var _ Denylist = (*redisDenylist)(nil)
+
+// NewRedis 创建一个保存在 Redis 中的 Denylist，所有键都以 prefix 开头.
+func NewRedis(client redis.UniversalClient, prefix string) Denylist {
+	return &redisDenylist{client: client, prefix: prefix}
+}
+
+// Add 是 Denylist 接口中 `Add` 方法的实现.
+func (r *redisDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// 令牌已经过期，不需要再记录
+		return nil
+	}
+
+	return r.client.Set(ctx, r.tokenKey(jti), 1, ttl).Err()
+}
+
+// Claim 是 Denylist 接口中 `Claim` 方法的实现，使用 SET NX 保证只有一个调用方写入成功.
+func (r *redisDenylist) Claim(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return false, nil
+	}
+
+	return r.client.SetNX(ctx, r.tokenKey(jti), 1, ttl).Result()
+}
+
+// Contains 是 Denylist 接口中 `Contains` 方法的实现.
+func (r *redisDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.tokenKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// RevokeBefore 是 Denylist 接口中 `RevokeBefore` 方法的实现.
+func (r *redisDenylist) RevokeBefore(ctx context.Context, identity string, t time.Time, ttl time.Duration) error {
+	return r.client.Set(ctx, r.userKey(identity), t.UnixMilli(), ttl).Err()
+}
+
+// RevokedBefore 是 Denylist 接口中 `RevokedBefore` 方法的实现.
+func (r *redisDenylist) RevokedBefore(ctx context.Context, identity string) (time.Time, error) {
+	v, err := r.client.Get(ctx, r.userKey(identity)).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.UnixMilli(ms), nil
+}
+
+func (r *redisDenylist) tokenKey(jti string) string {
+	return r.prefix + "jti:" + jti
+}
+
+func (r *redisDenylist) userKey(identity string) string {
+	return r.prefix + "user:" + identity
+}
@@ -0,0 +1,199 @@
+// miniblog.proto 定义了 miniblog 的 gRPC 服务，与 HTTP 接口共用 biz 层.
+// 除 Login、CreateUser、RefreshToken 和 ChangePassword 外，所有方法都需要在
+// `authorization` 元数据中携带 `Bearer <访问令牌>`.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: miniblog/v1/miniblog.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_miniblog_v1_miniblog_proto protoreflect.FileDescriptor
+
+var file_miniblog_v1_miniblog_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x69,
+	0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6d, 0x69,
+	0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x1a, 0x16, 0x6d, 0x69, 0x6e, 0x69, 0x62,
+	0x6c, 0x6f, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x6f, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x16, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x75,
+	0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0xc0, 0x05, 0x0a, 0x0b, 0x55, 0x73,
+	0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x05, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0c,
+	0x52, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x20, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x66, 0x72, 0x65,
+	0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x06,
+	0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x5b, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x12, 0x22, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x6d, 0x69, 0x6e, 0x69,
+	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12,
+	0x4f, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1e, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x4f, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1e,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x49, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1c, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x69,
+	0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x8c, 0x03, 0x0a,
+	0x0b, 0x50, 0x6f, 0x73, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x1e, 0x2e, 0x6d, 0x69, 0x6e,
+	0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50,
+	0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d, 0x69, 0x6e,
+	0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50,
+	0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a,
+	0x07, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x73, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x00, 0x12, 0x4f,
+	0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x1e, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4f, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x1e, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x50, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e,
+	0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x50, 0x6f, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x12, 0x49, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x73, 0x74, 0x12, 0x1c, 0x2e, 0x6d,
+	0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x6f, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x69, 0x6e,
+	0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x36, 0x5a, 0x34, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x46, 0x6f, 0x72, 0x65, 0x73, 0x74,
+	0x2d, 0x32, 0x31, 0x31, 0x2f, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67, 0x2f, 0x70, 0x6b,
+	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x69, 0x6e, 0x69, 0x62, 0x6c, 0x6f, 0x67,
+	0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_miniblog_v1_miniblog_proto_goTypes = []interface{}{
+	(*CreateUserRequest)(nil),      // 0: miniblog.v1.CreateUserRequest
+	(*LoginRequest)(nil),           // 1: miniblog.v1.LoginRequest
+	(*RefreshTokenRequest)(nil),    // 2: miniblog.v1.RefreshTokenRequest
+	(*LogoutRequest)(nil),          // 3: miniblog.v1.LogoutRequest
+	(*ChangePasswordRequest)(nil),  // 4: miniblog.v1.ChangePasswordRequest
+	(*GetUserRequest)(nil),         // 5: miniblog.v1.GetUserRequest
+	(*UpdateUserRequest)(nil),      // 6: miniblog.v1.UpdateUserRequest
+	(*DeleteUserRequest)(nil),      // 7: miniblog.v1.DeleteUserRequest
+	(*ListUserRequest)(nil),        // 8: miniblog.v1.ListUserRequest
+	(*CreatePostRequest)(nil),      // 9: miniblog.v1.CreatePostRequest
+	(*GetPostRequest)(nil),         // 10: miniblog.v1.GetPostRequest
+	(*UpdatePostRequest)(nil),      // 11: miniblog.v1.UpdatePostRequest
+	(*DeletePostRequest)(nil),      // 12: miniblog.v1.DeletePostRequest
+	(*ListPostRequest)(nil),        // 13: miniblog.v1.ListPostRequest
+	(*CreateUserResponse)(nil),     // 14: miniblog.v1.CreateUserResponse
+	(*LoginResponse)(nil),          // 15: miniblog.v1.LoginResponse
+	(*LogoutResponse)(nil),         // 16: miniblog.v1.LogoutResponse
+	(*ChangePasswordResponse)(nil), // 17: miniblog.v1.ChangePasswordResponse
+	(*UserInfo)(nil),               // 18: miniblog.v1.UserInfo
+	(*UpdateUserResponse)(nil),     // 19: miniblog.v1.UpdateUserResponse
+	(*DeleteUserResponse)(nil),     // 20: miniblog.v1.DeleteUserResponse
+	(*ListUserResponse)(nil),       // 21: miniblog.v1.ListUserResponse
+	(*CreatePostResponse)(nil),     // 22: miniblog.v1.CreatePostResponse
+	(*PostInfo)(nil),               // 23: miniblog.v1.PostInfo
+	(*UpdatePostResponse)(nil),     // 24: miniblog.v1.UpdatePostResponse
+	(*DeletePostResponse)(nil),     // 25: miniblog.v1.DeletePostResponse
+	(*ListPostResponse)(nil),       // 26: miniblog.v1.ListPostResponse
+}
+var file_miniblog_v1_miniblog_proto_depIdxs = []int32{
+	0,  // 0: miniblog.v1.UserService.CreateUser:input_type -> miniblog.v1.CreateUserRequest
+	1,  // 1: miniblog.v1.UserService.Login:input_type -> miniblog.v1.LoginRequest
+	2,  // 2: miniblog.v1.UserService.RefreshToken:input_type -> miniblog.v1.RefreshTokenRequest
+	3,  // 3: miniblog.v1.UserService.Logout:input_type -> miniblog.v1.LogoutRequest
+	4,  // 4: miniblog.v1.UserService.ChangePassword:input_type -> miniblog.v1.ChangePasswordRequest
+	5,  // 5: miniblog.v1.UserService.GetUser:input_type -> miniblog.v1.GetUserRequest
+	6,  // 6: miniblog.v1.UserService.UpdateUser:input_type -> miniblog.v1.UpdateUserRequest
+	7,  // 7: miniblog.v1.UserService.DeleteUser:input_type -> miniblog.v1.DeleteUserRequest
+	8,  // 8: miniblog.v1.UserService.ListUser:input_type -> miniblog.v1.ListUserRequest
+	9,  // 9: miniblog.v1.PostService.CreatePost:input_type -> miniblog.v1.CreatePostRequest
+	10, // 10: miniblog.v1.PostService.GetPost:input_type -> miniblog.v1.GetPostRequest
+	11, // 11: miniblog.v1.PostService.UpdatePost:input_type -> miniblog.v1.UpdatePostRequest
+	12, // 12: miniblog.v1.PostService.DeletePost:input_type -> miniblog.v1.DeletePostRequest
+	13, // 13: miniblog.v1.PostService.ListPost:input_type -> miniblog.v1.ListPostRequest
+	14, // 14: miniblog.v1.UserService.CreateUser:output_type -> miniblog.v1.CreateUserResponse
+	15, // 15: miniblog.v1.UserService.Login:output_type -> miniblog.v1.LoginResponse
+	15, // 16: miniblog.v1.UserService.RefreshToken:output_type -> miniblog.v1.LoginResponse
+	16, // 17: miniblog.v1.UserService.Logout:output_type -> miniblog.v1.LogoutResponse
+	17, // 18: miniblog.v1.UserService.ChangePassword:output_type -> miniblog.v1.ChangePasswordResponse
+	18, // 19: miniblog.v1.UserService.GetUser:output_type -> miniblog.v1.UserInfo
+	19, // 20: miniblog.v1.UserService.UpdateUser:output_type -> miniblog.v1.UpdateUserResponse
+	20, // 21: miniblog.v1.UserService.DeleteUser:output_type -> miniblog.v1.DeleteUserResponse
+	21, // 22: miniblog.v1.UserService.ListUser:output_type -> miniblog.v1.ListUserResponse
+	22, // 23: miniblog.v1.PostService.CreatePost:output_type -> miniblog.v1.CreatePostResponse
+	23, // 24: miniblog.v1.PostService.GetPost:output_type -> miniblog.v1.PostInfo
+	24, // 25: miniblog.v1.PostService.UpdatePost:output_type -> miniblog.v1.UpdatePostResponse
+	25, // 26: miniblog.v1.PostService.DeletePost:output_type -> miniblog.v1.DeletePostResponse
+	26, // 27: miniblog.v1.PostService.ListPost:output_type -> miniblog.v1.ListPostResponse
+	14, // [14:28] is the sub-list for method output_type
+	0,  // [0:14] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_miniblog_v1_miniblog_proto_init() }
+func file_miniblog_v1_miniblog_proto_init() {
+	if File_miniblog_v1_miniblog_proto != nil {
+		return
+	}
+	file_miniblog_v1_post_proto_init()
+	file_miniblog_v1_user_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_miniblog_v1_miniblog_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_miniblog_v1_miniblog_proto_goTypes,
+		DependencyIndexes: file_miniblog_v1_miniblog_proto_depIdxs,
+	}.Build()
+	File_miniblog_v1_miniblog_proto = out.File
+	file_miniblog_v1_miniblog_proto_rawDesc = nil
+	file_miniblog_v1_miniblog_proto_goTypes = nil
+	file_miniblog_v1_miniblog_proto_depIdxs = nil
+}
@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RedisOptions 定义 Redis 数据库的选项.
+type RedisOptions struct {
+	Addr     string
+	Password string
+	Database int
+}
+
+// NewRedis 使用给定的选项创建一个新的 Redis 客户端，并检查 Redis 是否可以连接.
+func NewRedis(opts *RedisOptions) (*goredis.Client, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     opts.Addr,
+		Password: opts.Password,
+		DB:       opts.Database,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -3,29 +3,69 @@ package token
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 // Config 包括 token 包的配置选项.
 type Config struct {
-	key         string
-	identityKey string
+	key           string
+	identityKey   string
+	accessExpire  time.Duration
+	refreshExpire time.Duration
 }
 
-// ErrMissingHeader 表示 `Authorization` 请求头为空.
-var ErrMissingHeader = errors.New("the length of the `Authorization` header is zero")
+const (
+	// TypeAccess 表示访问令牌，用于请求需要认证的接口.
+	TypeAccess = "access"
+
+	// TypeRefresh 表示刷新令牌，只能用于换取新的令牌对.
+	TypeRefresh = "refresh"
+
+	// typeKey 是 claims 中保存令牌类型的键.
+	typeKey = "typ"
+)
+
+var (
+	// ErrMissingHeader 表示 `Authorization` 请求头为空.
+	ErrMissingHeader = errors.New("the length of the `Authorization` header is zero")
+
+	// ErrTokenType 表示令牌类型与期望的不一致，例如使用刷新令牌访问接口.
+	ErrTokenType = errors.New("unexpected token type")
+)
 
 var (
-	config = Config{"Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", "identityKey"}
+	config = Config{"Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5", "identityKey", 2 * time.Hour, 7 * 24 * time.Hour}
 	once   sync.Once
 )
 
+// Claims 是从令牌中解析出的信息.
+type Claims struct {
+	// Identity 是令牌的主题，即 identityKey 对应的值.
+	Identity string
+	// ID 是令牌的唯一标识 jti，用于吊销单个令牌.
+	ID        string
+	Type      string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Pair 是一次签发的访问令牌和刷新令牌.
+type Pair struct {
+	AccessToken  string
+	RefreshToken string
+	// ExpiresAt 是访问令牌的过期时间.
+	ExpiresAt time.Time
+}
+
 // Init 设置包级别的配置 config, config 会用于本包后面的 token 签发和解析.
-func Init(key string, identityKey string) {
+// accessExpire 和 refreshExpire 为 0 时使用默认的有效期.
+func Init(key string, identityKey string, accessExpire, refreshExpire time.Duration) {
 	once.Do(func() {
 		if key != "" {
 			config.key = key
@@ -33,11 +73,26 @@ func Init(key string, identityKey string) {
 		if identityKey != "" {
 			config.identityKey = identityKey
 		}
+		if accessExpire > 0 {
+			config.accessExpire = accessExpire
+		}
+		if refreshExpire > 0 {
+			config.refreshExpire = refreshExpire
+		}
 	})
 }
 
+// MaxLifetime 返回签发的令牌中最长的有效期.
+func MaxLifetime() time.Duration {
+	if config.refreshExpire > config.accessExpire {
+		return config.refreshExpire
+	}
+
+	return config.accessExpire
+}
+
 // Parse 使用指定的密钥 key 解析 token，解析成功返回 token 上下文，否则报错.
-func Parse(tokenString string, key string) (string, error) {
+func Parse(tokenString string, key string) (*Claims, error) {
 	// 解析 token
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// 确保 token 加密算法是预期的加密算法
@@ -49,45 +104,98 @@ func Parse(tokenString string, key string) (string, error) {
 	})
 	// 解析失败
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var identityKey string
-	// 如果解析成功，从 token 中取出 token 的主题
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		identityKey = claims[config.identityKey].(string)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenMalformed
 	}
 
-	return identityKey, nil
+	// 如果解析成功，从 token 中取出 token 的主题及吊销需要的信息
+	identity, _ := claims[config.identityKey].(string)
+	jti, _ := claims["jti"].(string)
+	typ, _ := claims[typeKey].(string)
+	if identity == "" || jti == "" {
+		return nil, jwt.ErrTokenMalformed
+	}
+
+	return &Claims{
+		Identity:  identity,
+		ID:        jti,
+		Type:      typ,
+		IssuedAt:  numericTime(claims["iat"]),
+		ExpiresAt: numericTime(claims["exp"]),
+	}, nil
 }
 
-// ParseRequest 从请求头中获取令牌，并将其传递给 Parse 函数以解析令牌.
-func ParseRequest(c *gin.Context) (string, error) {
+// ParseRequest 从请求头中获取访问令牌，并将其传递给 Parse 函数以解析令牌.
+func ParseRequest(c *gin.Context) (*Claims, error) {
 	header := c.Request.Header.Get("Authorization")
-
-	fmt.Println("header: ", header)
 	if len(header) == 0 {
-		return "", ErrMissingHeader
+		return nil, ErrMissingHeader
 	}
 
 	var t string
 	// 从请求头中取出 token
 	fmt.Sscanf(header, "Bearer %s", &t)
 
-	return Parse(t, config.key)
+	return ParseType(t, TypeAccess)
 }
 
-// Sign 使用 jwtSecret 签发 token，token 的 claims 中会存放传入的 subject.
-func Sign(identityKey string) (tokenString string, err error) {
+// ParseType 解析令牌并确保令牌类型为 typ.
+func ParseType(tokenString string, typ string) (*Claims, error) {
+	claims, err := Parse(tokenString, config.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Type != typ {
+		return nil, ErrTokenType
+	}
+
+	return claims, nil
+}
+
+// Sign 使用 jwtSecret 签发访问令牌和刷新令牌，token 的 claims 中会存放传入的 subject.
+func Sign(identityKey string) (*Pair, error) {
+	now := time.Now()
+
+	access, err := sign(identityKey, TypeAccess, now, now.Add(config.accessExpire))
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := sign(identityKey, TypeRefresh, now, now.Add(config.refreshExpire))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pair{AccessToken: access, RefreshToken: refresh, ExpiresAt: now.Add(config.accessExpire)}, nil
+}
+
+func sign(identityKey, typ string, now, expiresAt time.Time) (string, error) {
 	// Token 的内容
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		config.identityKey: identityKey,
-		"nbf":              time.Now().Unix(),
-		"iat":              time.Now().Unix(),
-		"exp":              time.Now().Add(100000 * time.Hour).Unix(),
+		typeKey:            typ,
+		"jti":              uuid.New().String(),
+		"nbf":              now.Unix(),
+		// iat 精确到毫秒，以便与修改密码的时间比较
+		"iat": float64(now.UnixMilli()) / 1000,
+		"exp": expiresAt.Unix(),
 	})
+
 	// 签发 token
-	tokenString, err = token.SignedString([]byte(config.key))
+	return token.SignedString([]byte(config.key))
+}
+
+// numericTime 将 JWT 中以秒为单位的时间转换为 time.Time.
+func numericTime(v interface{}) time.Time {
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}
+	}
 
-	return
+	return time.UnixMilli(int64(math.Round(f * 1000)))
 }
@@ -0,0 +1,146 @@
+package token
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+const testKey = "Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5"
+
+func init() {
+	Init(testKey, "X-Username", time.Hour, 24*time.Hour)
+}
+
+func TestSignAndParse(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	pair, err := Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	access, err := ParseType(pair.AccessToken, TypeAccess)
+	if err != nil {
+		t.Fatalf("ParseType(access) error = %v", err)
+	}
+	refresh, err := ParseType(pair.RefreshToken, TypeRefresh)
+	if err != nil {
+		t.Fatalf("ParseType(refresh) error = %v", err)
+	}
+
+	for _, claims := range []*Claims{access, refresh} {
+		if claims.Identity != "colin" {
+			t.Errorf("Identity = %q, want %q", claims.Identity, "colin")
+		}
+		if claims.ID == "" {
+			t.Errorf("ID is empty")
+		}
+		// iat 精确到毫秒，不能早于签发前的时间
+		if claims.IssuedAt.Before(before) || claims.IssuedAt.After(time.Now()) {
+			t.Errorf("IssuedAt = %v, want between %v and now", claims.IssuedAt, before)
+		}
+	}
+	if access.ID == refresh.ID {
+		t.Errorf("access and refresh tokens share jti %q", access.ID)
+	}
+	if got := refresh.ExpiresAt.Sub(access.ExpiresAt); got < 22*time.Hour {
+		t.Errorf("refresh token expires %v after access token, want about 23h", got)
+	}
+	if !pair.ExpiresAt.Truncate(time.Second).Equal(access.ExpiresAt) {
+		t.Errorf("Pair.ExpiresAt = %v, want %v", pair.ExpiresAt, access.ExpiresAt)
+	}
+	if MaxLifetime() != 24*time.Hour {
+		t.Errorf("MaxLifetime() = %v, want %v", MaxLifetime(), 24*time.Hour)
+	}
+}
+
+func TestParseTypeMismatch(t *testing.T) {
+	pair, err := Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if _, err := ParseType(pair.RefreshToken, TypeAccess); err != ErrTokenType {
+		t.Errorf("ParseType(refresh, access) error = %v, want %v", err, ErrTokenType)
+	}
+	if _, err := ParseType(pair.AccessToken, TypeRefresh); err != ErrTokenType {
+		t.Errorf("ParseType(access, refresh) error = %v, want %v", err, ErrTokenType)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	pair, err := Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+		s, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return s
+	}
+	now := time.Now()
+	valid := jwt.MapClaims{"X-Username": "colin", "typ": TypeAccess, "jti": "id", "iat": now.Unix(), "exp": now.Add(time.Hour).Unix()}
+	expired := jwt.MapClaims{"X-Username": "colin", "typ": TypeAccess, "jti": "id", "iat": now.Add(-2 * time.Hour).Unix(), "exp": now.Add(-time.Hour).Unix()}
+	noJTI := jwt.MapClaims{"X-Username": "colin", "typ": TypeAccess, "exp": now.Add(time.Hour).Unix()}
+	noIdentity := jwt.MapClaims{"typ": TypeAccess, "jti": "id", "exp": now.Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"tampered", pair.AccessToken[:len(pair.AccessToken)-2] + "xx"},
+		{"other key", sign(jwt.SigningMethodHS256, []byte("other-key"), valid)},
+		{"alg none", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid)},
+		{"expired", sign(jwt.SigningMethodHS256, []byte(testKey), expired)},
+		{"without jti", sign(jwt.SigningMethodHS256, []byte(testKey), noJTI)},
+		{"without identity", sign(jwt.SigningMethodHS256, []byte(testKey), noIdentity)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseType(tt.token, TypeAccess); err == nil {
+				t.Errorf("ParseType() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	pair, err := Sign("colin")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{"access token", "Bearer " + pair.AccessToken, nil},
+		{"missing header", "", ErrMissingHeader},
+		{"refresh token", "Bearer " + pair.RefreshToken, ErrTokenType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("Authorization", tt.header)
+			}
+
+			claims, err := ParseRequest(c)
+			if err != tt.wantErr {
+				t.Fatalf("ParseRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claims.Identity != "colin" {
+				t.Errorf("Identity = %q, want %q", claims.Identity, "colin")
+			}
+		})
+	}
+}